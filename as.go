@@ -2,8 +2,11 @@ package autoinit
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"strings"
+
+	"github.com/rs/zerolog"
 )
 
 // Filter interface for additional search constraints in As pattern
@@ -26,9 +29,23 @@ type jsonTagFilter struct {
 }
 
 func (f jsonTagFilter) Matches(field reflect.Value, fieldType *reflect.StructField) bool {
-	jsonTag := fieldType.Tag.Get("json")
-	tagName := strings.Split(jsonTag, ",")[0]
-	return tagName == f.tag
+	name, ok := jsonTagName(fieldType)
+	return ok && name == f.tag
+}
+
+// jsonTagName returns the JSON name declared by a field's json tag, following
+// encoding/json: `json:"-"` excludes the field (no name) and an empty name, as
+// in `json:",omitempty"`, means the field name. Fields without a json tag have no name.
+func jsonTagName(fieldType *reflect.StructField) (string, bool) {
+	jsonTag, ok := fieldType.Tag.Lookup("json")
+	if !ok || jsonTag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(jsonTag, ",")
+	if name == "" {
+		name = fieldType.Name
+	}
+	return name, true
 }
 
 // customTagFilter matches components by custom tag key and value
@@ -42,12 +59,113 @@ func (f customTagFilter) Matches(field reflect.Value, fieldType *reflect.StructF
 	return tagValue == f.value
 }
 
+// notFilter inverts the result of another filter
+type notFilter struct {
+	filter Filter
+}
+
+func (f notFilter) Matches(field reflect.Value, fieldType *reflect.StructField) bool {
+	return !f.filter.Matches(field, fieldType)
+}
+
+// orFilter matches when any of its filters match
+type orFilter struct {
+	filters []Filter
+}
+
+func (f orFilter) Matches(field reflect.Value, fieldType *reflect.StructField) bool {
+	for _, filter := range f.filters {
+		if filter.Matches(field, fieldType) {
+			return true
+		}
+	}
+	return false
+}
+
+// capabilityFilter matches components implementing a lifecycle interface
+type capabilityFilter struct {
+	capability Capability
+}
+
+func (f capabilityFilter) Matches(field reflect.Value, fieldType *reflect.StructField) bool {
+	return implementsCapability(field, f.capability)
+}
+
+// embeddedMatchFilter is the marker returned by MatchEmbedded. It does not
+// constrain candidates; As removes it from the filters and widens type matching.
+type embeddedMatchFilter struct{}
+
+func (embeddedMatchFilter) Matches(field reflect.Value, fieldType *reflect.StructField) bool {
+	return true
+}
+
+// MatchEmbedded widens the type matching of As to components that embed the
+// target type, directly or through other embedded structs, for embedding-based
+// composition ("anything that embeds BaseComponent"). The embedded value is
+// returned, addressed within its component, so it fits the target:
+//
+//	var base *BaseComponent
+//	As(ctx, self, parent, &base, MatchEmbedded()) // &server.BaseComponent
+//
+// Candidates are considered in the usual search order, whether they are of the
+// target type or embed it; other filters apply to the field holding the
+// component. Like other fields, the embedded type must be exported.
+//
+// MatchEmbedded changes how As matches types rather than which fields pass,
+// so it must be passed to As directly: Not and Or panic if given it.
+func MatchEmbedded() Filter {
+	return embeddedMatchFilter{}
+}
+
+// usableMatchFilter is the marker AsInterface adds to its filters. Like
+// embeddedMatchFilter it does not constrain fields itself; As removes it and
+// skips candidates holding a nil pointer.
+type usableMatchFilter struct{}
+
+func (usableMatchFilter) Matches(field reflect.Value, fieldType *reflect.StructField) bool {
+	return true
+}
+
+// asMode holds the search options passed to As as marker filters
+type asMode struct {
+	embedding bool // components embedding the target match too (MatchEmbedded)
+	usable    bool // candidates holding a nil pointer are skipped (AsInterface)
+}
+
+// match is matchCandidate under the mode
+func (m asMode) match(candidate reflect.Value, target reflect.Type) (reflect.Value, bool) {
+	match, ok := matchCandidate(candidate, target, m.embedding)
+	if !ok || (m.usable && holdsNil(match)) {
+		return reflect.Value{}, false
+	}
+	return match, true
+}
+
+// splitMarkers removes the marker filters from filters and returns the mode
+// they select
+func splitMarkers(filters []Filter) ([]Filter, asMode) {
+	var mode asMode
+	var rest []Filter
+	for _, filter := range filters {
+		switch filter.(type) {
+		case embeddedMatchFilter:
+			mode.embedding = true
+		case usableMatchFilter:
+			mode.usable = true
+		default:
+			rest = append(rest, filter)
+		}
+	}
+	return rest, mode
+}
+
 // WithFieldName creates a filter that matches by field name
 func WithFieldName(name string) Filter {
 	return fieldNameFilter{name: name}
 }
 
-// WithJSONTag creates a filter that matches by JSON tag value
+// WithJSONTag creates a filter that matches by JSON tag value.
+// Fields tagged `json:"-"` never match; `json:",omitempty"` matches the field name.
 func WithJSONTag(tag string) Filter {
 	return jsonTagFilter{tag: tag}
 }
@@ -57,6 +175,45 @@ func WithTag(key, value string) Filter {
 	return customTagFilter{key: key, value: value}
 }
 
+// WithCapability creates a filter that matches components implementing the
+// lifecycle interface named by c, e.g. to find a Cache that can be closed:
+//
+//	var cache *Cache
+//	As(ctx, self, parent, &cache, WithCapability(CapabilityClose))
+//
+// Methods with pointer receivers count for struct fields, as for
+// ComponentCapabilities.
+func WithCapability(c Capability) Filter {
+	return capabilityFilter{capability: c}
+}
+
+// Not creates a filter that matches when f does not match.
+// Combined with the implicit AND of As filters and Or, this allows arbitrary
+// filter expressions, e.g. "any Database NOT named PrimaryDB":
+//
+//	As(ctx, self, parent, &db, Not(WithFieldName("PrimaryDB")))
+func Not(f Filter) Filter {
+	rejectMarkers("Not", f)
+	return notFilter{filter: f}
+}
+
+// Or creates a filter that matches when at least one of the given filters matches.
+// An Or with no filters never matches.
+func Or(filters ...Filter) Filter {
+	rejectMarkers("Or", filters...)
+	return orFilter{filters: filters}
+}
+
+// rejectMarkers panics if filters passed to a combinator include
+// MatchEmbedded, which would always match there instead of widening the search
+func rejectMarkers(combinator string, filters ...Filter) {
+	for _, filter := range filters {
+		if _, ok := filter.(embeddedMatchFilter); ok {
+			panic(fmt.Sprintf("autoinit: MatchEmbedded cannot be used inside %s; pass it to As directly", combinator))
+		}
+	}
+}
+
 // As attempts to find a dependency matching the target type AND all provided filters.
 // All filters are applied conjunctively (AND logic) to narrow down candidates.
 // This follows the Go CDK pattern for escape hatches with additional filtering capabilities.
@@ -97,20 +254,29 @@ func As[T any](ctx context.Context, self, parent interface{}, target *T, filters
 
 	// Type-safe assignment
 	resultValue := reflect.ValueOf(result)
-	if resultValue.Type().AssignableTo(targetType) {
-		targetElem.Set(resultValue)
-		return true
-	}
-
-	// For interface types, check if the result implements the interface
-	if targetType.Kind() == reflect.Interface && resultValue.Type().Implements(targetType) {
+	if resultValue.Type().AssignableTo(targetType) ||
+		// For interface types, check if the result implements the interface
+		(targetType.Kind() == reflect.Interface && resultValue.Type().Implements(targetType)) {
 		targetElem.Set(resultValue)
+		recordDependency(ctx, self, resultValue, describeSearch("As", targetType, describeFilters(filters)...))
 		return true
 	}
 
 	return false
 }
 
+// recordDependency notes in the current run that self discovered dependency
+// with the search described by via
+func recordDependency(ctx context.Context, self interface{}, dependency reflect.Value, via string) {
+	if self == nil {
+		return
+	}
+	if state := getRunState(ctx); state != nil {
+		state.recordDependency(reflect.ValueOf(self), dependency)
+	}
+	recordDiscovery(ctx, self, dependency, via)
+}
+
 // MustAs is like As but panics if the dependency is not found.
 // Use this when a dependency is required for the component to function.
 func MustAs[T any](ctx context.Context, self, parent interface{}, target *T, filters ...Filter) {
@@ -120,42 +286,134 @@ func MustAs[T any](ctx context.Context, self, parent interface{}, target *T, fil
 	}
 }
 
+// AsInterface is like As but specialized for interface targets. In addition to
+// assignability it confirms that the discovered value can actually serve calls:
+// I must be an interface type and the found value must not be a typed nil
+// (e.g. a nil *Logger stored in an interface), whose methods would panic.
+// Typed nils are passed over, so the search goes on to the next candidate.
+//
+// Usage:
+//
+//	var logger Logger
+//	if AsInterface(ctx, self, parent, &logger) {
+//	    logger.Log("ready")
+//	}
+//
+// Returns false and leaves target unchanged if no usable implementation is found.
+func AsInterface[I any](ctx context.Context, self, parent interface{}, target *I, filters ...Filter) bool {
+	if target == nil {
+		return false
+	}
+
+	interfaceType := reflect.TypeOf(target).Elem()
+	if interfaceType.Kind() != reflect.Interface {
+		return false
+	}
+
+	// Typed nils are skipped during the search, so a usable implementation
+	// found later is returned instead
+	search := append(append([]Filter{}, filters...), usableMatchFilter{})
+	result := asSearch(ctx, self, parent, interfaceType, search...)
+	if result == nil {
+		return false
+	}
+	found, ok := result.(I)
+	if !ok {
+		return false
+	}
+	*target = found
+	recordDependency(ctx, self, reflect.ValueOf(result), describeSearch("AsInterface", interfaceType, describeFilters(filters)...))
+	return true
+}
+
+// isNilValue reports whether v holds a nil pointer, map, slice, func, chan or interface
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// holdsNil reports whether v is nil or an interface holding a nil value
+func holdsNil(v reflect.Value) bool {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return isNilValue(v)
+}
+
 // asSearch performs the actual search with conjunctive filtering
 func asSearch(ctx context.Context, self, parent interface{}, targetType reflect.Type, filters ...Filter) interface{} {
+	filters, mode := splitMarkers(filters)
+
 	// First check if we have a TestContext in the context
 	if tc := getTestContext(ctx); tc != nil {
 		tc.mu.RLock()
 		defer tc.mu.RUnlock()
 
-		// Try to find by type first
-		if candidates, exists := tc.dependencies[targetType]; exists {
-			for _, candidate := range candidates {
-				// Apply filters if any
-				if len(filters) == 0 {
-					return candidate
-				}
-				// For TestContext, we'll skip filter matching for now
-				// In a full implementation, you'd apply filters here
-				return candidate
-			}
+		// Try to find by type first, honoring overrides.
+		// For TestContext, we'll skip filter matching for now
+		// In a full implementation, you'd apply filters here
+		if candidate, ok := tc.lookup(targetType); ok && !(mode.usable && holdsNil(reflect.ValueOf(candidate))) {
+			return candidate
 		}
 	}
 
-	if parent == nil {
+	logger := runLogger(ctx)
+	if parent == nil && len(additionalRoots(ctx)) == 0 {
+		logger.Debug().
+			Str("target", targetType.String()).
+			Msg("As: no parent to search")
 		return nil
 	}
 
-	// Search in parent's fields
-	return searchInStruct(parent, self, targetType, filters)
+	// Search in parent's fields, then in any additional roots
+	var result interface{}
+	if parent != nil {
+		result = searchInStruct(parent, self, targetType, mode, filters, logger)
+		if result != nil {
+			recordAmbiguousMatch(ctx, parent, self, targetType, mode, filters)
+		}
+	}
+	if result == nil {
+		result = searchRootsAs(ctx, self, targetType, mode, filters, logger)
+	}
+	if result == nil {
+		logger.Debug().
+			Str("target", targetType.String()).
+			Str("parent", fmt.Sprintf("%T", parent)).
+			Int("filters", len(filters)).
+			Msg("As: no matching dependency found")
+	}
+	return result
+}
+
+// logRejected records at debug level why a candidate was not returned by a search
+func logRejected(logger *zerolog.Logger, search string, owner reflect.Type, field string, target reflect.Type, reason string) {
+	logger.Debug().
+		Str("in", owner.String()).
+		Str("field", field).
+		Str("target", target.String()).
+		Str("reason", reason).
+		Msg(search + ": rejected candidate")
 }
 
 // searchInStruct searches for matching components in a struct
-func searchInStruct(parent, exclude interface{}, targetType reflect.Type, filters []Filter) interface{} {
+func searchInStruct(parent, exclude interface{}, targetType reflect.Type, mode asMode, filters []Filter, logger *zerolog.Logger) interface{} {
 	v := reflect.ValueOf(parent)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
+	return searchInStructValue(v, exclude, targetType, mode, filters, logger)
+}
 
+// searchInStructValue searches struct value v. Working on the reflect.Value keeps
+// embedded structs addressable and lets us descend into unexported embedded
+// structs, whose exported fields are promoted and remain accessible. With
+// mode.embedding, components embedding targetType match too (see MatchEmbedded).
+func searchInStructValue(v reflect.Value, exclude interface{}, targetType reflect.Type, mode asMode, filters []Filter, logger *zerolog.Logger) interface{} {
 	if v.Kind() != reflect.Struct {
 		return nil
 	}
@@ -168,39 +426,56 @@ func searchInStruct(parent, exclude interface{}, targetType reflect.Type, filter
 
 		// Skip unexported fields
 		if !field.CanInterface() {
+			logRejected(logger, "As", t, fieldType.Name, targetType, "unexported")
 			continue
 		}
 
 		// Skip self
 		fieldInterface := field.Interface()
-		if fieldInterface == exclude {
+		if sameComponent(fieldInterface, exclude) {
+			logRejected(logger, "As", t, fieldType.Name, targetType, "self")
 			continue
 		}
 
 		// Skip nil pointers
 		if field.Kind() == reflect.Ptr && field.IsNil() {
+			logRejected(logger, "As", t, fieldType.Name, targetType, "nil")
 			continue
 		}
 
 		// Check if this field matches our type requirement
-		if !matchesTargetType(field, targetType) {
+		match, ok := matchCandidate(field, targetType, mode.embedding)
+		if !ok {
+			logRejected(logger, "As", t, fieldType.Name, targetType, "type mismatch")
+			continue
+		}
+		if mode.usable && holdsNil(match) {
+			logRejected(logger, "As", t, fieldType.Name, targetType, "typed nil")
 			continue
 		}
 
 		// Apply all filters conjunctively
 		if !matchesAllFilters(field, &fieldType, filters) {
+			logRejected(logger, "As", t, fieldType.Name, targetType, "filter mismatch")
 			continue
 		}
 
 		// Found a match!
-		// For value types, return a pointer if the field is addressable
-		if field.Kind() != reflect.Ptr && field.CanAddr() {
-			return field.Addr().Interface()
+		logger.Debug().
+			Str("in", t.String()).
+			Str("field", fieldType.Name).
+			Str("target", targetType.String()).
+			Msg("As: found dependency")
+		// For value types, return a pointer if the field is addressable. Interface
+		// fields return their value.
+		if match.Kind() != reflect.Ptr && match.Kind() != reflect.Interface && match.CanAddr() {
+			return match.Addr().Interface()
 		}
-		return fieldInterface
+		return match.Interface()
 	}
 
-	// Also search in slices
+	// Then search collections and embedded structs. Embedded structs are searched
+	// with the same two passes, so their own slices and maps are covered too.
 	for i := 0; i < v.NumField(); i++ {
 		field := v.Field(i)
 		fieldType := t.Field(i)
@@ -210,15 +485,17 @@ func searchInStruct(parent, exclude interface{}, targetType reflect.Type, filter
 				elem := field.Index(j)
 				if elem.CanInterface() {
 					elemInterface := elem.Interface()
-					if elemInterface != exclude && matchesTargetType(elem, targetType) {
+					if match, ok := mode.match(elem, targetType); ok && !sameComponent(elemInterface, exclude) {
 						// For slice elements, we need to check filters differently
 						// since they don't have field metadata
-						if len(filters) == 0 {
+						if len(filters) > 0 {
+							logRejected(logger, "As", t, fmt.Sprintf("%s[%d]", fieldType.Name, j), targetType, "filters do not apply to collection elements")
+						} else {
 							// No additional filters, type match is enough
-							if elem.Kind() != reflect.Ptr && elem.CanAddr() {
-								return elem.Addr().Interface()
+							if match.Kind() != reflect.Ptr && match.CanAddr() {
+								return match.Addr().Interface()
 							}
-							return elemInterface
+							return match.Interface()
 						}
 					}
 				}
@@ -231,19 +508,22 @@ func searchInStruct(parent, exclude interface{}, targetType reflect.Type, filter
 				val := field.MapIndex(key)
 				if val.CanInterface() {
 					valInterface := val.Interface()
-					if valInterface != exclude && matchesTargetType(val, targetType) {
-						if len(filters) == 0 {
+					if match, ok := mode.match(val, targetType); ok && !sameComponent(valInterface, exclude) {
+						if len(filters) > 0 {
+							logRejected(logger, "As", t, fmt.Sprintf("%s[%v]", fieldType.Name, key), targetType, "filters do not apply to collection elements")
+						} else {
 							// Map values are not addressable
-							return valInterface
+							return match.Interface()
 						}
 					}
 				}
 			}
 		}
 
-		// Search in embedded structs
-		if fieldType.Anonymous && (field.Kind() == reflect.Struct || (field.Kind() == reflect.Ptr && field.Elem().Kind() == reflect.Struct)) {
-			if result := searchInStruct(field.Interface(), exclude, targetType, filters); result != nil {
+		// Search in embedded structs (direct fields and collections), whose fields
+		// keep their own tags
+		if fieldType.Anonymous {
+			if result := searchInStructValue(embeddedStruct(field), exclude, targetType, mode, filters, logger); result != nil {
 				return result
 			}
 		}
@@ -252,44 +532,109 @@ func searchInStruct(parent, exclude interface{}, targetType reflect.Type, filter
 	return nil
 }
 
-// matchesTargetType checks if a value matches the target type
-func matchesTargetType(field reflect.Value, targetType reflect.Type) bool {
-	if !field.IsValid() || !field.CanInterface() {
+// embeddedStruct returns the struct held by an embedded field, dereferencing
+// embedded pointers and unwrapping embedded interfaces. The result is invalid
+// for nil pointers and nil interfaces.
+func embeddedStruct(field reflect.Value) reflect.Value {
+	if field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return reflect.Value{}
+		}
+		field = field.Elem()
+	}
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return reflect.Value{}
+		}
+		return field.Elem()
+	}
+	return field
+}
+
+// matchCandidate returns the value a search for target yields for candidate:
+// the candidate itself if it matches, or with embedding the nearest component
+// embedded in it that matches
+func matchCandidate(candidate reflect.Value, target reflect.Type, embedding bool) (reflect.Value, bool) {
+	if TypeMatches(candidate, target) {
+		return candidate, true
+	}
+	if !embedding {
+		return reflect.Value{}, false
+	}
+	return embeddedMatch(candidate, target)
+}
+
+// embeddedMatch searches the embedded fields of the struct held by candidate,
+// breadth-first like Go's promotion rules, for one that matches target
+func embeddedMatch(candidate reflect.Value, target reflect.Type) (reflect.Value, bool) {
+	v := embeddedStruct(candidate)
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	// Embedded pointers can make a type embed itself; visit each type once
+	seen := map[reflect.Type]bool{v.Type(): true}
+	level := []reflect.Value{v}
+	for len(level) > 0 {
+		var next []reflect.Value
+		for _, s := range level {
+			t := s.Type()
+			for i := 0; i < s.NumField(); i++ {
+				if !t.Field(i).Anonymous {
+					continue
+				}
+				field := s.Field(i)
+				if TypeMatches(field, target) {
+					return field, true
+				}
+				if inner := embeddedStruct(field); inner.IsValid() && inner.Kind() == reflect.Struct && !seen[inner.Type()] {
+					seen[inner.Type()] = true
+					next = append(next, inner)
+				}
+			}
+		}
+		level = next
+	}
+	return reflect.Value{}, false
+}
+
+// TypeMatches reports whether candidate can satisfy a lookup for target. It is
+// the matching rule used by As and ComponentFinder: the candidate's dynamic type
+// matches when it equals target, when the two differ only by one level of
+// pointer (T vs *T), or, for interface targets, when it or a pointer to it
+// implements the interface. Invalid, unexported and nil interface values never match.
+func TypeMatches(candidate reflect.Value, target reflect.Type) bool {
+	if !candidate.IsValid() || !candidate.CanInterface() || target == nil {
 		return false
 	}
 
-	fieldInterface := field.Interface()
-	fieldType := reflect.TypeOf(fieldInterface)
+	candidateType := reflect.TypeOf(candidate.Interface())
+	if candidateType == nil {
+		// Nil interface value
+		return false
+	}
 
 	// Direct type match
-	if fieldType == targetType {
+	if candidateType == target {
 		return true
 	}
 
-	// If target is a pointer type, check if field type matches
-	if targetType.Kind() == reflect.Ptr && targetType.Elem() == fieldType {
+	// If target is a pointer type, check if the candidate is its element type
+	if target.Kind() == reflect.Ptr && target.Elem() == candidateType {
 		return true
 	}
 
-	// If field is a pointer, check if its element type matches
-	if fieldType.Kind() == reflect.Ptr && fieldType.Elem() == targetType {
+	// If the candidate is a pointer, check if its element type matches
+	if candidateType.Kind() == reflect.Ptr && candidateType.Elem() == target {
 		return true
 	}
 
-	// Check if both are pointers to the same type
-	if targetType.Kind() == reflect.Ptr && fieldType.Kind() == reflect.Ptr {
-		if targetType.Elem() == fieldType.Elem() {
+	// Check interface implementation, including by a pointer to the candidate
+	if target.Kind() == reflect.Interface {
+		if candidateType.Implements(target) {
 			return true
 		}
-	}
-
-	// Check interface implementation
-	if targetType.Kind() == reflect.Interface {
-		if fieldType.Implements(targetType) {
-			return true
-		}
-		// Check if pointer type implements the interface
-		if fieldType.Kind() != reflect.Ptr && reflect.PtrTo(fieldType).Implements(targetType) {
+		if candidateType.Kind() != reflect.Ptr && reflect.PtrTo(candidateType).Implements(target) {
 			return true
 		}
 	}
@@ -315,3 +660,13 @@ func AsType[T any](ctx context.Context, self, parent interface{}) (T, bool) {
 	ok := As(ctx, self, parent, &target)
 	return target, ok
 }
+
+// AsTypeFiltered is like AsType but narrows the search with filters, which are
+// applied conjunctively as in As
+//
+//	primary, ok := AsTypeFiltered[*Database](ctx, self, parent, WithFieldName("PrimaryDB"))
+func AsTypeFiltered[T any](ctx context.Context, self, parent interface{}, filters ...Filter) (T, bool) {
+	var target T
+	ok := As(ctx, self, parent, &target, filters...)
+	return target, ok
+}
@@ -2,6 +2,7 @@ package autoinit_test
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/telnet2/autoinit"
@@ -152,6 +153,57 @@ func TestAsWithJSONTagFilter(t *testing.T) {
 	}
 }
 
+// TestAsWithJSONTagEdgeCases tests json:"-" and empty-name json tags
+func TestAsWithJSONTagEdgeCases(t *testing.T) {
+	type App struct {
+		Excluded *TestDatabase `json:"-"`
+		Untagged *TestDatabase
+		Cache    *TestDatabase `json:",omitempty"`
+		Named    *TestDatabase `json:"named,omitempty"`
+	}
+
+	app := &App{
+		Excluded: &TestDatabase{Name: "excluded"},
+		Untagged: &TestDatabase{Name: "untagged"},
+		Cache:    &TestDatabase{Name: "cache"},
+		Named:    &TestDatabase{Name: "named"},
+	}
+
+	ctx := context.Background()
+	testCases := []struct {
+		tag  string
+		want string // Expected database name, "" for no match
+	}{
+		{"-", ""},
+		{"", ""},
+		{"Cache", "cache"},
+		{"named", "named"},
+		{"Untagged", ""},
+	}
+
+	for _, tc := range testCases {
+		var db *TestDatabase
+		found := autoinit.As(ctx, nil, app, &db, autoinit.WithJSONTag(tc.tag))
+		if tc.want == "" {
+			if found {
+				t.Errorf("WithJSONTag(%q) should not match, got %q", tc.tag, db.Name)
+			}
+			continue
+		}
+		if !found || db.Name != tc.want {
+			t.Errorf("WithJSONTag(%q) = %v (found=%v); want %q", tc.tag, db, found, tc.want)
+		}
+	}
+
+	// The finder applies the same rules
+	if autoinit.FindByTag(ctx, nil, app, "-") != nil {
+		t.Error("FindByTag should never match json:\"-\"")
+	}
+	if db, ok := autoinit.FindByTag(ctx, nil, app, "Cache").(*TestDatabase); !ok || db.Name != "cache" {
+		t.Error("FindByTag should match an empty json name by field name")
+	}
+}
+
 // TestAsWithCustomTagFilter tests filtering by custom tags
 func TestAsWithCustomTagFilter(t *testing.T) {
 	type App struct {
@@ -462,3 +514,341 @@ func TestAsType(t *testing.T) {
 		t.Error("AsType should return false for non-existent type")
 	}
 }
+
+// TestAsTypeFiltered tests the generic convenience form with filters
+func TestAsTypeFiltered(t *testing.T) {
+	type App struct {
+		PrimaryDB   *TestDatabase `json:"primary"`
+		SecondaryDB *TestDatabase `json:"secondary"`
+	}
+
+	app := &App{
+		PrimaryDB:   &TestDatabase{Name: primaryDBName},
+		SecondaryDB: &TestDatabase{Name: "secondary"},
+	}
+
+	ctx := context.Background()
+
+	db, ok := autoinit.AsTypeFiltered[*TestDatabase](ctx, nil, app, autoinit.WithJSONTag("secondary"))
+	if !ok || db.Name != "secondary" {
+		t.Errorf("Expected secondary DB, got %v (ok=%v)", db, ok)
+	}
+
+	db, ok = autoinit.AsTypeFiltered[*TestDatabase](ctx, nil, app, autoinit.WithFieldName("PrimaryDB"))
+	if !ok || db.Name != primaryDBName {
+		t.Errorf("Expected primary DB, got %v (ok=%v)", db, ok)
+	}
+
+	_, ok = autoinit.AsTypeFiltered[*TestDatabase](ctx, nil, app, autoinit.WithFieldName("Missing"))
+	if ok {
+		t.Error("AsTypeFiltered should return false when no field matches the filters")
+	}
+}
+
+// TestAsNotFilter tests inverting a filter with Not
+func TestAsNotFilter(t *testing.T) {
+	type App struct {
+		PrimaryDB   *TestDatabase
+		SecondaryDB *TestDatabase
+	}
+
+	app := &App{
+		PrimaryDB:   &TestDatabase{Name: primaryDBName},
+		SecondaryDB: &TestDatabase{Name: "secondary"},
+	}
+
+	ctx := context.Background()
+
+	// Any Database NOT named PrimaryDB
+	var db *TestDatabase
+	if !autoinit.As(ctx, nil, app, &db, autoinit.Not(autoinit.WithFieldName("PrimaryDB"))) {
+		t.Fatal("Failed to find TestDatabase not named PrimaryDB")
+	}
+	if db.Name != "secondary" {
+		t.Errorf("Expected 'secondary', got '%s'", db.Name)
+	}
+
+	// Negating both names leaves nothing to match
+	var none *TestDatabase
+	if autoinit.As(ctx, nil, app, &none,
+		autoinit.Not(autoinit.WithFieldName("PrimaryDB")),
+		autoinit.Not(autoinit.WithFieldName("SecondaryDB"))) {
+		t.Error("Should not find a Database when all names are excluded")
+	}
+
+	// Double negation is the same as the original filter
+	var primary *TestDatabase
+	if !autoinit.As(ctx, nil, app, &primary, autoinit.Not(autoinit.Not(autoinit.WithFieldName("PrimaryDB")))) {
+		t.Fatal("Failed to find PrimaryDB with double negation")
+	}
+	if primary.Name != primaryDBName {
+		t.Errorf("Expected '%s', got '%s'", primaryDBName, primary.Name)
+	}
+}
+
+// TestAsOrFilter tests disjunctive filters combined with Not
+func TestAsOrFilter(t *testing.T) {
+	type App struct {
+		PrimaryDB   *TestDatabase
+		SecondaryDB *TestDatabase
+		BackupDB    *TestDatabase
+	}
+
+	app := &App{
+		PrimaryDB:   &TestDatabase{Name: primaryDBName},
+		SecondaryDB: &TestDatabase{Name: "secondary"},
+		BackupDB:    &TestDatabase{Name: "backup"},
+	}
+
+	ctx := context.Background()
+
+	var db *TestDatabase
+	if !autoinit.As(ctx, nil, app, &db,
+		autoinit.Or(autoinit.WithFieldName("SecondaryDB"), autoinit.WithFieldName("BackupDB")),
+		autoinit.Not(autoinit.WithFieldName("SecondaryDB"))) {
+		t.Fatal("Failed to find Database with Or/Not combination")
+	}
+	if db.Name != "backup" {
+		t.Errorf("Expected 'backup', got '%s'", db.Name)
+	}
+
+	var none *TestDatabase
+	if autoinit.As(ctx, nil, app, &none, autoinit.Or()) {
+		t.Error("Empty Or should never match")
+	}
+}
+
+// TestAsInterface tests interface discovery that rejects unusable values
+func TestAsInterface(t *testing.T) {
+	type App struct {
+		Logger *TestStructLogger
+		DB     *TestDatabase
+	}
+
+	app := &App{
+		Logger: &TestStructLogger{Name: "appLogger"},
+		DB:     &TestDatabase{Name: "mainDB"},
+	}
+
+	ctx := context.Background()
+
+	var logger TestLogger
+	if !autoinit.AsInterface(ctx, nil, app, &logger) {
+		t.Fatal("Failed to find TestLogger interface")
+	}
+	logger.Log("hello")
+	if len(app.Logger.Logs) != 1 {
+		t.Error("Logger interface method call failed")
+	}
+
+	// Concrete targets are rejected
+	var db *TestDatabase
+	if autoinit.AsInterface(ctx, nil, app, &db) {
+		t.Error("AsInterface should reject non-interface targets")
+	}
+}
+
+// TestAsInterfaceRejectsTypedNil tests that a typed nil in an interface field is not returned
+func TestAsInterfaceRejectsTypedNil(t *testing.T) {
+	type App struct {
+		Logger TestLogger
+	}
+
+	var nilLogger *TestStructLogger
+	app := &App{Logger: nilLogger}
+
+	ctx := context.Background()
+
+	var logger TestLogger
+	if autoinit.AsInterface(ctx, nil, app, &logger) {
+		t.Error("AsInterface should not return a typed nil")
+	}
+	if logger != nil {
+		t.Error("target should be left unchanged")
+	}
+}
+
+type usableLoggerConsumer struct {
+	Logger TestLogger
+	Found  bool
+}
+
+func (c *usableLoggerConsumer) Init(ctx context.Context, parent interface{}) error {
+	c.Found = autoinit.AsInterface(ctx, c, parent, &c.Logger)
+	return nil
+}
+
+// TestAsInterfaceSkipsTypedNil tests that a typed nil does not hide a usable
+// implementation later in the search, nor is recorded as a dependency
+func TestAsInterfaceSkipsTypedNil(t *testing.T) {
+	type App struct {
+		Stale    TestLogger
+		Live     TestLogger
+		Consumer *usableLoggerConsumer
+	}
+
+	var nilLogger *TestStructLogger
+	live := &TestStructLogger{Name: "live"}
+	app := &App{Stale: nilLogger, Live: live, Consumer: &usableLoggerConsumer{}}
+	report := &autoinit.InitReport{}
+	if err := autoinit.WithOptions(context.Background(), app, &autoinit.Options{Report: report}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !app.Consumer.Found || app.Consumer.Logger != TestLogger(live) {
+		t.Fatalf("expected the live logger, got %v", app.Consumer.Logger)
+	}
+	deps := report.DependenciesOf("Consumer")
+	if len(deps) != 1 || deps[0].ToString() != "Live" {
+		t.Errorf("expected a single dependency on Live, got %v", deps)
+	}
+}
+
+// Types for promoted-field discovery: the tagged fields live in an embedded struct
+type promotedStores struct {
+	Primary   TestDatabase  `json:"primary" role:"main"`
+	Secondary *TestDatabase `json:"secondary"`
+}
+
+type promotedConsumer struct {
+	ByJSON   *TestDatabase
+	ByTag    *TestDatabase
+	ByName   *TestDatabase
+	Finder   *TestDatabase
+	Lookedup bool
+}
+
+func (c *promotedConsumer) Init(ctx context.Context, parent interface{}) error {
+	c.Lookedup = true
+	autoinit.As(ctx, c, parent, &c.ByJSON, autoinit.WithJSONTag("secondary"))
+	autoinit.As(ctx, c, parent, &c.ByTag, autoinit.WithTag("role", "main"))
+	autoinit.As(ctx, c, parent, &c.ByName, autoinit.WithFieldName("Primary"))
+	if db, ok := autoinit.FindByTag(ctx, c, parent, primaryTag).(*TestDatabase); ok {
+		c.Finder = db
+	}
+	return nil
+}
+
+type promotedApp struct {
+	promotedStores
+	Consumer promotedConsumer
+}
+
+// TestAsPromotedFieldTags tests that filters see the tags of fields promoted from an embedded struct
+func TestAsPromotedFieldTags(t *testing.T) {
+	app := &promotedApp{}
+	app.Primary.Name = primaryDBName
+	app.Secondary = &TestDatabase{Name: "secondary"}
+
+	if err := autoinit.AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c := app.Consumer
+	if !c.Lookedup {
+		t.Fatal("consumer was not initialized")
+	}
+	if c.ByJSON != app.Secondary {
+		t.Errorf("WithJSONTag should find the promoted pointer field, got %v", c.ByJSON)
+	}
+	if c.ByTag != &app.Primary {
+		t.Errorf("WithTag should find the promoted value field by address, got %v", c.ByTag)
+	}
+	if c.ByName != &app.Primary {
+		t.Errorf("WithFieldName should find the promoted value field by address, got %v", c.ByName)
+	}
+	if c.Finder != &app.Primary {
+		t.Errorf("FindByTag should find the promoted value field by address, got %v", c.Finder)
+	}
+}
+
+// Types for discovery through collections held by an embedded struct
+type EmbeddedPool struct {
+	Databases []*TestDatabase
+	Caches    map[string]*TestCache
+}
+
+type poolConsumer struct {
+	DB    *TestDatabase
+	Cache *TestCache
+}
+
+func (c *poolConsumer) Init(ctx context.Context, parent interface{}) error {
+	autoinit.As(ctx, c, parent, &c.DB)
+	autoinit.As(ctx, c, parent, &c.Cache)
+	return nil
+}
+
+// TestAsEmbeddedCollections tests that As searches slices and maps inside embedded structs
+func TestAsEmbeddedCollections(t *testing.T) {
+	type App struct {
+		EmbeddedPool
+		Consumer poolConsumer
+	}
+
+	app := &App{
+		EmbeddedPool: EmbeddedPool{
+			Databases: []*TestDatabase{{Name: "pooled"}},
+			Caches:    map[string]*TestCache{"main": {Name: "pooledCache"}},
+		},
+	}
+
+	if err := autoinit.AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Consumer.DB != app.Databases[0] {
+		t.Errorf("expected the database from the embedded slice, got %v", app.Consumer.DB)
+	}
+	if app.Consumer.Cache != app.Caches["main"] {
+		t.Errorf("expected the cache from the embedded map, got %v", app.Consumer.Cache)
+	}
+
+	// Embedded pointers and the finder API behave the same way
+	type PtrApp struct {
+		*EmbeddedPool
+		Consumer poolConsumer
+	}
+	ptrApp := &PtrApp{EmbeddedPool: &EmbeddedPool{Databases: []*TestDatabase{{Name: "pooled"}}}}
+	if err := autoinit.AutoInit(context.Background(), ptrApp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ptrApp.Consumer.DB != ptrApp.Databases[0] {
+		t.Errorf("expected the database from the embedded pointer's slice, got %v", ptrApp.Consumer.DB)
+	}
+	if db := autoinit.FindByType[*TestDatabase](context.Background(), nil, ptrApp); db != ptrApp.Databases[0] {
+		t.Errorf("FindByType should find the database from the embedded slice, got %v", db)
+	}
+}
+
+// TestTypeMatches tests the shared type matching rule used by As and the finder
+func TestTypeMatches(t *testing.T) {
+	db := &TestDatabase{}
+	var nilLogger TestLogger
+	holder := struct{ Logger TestLogger }{Logger: nilLogger}
+
+	dbType := reflect.TypeOf(TestDatabase{})
+	dbPtrType := reflect.TypeOf(db)
+	loggerType := reflect.TypeOf((*TestLogger)(nil)).Elem()
+
+	testCases := []struct {
+		name      string
+		candidate reflect.Value
+		target    reflect.Type
+		want      bool
+	}{
+		{"pointer to pointer type", reflect.ValueOf(db), dbPtrType, true},
+		{"pointer to value type", reflect.ValueOf(db), dbType, true},
+		{"value to pointer type", reflect.ValueOf(*db), dbPtrType, true},
+		{"unrelated type", reflect.ValueOf(db), reflect.TypeOf(TestCache{}), false},
+		{"pointer implements interface", reflect.ValueOf(&TestStructLogger{}), loggerType, true},
+		{"value whose pointer implements interface", reflect.ValueOf(TestStructLogger{}), loggerType, true},
+		{"nil interface value", reflect.ValueOf(holder).Field(0), loggerType, false},
+		{"invalid value", reflect.Value{}, dbType, false},
+	}
+
+	for _, tc := range testCases {
+		if got := autoinit.TypeMatches(tc.candidate, tc.target); got != tc.want {
+			t.Errorf("%s: TypeMatches = %v; want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -6,14 +6,22 @@
 // This enables plug-and-play architecture where adding new components requires
 // no changes to initialization code - just add the component field and it works.
 //
-// Supports three initialization patterns: Init(), Init(context.Context), and Init(context.Context, interface{}).
+// Supports three initialization patterns: Init(), Init(context.Context), and Init(context.Context, interface{}),
+// plus InitReflect(context.Context, reflect.Value) for components that need reflection access to their parent.
 package autoinit
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -33,6 +41,24 @@ type ParentInitializer interface {
 	Init(ctx context.Context, parent interface{}) error
 }
 
+// ReflectiveInitializer is an escape hatch for components that need reflection
+// access to their parent, e.g. serializers or validators that inspect sibling
+// fields, including unexported ones. If implemented, InitReflect is called
+// instead of any Init method.
+//
+// parent is the enclosing struct itself (never a pointer to it) and refers to
+// the live value: it is addressable whenever the parent was reached through a
+// pointer or an addressable field, which covers every struct AutoInit initializes
+// in place, so changes made through it persist. For a struct held by value in a
+// map, parent is the temporary copy AutoInit initializes and writes back into
+// the map after the copy's own Init. For the root component parent is the zero Value
+// (parent.IsValid() is false). Values obtained from unexported fields follow
+// the usual reflect rules: they can be read but not set or passed to Interface.
+// Do not retain parent beyond the call.
+type ReflectiveInitializer interface {
+	InitReflect(ctx context.Context, parent reflect.Value) error
+}
+
 // PreInitializer is the interface for pre-initialization hooks
 type PreInitializer interface {
 	PreInit(ctx context.Context) error
@@ -53,16 +79,229 @@ type PostFieldHook interface {
 	PostFieldInit(ctx context.Context, fieldName string, fieldValue interface{}) error
 }
 
+// ContextMutatingPreFieldHook lets a parent supply the context a specific
+// child is initialized with, e.g. to give one child its own configuration,
+// deadline or logger. PreFieldInitContext is called for each field after
+// PreFieldInit (if both are implemented); the returned context is used for the
+// field's whole subtree and its PostFieldInit call. It must be derived from
+// ctx, which carries AutoInit's own state; return ctx unchanged, or nil, to
+// keep it.
+type ContextMutatingPreFieldHook interface {
+	PreFieldInitContext(ctx context.Context, fieldName string, fieldValue interface{}) (context.Context, error)
+}
+
+// SerialInitializer marks a component that must be initialized on the goroutine
+// that called AutoInit, e.g. because it touches non-thread-safe globals or
+// main-thread-only APIs. The same constraint can be declared on a field with
+// the `autoinit:"serial"` tag.
+//
+// AutoInit traverses the tree on the calling goroutine, so only AutoInitLayers
+// with Options.MaxConcurrency > 1 is affected: it initializes the serial
+// components of a layer on the calling goroutine, one at a time, once the
+// rest of the layer completed. A run nested in a component's Init on another
+// goroutine initializes on that goroutine.
+type SerialInitializer interface {
+	SerialInit()
+}
+
 // Options configures the behavior of AutoInit
 type Options struct {
 	// Logger for trace logging during traversal. If nil, uses default stdout logger
 	Logger *zerolog.Logger
+	// LogLevel is the level of logs marking lifecycle steps, such as a run
+	// starting or a component's Init, hook, Link or Shutdown call completing.
+	// Field traversal details are always logged at zerolog.TraceLevel, so e.g.
+	// zerolog.InfoLevel shows which components were initialized without the
+	// traversal noise. If nil, lifecycle steps are logged at
+	// zerolog.TraceLevel like the traversal.
+	LogLevel *zerolog.Level
+	// ErrorLogLevel is the level of logs reporting failures. If nil, failures
+	// are logged at zerolog.ErrorLevel.
+	ErrorLogLevel *zerolog.Level
 	// DisableCycleDetection disables cycle detection (not recommended for production)
 	DisableCycleDetection bool
 	// RequireTags when true, only initializes components that have an autoinit tag.
 	// This gives explicit control over which components are plugged into the system.
 	// Components without tags will be skipped (not initialized).
 	RequireTags bool
+	// AllowDescend, if set, limits which struct types AutoInit descends into.
+	// Types for which it returns false are still initialized if they implement
+	// an Init method, but their fields are not traversed. Use it to avoid walking
+	// into large third-party or stdlib structs. See AllowPackages.
+	AllowDescend func(reflect.Type) bool
+	// LeafTypes lists additional struct types treated as plain values: fields of
+	// these types (or pointers and collections of them) are neither traversed nor
+	// initialized. Common stdlib value types such as time.Time, url.URL and big.Int
+	// are always treated as leaves.
+	LeafTypes []reflect.Type
+	// RecognizeConventions opts into common ecosystem lifecycle methods:
+	// Start(ctx) error is called as the initializer of components without an
+	// Init method, and io.Closer is used by AutoShutdown for components without
+	// a Shutdown method.
+	RecognizeConventions bool
+	// DetectDuplicateInit enables a validation pass that warns about components
+	// reachable twice within one parent, e.g. an embedded component whose Init is
+	// promoted to the parent, or (without cycle detection) two fields sharing a pointer.
+	DetectDuplicateInit bool
+	// OnEvent, if set, is called after every lifecycle method (PreInit, Init, PostInit)
+	// a component implements, in the order the calls are made. Calls never
+	// overlap, even when AutoInitLayers initializes components concurrently;
+	// events of concurrent components then arrive in the order they complete.
+	OnEvent func(Event)
+	// TraceID, if set, is attached to every log line and Event of the run and
+	// made available to components via TraceIDFromContext. See WithTraceID.
+	TraceID string
+	// ContinueOnError keeps initializing the rest of the tree after a component
+	// fails, and returns all failures joined with errors.Join. A failing component
+	// stops its own lifecycle: if PreInit (or a field hook) fails, its remaining
+	// fields, Init and PostInit are skipped; if Init fails, PostInit is skipped.
+	// Its successfully initialized children are kept, not rolled back, and its
+	// parent continues as if the component had completed, so the parent's
+	// PostFieldInit hook, Init and PostInit still run.
+	ContinueOnError bool
+	// Middleware wraps every component's Init call, first entry outermost.
+	// See InitFunc for the call it wraps, and RecoverMiddleware and
+	// TimingMiddleware for built-ins. Since it runs around Init, AutoInitLayers
+	// with Options.MaxConcurrency > 1 calls it concurrently for the components
+	// of a layer; TimingMiddleware's record function is still called for one
+	// component at a time.
+	Middleware []Middleware
+	// RecoverPanics recovers panics in Init, PreInit, PostInit and field hooks.
+	// A panic in a lifecycle method fails that component with an InitError whose
+	// Cause is a *PanicError carrying the panic value and stack; a panic in a
+	// field hook is returned as a *PanicError like any other hook error.
+	RecoverPanics bool
+	// RestoreOnFailure saves the exported fields of each component before its
+	// Init and puts them back if Init fails, including a recovered panic, so
+	// a failed component is left as it was, e.g. to be retried. Slices and
+	// maps are copied so appends and writes are undone; values behind
+	// pointers, such as child components, are not copied, and unexported
+	// fields are not restored.
+	RestoreOnFailure bool
+	// Report, if set, is filled with diagnostics about the run, such as which
+	// fields were skipped and why.
+	Report *InitReport
+	// ContextValues are added to the context before traversal, so components can
+	// read them with ctx.Value without the caller wrapping ctx in WithValue calls.
+	// They overlay the passed ctx: a key already present there is shadowed.
+	ContextValues map[interface{}]interface{}
+	// OnProgress, if set, is called after every Init call with the number of
+	// calls made so far and the expected total, e.g. to drive a startup progress
+	// bar. The total is counted before traversal using the same skip rules,
+	// counting a component shared through reinit fields once per reference, so
+	// it is exact for static trees; components added during the run (e.g. by a
+	// PreFieldInit hook, AllocateNilPointers or CallComponentMethods) make it
+	// an estimate, and it grows if exceeded. Calls never overlap and done
+	// increases by one with each, even when AutoInitLayers initializes
+	// components concurrently.
+	OnProgress func(done, total int)
+	// MaxSearchDepth, if positive, is the number of ancestor levels finder
+	// searches made during the run climb, instead of DefaultMaxSearchDepth.
+	// SearchOption.MaxDepth still overrides it for one search.
+	MaxSearchDepth int
+	// PerComponentTimeout, if positive, bounds every component's Init: Init
+	// receives a context that is cancelled after this long. A field can set its
+	// own limit with the `autoinit:"timeout=5s"` tag. Components must honor ctx
+	// for the limit to take effect, and the context is cancelled once Init
+	// returns, so it must not be kept for background work.
+	PerComponentTimeout time.Duration
+	// ReverseFieldOrder processes the fields of every struct from last to first,
+	// e.g. to let later-declared fields initialize first or to check that a tree
+	// does not depend on declaration order. priority=N tags still take precedence;
+	// only fields of equal priority are reversed. AutoShutdown with the same
+	// options tears down in the reverse of that order.
+	ReverseFieldOrder bool
+	// DeferCollections initializes the slice, array, map and Iterable fields
+	// of every struct in a second pass, after all its other fields, so that
+	// singular services are up before the workers held in collections that
+	// use them. By default fields initialize in declaration order, each
+	// collection completely before the next field. priority=N tags order the
+	// fields within each pass; ReverseFieldOrder reverses both passes.
+	DeferCollections bool
+	// AllocateNilPointers allocates nil pointer fields whose element type is a
+	// component (implements an initializer, see CheckNonNil) with reflect.New
+	// before initializing them, so fields need not be assigned &X{} by hand.
+	// Fields tagged `autoinit:"-"` or `autoinit:"optional"`, skipped fields and
+	// non-component types are left nil. Nil interface fields are filled from
+	// constructors registered with RegisterConstructor.
+	AllocateNilPointers bool
+	// TypeHooks attach behavior to every component of a type, wherever it
+	// appears in the tree, without modifying the component (e.g. to register
+	// every http.Handler with a router). A key matches a component by the rules
+	// of TypeMatches: its struct type, a pointer to it, or an interface it
+	// implements. For each component, the Before functions of all matching hooks
+	// run first, then PreInit, its fields, Init and PostInit, and finally the
+	// After functions. When several keys match, hooks run in order of their
+	// key's type name. An error from a hook fails the component like an error
+	// from Init; After does not run if the component failed. Hook functions
+	// are called one at a time, even when AutoInitLayers initializes
+	// components concurrently.
+	TypeHooks map[reflect.Type]TypeHook
+	// DisablePreInit, DisablePostInit and DisableFieldHooks skip the PreInit,
+	// PostInit and PreFieldInit/PreFieldInitContext/PostFieldInit hooks
+	// entirely, sparing trees that do not use them the interface checks made
+	// for every component and field. Init, Link and type hooks still run.
+	DisablePreInit    bool
+	DisablePostInit   bool
+	DisableFieldHooks bool
+	// TreeRetry re-runs the whole initialization if it fails, for components
+	// that must be initialized together. See TreeRetry.
+	TreeRetry TreeRetry
+	// CallComponentMethods initializes the components returned by methods
+	// registered with RegisterComponentMethod. Registered methods are never
+	// called without it.
+	CallComponentMethods bool
+	// FeatureEnabled decides whether fields tagged `autoinit:"feature=name"`
+	// take part in the run, e.g. by asking a feature-flag service. Fields of a
+	// disabled feature are skipped like `autoinit:"-"` fields, by AutoInit and
+	// by tree walks such as AutoShutdown; nil disables every feature.
+	FeatureEnabled func(name string) bool
+	// InitPlan, if non-nil, fixes the initialization order: the components at
+	// these dot-separated paths ("<root>" for the target) are initialized
+	// strictly in the listed order, each with its own PreInit, Init, PostInit
+	// and its parent's field hooks, regardless of where it sits in the tree.
+	// Components not listed, such as containers without an initializer and
+	// optional components, run afterwards in the usual order. The run fails
+	// before anything is initialized if the plan names a path that does not
+	// exist, or omits a component with an initializer that is not tagged
+	// `autoinit:"optional"`. InitReport.InitPlan records a plan from a previous
+	// run. The tree is traversed once per listed path. InitPlan cannot be
+	// combined with Phases.
+	InitPlan []string
+	// Phases orders named initialization phases across the whole tree. A
+	// field tagged `autoinit:"phase=name"` puts the component it holds, and all
+	// components below it, into that phase; a nested phase tag starts a new
+	// phase. AutoInit runs every component of the first phase, wherever it is
+	// in the tree, before any component of the second, and so on, then the
+	// components in no phase, including the root. Each component's lifecycle
+	// runs in its own phase, independent of its parent's. A phase tag not
+	// listed here fails the run before any component is initialized. Without
+	// Phases, phase tags are ignored.
+	Phases []string
+	// ConcurrencyByTag limits how many components of a resource class may be
+	// in their Init at the same time, e.g. {"network": 4} to avoid opening
+	// many connections at once. A field tagged `autoinit:"resource=name"`
+	// puts the component it holds, not the ones below it, in class name;
+	// classes without a positive limit are not limited. AutoInit traverses a
+	// tree sequentially, so the limits apply to the layers AutoInitLayers
+	// initializes concurrently, and when components initialize subtrees
+	// concurrently with nested AutoInit calls made with the run's context,
+	// which share the run's limits. A component nested in the Init
+	// of one holding the same class does not wait for another slot.
+	ConcurrencyByTag map[string]int
+	// MaxConcurrency is the number of components of a layer AutoInitLayers
+	// initializes at the same time. With 0 or 1 they are initialized one at
+	// a time on the calling goroutine. Other entry points ignore it.
+	MaxConcurrency int
+	// CheckRequirements resolves the requirements declared by components
+	// implementing DependencyDeclarer before any component is initialized,
+	// failing the run with a *RequirementsError if the tree does not satisfy
+	// them. See CheckRequirements.
+	CheckRequirements bool
+
+	// Set by InitGroup and ShutdownGroup to restrict lifecycle calls to one group
+	groupFilter bool
+	group       string
 }
 
 // defaultLogger creates a default logger to stdout with trace level
@@ -87,6 +326,10 @@ func getParentChain(ctx context.Context) *ParentChain {
 // Components are initialized depth-first in declaration order, enabling plug-and-play
 // architecture where you can add new components without changing initialization code.
 // Uses default logger for trace logging.
+//
+// Initialization order is deterministic: the same tree always produces the same
+// sequence of lifecycle calls. Map-held components are visited in sorted key order,
+// and no other source of randomness is involved in traversal.
 func AutoInit(ctx context.Context, target interface{}) error {
 	return WithOptions(ctx, target, nil)
 }
@@ -99,63 +342,219 @@ func AutoInit(ctx context.Context, target interface{}) error {
 // Supports: Init(), Init(ctx), and Init(ctx, parent) methods.
 // Includes cycle detection to prevent infinite loops in component references.
 func WithOptions(ctx context.Context, target interface{}, options *Options) error {
-	// Setup logger
-	var logger zerolog.Logger
-	if options != nil && options.Logger != nil {
-		logger = *options.Logger
-	} else {
-		logger = defaultLogger()
+	if options != nil && options.TreeRetry.Attempts > 1 {
+		return initTreeWithRetry(ctx, target, options)
 	}
+	return initTree(ctx, target, options)
+}
 
-	logger.Trace().
+// initTree runs AutoInit once
+func initTree(ctx context.Context, target interface{}, options *Options) error {
+	ctx, layered := takeLayeredRun(ctx)
+	ctx = withContextValues(ctx, options)
+	logger := optionsLogger(options)
+	ctx, logger = withRunTraceID(ctx, options, logger)
+	ctx = withRunLogger(ctx, logger)
+	// A run recording in the report of an enclosing run leaves it to that run
+	// to resolve the dependencies found
+	ownsReport := false
+	if options != nil && options.Report != nil {
+		ownsReport = reportSinkOf(ctx) == nil || reportSinkOf(ctx).report != options.Report
+		ctx = withReportSink(ctx, options.Report)
+	}
+
+	logger.WithLevel(stepLogLevel(options)).
 		Str("target_type", fmt.Sprintf("%T", target)).
 		Msg("Starting AutoInit")
 
-	if target == nil {
-		return fmt.Errorf("cannot initialize nil target")
+	v, err := resolveTarget(target, "initialize")
+	if err != nil {
+		return err
+	}
+	if !v.CanAddr() {
+		logger.Warn().
+			Str("target_type", v.Type().String()).
+			Msg("Target passed by value; changes to its value fields will not be visible to the caller, pass a pointer")
+		recordWarning(ctx, WarningTargetByValue, []string{},
+			fmt.Sprintf("target %s passed by value; changes to its value fields are not visible to the caller", v.Type()))
+	}
+	ctx = withProgress(ctx, v, options)
+	ctx = withRoot(ctx, target)
+	if options != nil && options.MaxSearchDepth > 0 {
+		ctx = WithMaxSearchDepth(ctx, options.MaxSearchDepth)
 	}
 
-	v := reflect.ValueOf(target)
-
-	// If it's a pointer, get the element
-	if v.Kind() == reflect.Ptr {
-		if v.IsNil() {
-			return fmt.Errorf("cannot initialize nil pointer")
+	// The root is not reached through a field, so no timeout tag or
+	// Recoverable policy applies
+	ctx = withFieldTimeout(ctx, 0)
+	ctx = withRecoveryPolicy(ctx, recoveryPolicy{})
+	ctx = withFieldResource(ctx, tagOptions{})
+	ctx = withFieldSerial(ctx, tagOptions{})
+
+	// Give the run its own parent chain, seeded with the ancestors on an
+	// enclosing run's chain, so that runs nested on other goroutines do not
+	// push onto each other's
+	ctx = WithParentChain(ctx, getParentChain(ctx).ancestors()...)
+
+	// Resolve declared dependencies before any component starts
+	if options != nil && options.CheckRequirements {
+		if err := checkRequirements(ctx, v, options); err != nil {
+			logger.WithLevel(errorLogLevel(options)).
+				Err(err).
+				Msg("AutoInit failed")
+			return err
 		}
-		v = v.Elem()
 	}
 
-	// Must be a struct
-	if v.Kind() != reflect.Struct {
-		return fmt.Errorf("target must be a struct or pointer to struct, got %s", v.Kind())
+	// Note where components are declared before Init assigns references to them
+	var declared map[componentKey][]string
+	if ownsReport {
+		declared = componentPaths(v, options)
 	}
 
-	// Create visited map for cycle detection (unless disabled)
-	var visited map[uintptr]bool
-	if options == nil || !options.DisableCycleDetection {
-		visited = make(map[uintptr]bool)
+	// Attach per-run bookkeeping, joining an enclosing run if there is one
+	ctx, state := withRunState(ctx)
+
+	// Collect failures instead of stopping at the first one if requested
+	var collector *errorCollector
+	if options != nil && options.ContinueOnError {
+		ctx, collector = withErrorCollector(ctx)
+	} else if getErrorCollector(ctx) != nil {
+		// A nested fail-fast run must not report into an enclosing run's collector
+		ctx = context.WithValue(ctx, errorCollectorKey, collector)
 	}
 
-	// Add parent chain to context if not already present
-	if getParentChain(ctx) == nil {
-		ctx = WithComponentSearch(ctx)
+	// Start recursive initialization with no parent, once per phase or
+	// dependency layer if any
+	if layered {
+		err = initLayered(ctx, v, &logger, options)
+	} else {
+		err = initPhases(ctx, v, &logger, options)
 	}
 
-	// Start recursive initialization with no parent (empty reflect.Value)
-	err := initStructWithVisited(ctx, v, reflect.Value{}, []string{}, &logger, visited, options)
+	// Cross-wire components once the whole tree completed PostInit: inject
+	// fields first, so that Link sees them assigned
+	if err == nil || (collector != nil && !isInterrupted(err)) {
+		if injectErr := injectTree(ctx, v, state, &logger, options); injectErr != nil {
+			err = injectErr
+		} else if linkErr := linkTree(ctx, v, state, &logger, options); linkErr != nil {
+			err = linkErr
+		}
+	}
+	if collector != nil {
+		if isInterrupted(err) {
+			err = errors.Join(collector.join(), err)
+		} else {
+			err = collector.join()
+		}
+	}
+
+	// Keep discovered dependencies for ReInit and for inspection
+	if options != nil && options.Report != nil {
+		options.Report.addDependencies(state.dependencySnapshot())
+		if ownsReport {
+			options.Report.resolveDependencies(v, options, declared)
+		}
+	}
 
 	if err != nil {
-		logger.Error().
+		logger.WithLevel(errorLogLevel(options)).
 			Err(err).
 			Msg("AutoInit failed")
 	} else {
-		logger.Trace().
+		logger.WithLevel(stepLogLevel(options)).
 			Msg("AutoInit completed successfully")
 	}
 
 	return err
 }
 
+// AutoInitValue initializes a copy of a struct passed by value and returns the
+// initialized copy, for callers that prefer value semantics over passing a pointer.
+// The copy is shallow: components reached through pointers, slices or maps are
+// shared with target and initialized in place. If T is itself a pointer type, the
+// pointed-to struct is initialized in place and the same pointer is returned.
+//
+//	app, err := autoinit.AutoInitValue(ctx, App{Config: cfg}, nil)
+func AutoInitValue[T any](ctx context.Context, target T, options *Options) (T, error) {
+	if reflect.TypeOf(target) != nil && reflect.TypeOf(target).Kind() == reflect.Ptr {
+		return target, WithOptions(ctx, target, options)
+	}
+
+	// Allocate an addressable copy so pointer-receiver Init methods can mutate it
+	result := target
+	err := WithOptions(ctx, &result, options)
+	return result, err
+}
+
+// optionsLogger returns the configured logger or the default stdout logger
+func optionsLogger(options *Options) zerolog.Logger {
+	if options != nil && options.Logger != nil {
+		return *options.Logger
+	}
+	return defaultLogger()
+}
+
+// stepLogLevel returns the level of logs marking lifecycle steps
+func stepLogLevel(options *Options) zerolog.Level {
+	if options == nil || options.LogLevel == nil {
+		return zerolog.TraceLevel
+	}
+	return *options.LogLevel
+}
+
+// errorLogLevel returns the level of logs reporting failures
+func errorLogLevel(options *Options) zerolog.Level {
+	if options == nil || options.ErrorLogLevel == nil {
+		return zerolog.ErrorLevel
+	}
+	return *options.ErrorLogLevel
+}
+
+// withContextValues returns ctx overlaid with options.ContextValues
+func withContextValues(ctx context.Context, options *Options) context.Context {
+	if options == nil {
+		return ctx
+	}
+	for key, value := range options.ContextValues {
+		ctx = context.WithValue(ctx, key, value)
+	}
+	return ctx
+}
+
+// resolveTarget validates a target passed to a public entry point and returns
+// the struct value it refers to. verb describes the operation for error messages.
+func resolveTarget(target interface{}, verb string) (reflect.Value, error) {
+	if target == nil {
+		return reflect.Value{}, fmt.Errorf("cannot %s nil target", verb)
+	}
+
+	v := reflect.ValueOf(target)
+
+	// If it's a pointer, get the element
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("cannot %s nil pointer", verb)
+		}
+		v = v.Elem()
+	}
+
+	// Must be a struct
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("target must be a struct or pointer to struct, got %s", v.Kind())
+	}
+
+	return v, nil
+}
+
+// appendPath returns a copy of path with elem appended
+func appendPath(path []string, elem string) []string {
+	result := make([]string, len(path)+1)
+	copy(result, path)
+	result[len(path)] = elem
+	return result
+}
+
 // pathToString converts path slice to dot-separated string
 func pathToString(path []string) string {
 	if len(path) == 0 {
@@ -174,15 +573,31 @@ func pathToString(path []string) string {
 // initStructWithVisited recursively discovers and initializes all components in a struct.
 // Each component (struct with Init method) is initialized after its child components,
 // enabling proper dependency order.
-func initStructWithVisited(ctx context.Context, v reflect.Value, parent reflect.Value, path []string, logger *zerolog.Logger, visited map[uintptr]bool, options *Options) error {
+// index is the declaration index of the struct field v was reached through
+// (collection elements share their collection's index), or -1 for the root.
+func initStructWithVisited(ctx context.Context, v reflect.Value, parent reflect.Value, path []string, index int, logger *zerolog.Logger, visited map[uintptr]bool, options *Options) error {
 	pathStr := pathToString(path)
+	ctx = withPlanNode(ctx, pathStr)
+
+	// Unwrap interface values (e.g. elements of []SomeInterface) to their dynamic value
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			logger.Trace().
+				Str("path", pathStr).
+				Msg("Skipping nil interface")
+			recordSkip(ctx, options, path, v.Type(), SkipNilPointer)
+			return nil
+		}
+		v = v.Elem()
+	}
 
 	// Handle pointer to struct
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
 			logger.Trace().
 				Str("path", pathStr).
-				Msg("Skipping nil pointer")
+				Msg(SkipNilPointer.logMessage())
+			recordSkip(ctx, options, path, v.Type(), SkipNilPointer)
 			return nil // Skip nil pointers
 		}
 
@@ -218,6 +633,11 @@ func initStructWithVisited(ctx context.Context, v reflect.Value, parent reflect.
 
 	t := v.Type()
 
+	// Stop promptly once the run's context has ended
+	if err := interruption(ctx, path); err != nil {
+		return err
+	}
+
 	// Maintain parent chain for component search
 	if chain := getParentChain(ctx); chain != nil {
 		// Get the interface value for this struct
@@ -231,52 +651,169 @@ func initStructWithVisited(ctx context.Context, v reflect.Value, parent reflect.
 		defer chain.Pop()
 	}
 
+	// Record the component in the tree report, if one is being built
+	ctx, node := beginNodeReport(ctx, path, t)
+
+	// A layered run initializes the components of a layer together, once its
+	// pass has reached all of them
+	if pass := currentPhasePass(ctx); pass != nil && pass.jobs != nil && pass.defers(ctx, pathStr, options) {
+		return pass.jobs.deferComponent(ctx, node, v, parent, path, index, logger, visited, options)
+	}
+	err := initComponent(ctx, v, parent, path, index, logger, visited, options)
+	return finishComponent(ctx, node, v, err, options)
+}
+
+// finishComponent records the outcome err of initializing the component v
+// and returns the error its parent sees
+func finishComponent(ctx context.Context, node *nodeReportCursor, v reflect.Value, err error, options *Options) error {
+	node.finish(err)
+	if state := getRunState(ctx); err != nil && state != nil {
+		state.markFailed(v)
+	}
+	if options != nil && options.Report != nil && lifecycleActive(ctx, options) {
+		if key, ok := mapCopyOf(ctx).keyOf(v); ok {
+			options.Report.recordOutcome(key, err == nil)
+		}
+	}
+
+	// With ContinueOnError the failure is recorded and the parent carries on,
+	// unless the run's context ended
+	if collector := getErrorCollector(ctx); err != nil && collector != nil && !isInterrupted(err) {
+		collector.add(err)
+		return nil
+	}
+	return err
+}
+
+// initComponent runs the lifecycle of struct v: PreInit, its fields, Init and PostInit
+func initComponent(ctx context.Context, v reflect.Value, parent reflect.Value, path []string, index int, logger *zerolog.Logger, visited map[uintptr]bool, options *Options) error {
+	if err := checkSingleton(ctx, v, path, index); err != nil {
+		return err
+	}
+
+	// When initializing a single group, or in a pass of a phased run, only
+	// the selected components run lifecycle methods
+	if !lifecycleActive(ctx, options) {
+		return initDescendants(ctx, v, path, logger, visited, options)
+	}
+
+	config, err := beginLifecycle(ctx, v, path, index, logger, options)
+	if err != nil {
+		return err
+	}
+	if err := initDescendants(ctx, v, path, logger, visited, options); err != nil {
+		return err
+	}
+	return endLifecycle(ctx, v, parent, path, index, logger, options, config)
+}
+
+// beginLifecycle runs the steps of v's lifecycle that precede its fields:
+// the type hooks before it and PreInit. It returns the snapshot of v's
+// configuration if v is a ConfigValidator.
+func beginLifecycle(ctx context.Context, v reflect.Value, path []string, index int, logger *zerolog.Logger, options *Options) (*configSnapshot, error) {
+	// A ConfigValidator's configuration must come out of the lifecycle unchanged
+	config, err := snapshotConfig(v)
+	if err != nil {
+		return nil, &InitError{Path: path, FieldIndex: index, FieldType: reflect.PointerTo(v.Type()).String(), Cause: err}
+	}
+
+	// Type hooks wrap the component's own lifecycle
+	if err := callTypeHooks(ctx, v, path, index, logger, options, PhaseBeforeTypeHook); err != nil {
+		return nil, err
+	}
+
 	// Call PreInit hook if this struct implements it
-	if err := callPreInit(ctx, v, path, logger); err != nil {
+	if err := callPreInit(ctx, v, path, index, logger, options); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// initDescendants initializes the fields of v, unless descent into its type
+// is disallowed
+func initDescendants(ctx context.Context, v reflect.Value, path []string, logger *zerolog.Logger, visited map[uintptr]bool, options *Options) error {
+	if !allowDescend(options, v.Type()) {
+		logger.Trace().
+			Str("path", pathToString(path)).
+			Str("type", v.Type().String()).
+			Msg("Not descending into type (AllowDescend)")
+		return nil
+	}
+	return initFields(ctx, v, path, logger, visited, options)
+}
+
+// endLifecycle runs the steps of v's lifecycle that follow its fields: Init,
+// PostInit, the check of its configuration and the type hooks after it
+func endLifecycle(ctx context.Context, v reflect.Value, parent reflect.Value, path []string, index int, logger *zerolog.Logger, options *Options, config *configSnapshot) error {
+	t := v.Type()
+
+	// After initializing all fields, check if this struct itself has Init() method
+	if err := callInitIfExists(ctx, v, parent, path, index, logger, options); err != nil {
 		return err
 	}
 
-	// First, recursively initialize all fields
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		fieldType := t.Field(i)
+	// Call PostInit hook if this struct implements it
+	if err := callPostInit(ctx, v, path, index, logger, options); err != nil {
+		return err
+	}
 
-		// Skip unexported fields
-		if !field.CanInterface() {
-			logger.Trace().
-				Str("path", pathStr).
-				Str("field", fieldType.Name).
-				Msg("Skipping unexported field")
-			continue
-		}
+	if err := config.verify(); err != nil {
+		return &InitError{Path: path, FieldIndex: index, FieldType: reflect.PointerTo(t).String(), Cause: err}
+	}
 
-		// Check autoinit tag if RequireTags is enabled
-		tag := fieldType.Tag.Get("autoinit")
-		if tag == "-" {
-			// Explicitly skip this field
-			logger.Trace().
+	return callTypeHooks(ctx, v, path, index, logger, options, PhaseAfterTypeHook)
+}
+
+// initFields initializes the fields of struct v in priority order (see fieldOrder)
+func initFields(ctx context.Context, v reflect.Value, path []string, logger *zerolog.Logger, visited map[uintptr]bool, options *Options) error {
+	pathStr := pathToString(path)
+	t := v.Type()
+
+	if options != nil && options.DetectDuplicateInit {
+		for _, problem := range findDuplicateInits(v, options) {
+			logger.Warn().
 				Str("path", pathStr).
-				Str("field", fieldType.Name).
-				Msg("Skipping field with autoinit:\"-\" tag")
-			continue
+				Msg(problem)
+			recordWarning(ctx, WarningDuplicateInit, path, problem)
 		}
+	}
 
-		if options != nil && options.RequireTags {
-			// When RequireTags is true, only process fields with autoinit tag
-			// (empty tag "" or specific values like "init" are OK)
-			if _, hasTag := fieldType.Tag.Lookup("autoinit"); !hasTag {
-				logger.Trace().
+	// Fields are processed in priority order, declaration order within a priority
+	order, err := fieldOrder(t, options)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range order {
+		field := v.Field(i)
+		fieldType := t.Field(i)
+
+		// Skip unexported, "-"-tagged and (with RequireTags) untagged fields
+		tag := parseTag(fieldType)
+		if recordsSkips(ctx) {
+			for _, option := range unknownTagOptions(tag) {
+				logger.Warn().
 					Str("path", pathStr).
 					Str("field", fieldType.Name).
-					Msg("Skipping field without autoinit tag (RequireTags enabled)")
-				continue
+					Str("option", option).
+					Msg("Ignoring unknown autoinit tag option")
+				recordWarning(ctx, WarningUnknownTagOption, appendPath(path, fieldType.Name),
+					fmt.Sprintf("unknown autoinit tag option %q is ignored", option))
 			}
 		}
+		if reason := fieldSkipReason(field, tag, options); reason != 0 {
+			logger.Trace().
+				Str("path", pathStr).
+				Str("field", fieldType.Name).
+				Msg(reason.logMessage())
+			if mayHoldComponents(fieldType.Type) {
+				recordSkip(ctx, options, appendPath(path, fieldType.Name), fieldType.Type, reason)
+			}
+			continue
+		}
 
 		// Create path for error reporting
-		fieldPath := make([]string, len(path)+1)
-		copy(fieldPath, path)
-		fieldPath[len(path)] = fieldType.Name
+		fieldPath := appendPath(path, fieldType.Name)
 		fieldPathStr := pathToString(fieldPath)
 
 		logger.Trace().
@@ -285,58 +822,157 @@ func initStructWithVisited(ctx context.Context, v reflect.Value, parent reflect.
 			Str("kind", field.Kind().String()).
 			Msg("Traversing field")
 
+		if requiresSerialInit(field, tag) {
+			logger.Trace().
+				Str("path", fieldPathStr).
+				Msg("Field requires serial initialization")
+		}
+
+		// A reinit tag opts the component out of cycle detection, so a shared
+		// pointer is initialized every time it is reached
+		if tag.has("reinit") {
+			allowReinit(ctx, visited, field, fieldPathStr, logger)
+		}
+
+		// Components below a group=name or phase=name tag belong to that
+		// group or phase
+		fieldCtx := withFieldResource(withFieldPhase(withFieldGroup(ctx, tag), tag), tag)
+		fieldCtx = withFieldSerial(fieldCtx, tag)
+		fieldCtx = withPlanNode(fieldCtx, fieldPathStr)
+		fieldCtx = withLayerField(fieldCtx)
+
+		// A timeout=D tag overrides Options.PerComponentTimeout for this field
+		timeout, err := tag.timeout()
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %w", t.String(), fieldType.Name, err)
+		}
+		fieldCtx = withFieldTimeout(fieldCtx, timeout)
+
+		// A Recoverable wrapper is transparent: its inner component takes the
+		// field's place, with its initializer guarded by the wrapper's policy
+		field, policy, _ := unwrapRecoverable(field)
+		fieldCtx = withRecoveryPolicy(fieldCtx, policy)
+
+		// Containers implementing Iterable are initialized entry by entry
+		if iterable, ok := iterableOf(field); ok {
+			if err := initIterableField(fieldCtx, v, field, iterable, fieldType.Name, fieldPath, i, logger, visited, options); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Factory lists are built and initialized entry by entry
+		if isFactoryList(field) {
+			if err := initFactoryField(fieldCtx, v, field, tag, fieldType.Name, fieldPath, i, logger, visited, options); err != nil {
+				return err
+			}
+			continue
+		}
+
 		// Handle different field types
 		switch field.Kind() {
 		case reflect.Struct:
 			// Call parent's PreFieldInit hook if it exists
-			if err := callPreFieldHook(ctx, v, fieldType.Name, field, logger); err != nil {
+			fieldCtx, err = callPreFieldHook(fieldCtx, v, fieldType.Name, field, logger, options)
+			if err != nil {
 				return err
 			}
 
 			// Recurse into struct fields with current struct as parent
-			if err := initStructWithVisited(ctx, field, v, fieldPath, logger, visited, options); err != nil {
+			if err := initStructWithVisited(fieldCtx, field, v, fieldPath, i, logger, visited, options); err != nil {
 				return err
 			}
 
 			// Call parent's PostFieldInit hook if it exists
-			if err := callPostFieldHook(ctx, v, fieldType.Name, field, logger); err != nil {
+			if err := callPostFieldHook(fieldCtx, v, fieldType.Name, field, logger, options); err != nil {
 				return err
 			}
 
 		case reflect.Ptr:
-			if !field.IsNil() && field.Elem().Kind() == reflect.Struct {
+			if field.IsNil() && allocatesNil(field, tag, options) {
+				field.Set(reflect.New(field.Type().Elem()))
+				logger.Trace().
+					Str("path", fieldPathStr).
+					Msg("Allocated nil component pointer")
+			}
+			if field.IsNil() && field.Type().Elem().Kind() == reflect.Struct {
+				logger.Trace().
+					Str("path", fieldPathStr).
+					Msg(SkipNilPointer.logMessage())
+				recordSkip(ctx, options, fieldPath, field.Type(), SkipNilPointer)
+			} else if !field.IsNil() && field.Elem().Kind() == reflect.Struct {
 				// Call parent's PreFieldInit hook if it exists
-				if err := callPreFieldHook(ctx, v, fieldType.Name, field, logger); err != nil {
+				fieldCtx, err = callPreFieldHook(fieldCtx, v, fieldType.Name, field, logger, options)
+				if err != nil {
 					return err
 				}
 
 				// Recurse into pointer to struct with current struct as parent
-				if err := initStructWithVisited(ctx, field, v, fieldPath, logger, visited, options); err != nil {
+				if err := initStructWithVisited(fieldCtx, field, v, fieldPath, i, logger, visited, options); err != nil {
 					return err
 				}
 
 				// Call parent's PostFieldInit hook if it exists
-				if err := callPostFieldHook(ctx, v, fieldType.Name, field, logger); err != nil {
+				if err := callPostFieldHook(fieldCtx, v, fieldType.Name, field, logger, options); err != nil {
+					return err
+				}
+			} else if !field.IsNil() && field.Elem().Kind() == reflect.Interface && !field.Elem().IsNil() {
+				// A pointer to an interface, as found in generated code, holds
+				// its component in the interface it points to
+				logger.Trace().
+					Str("path", fieldPathStr).
+					Str("type", field.Elem().Elem().Type().String()).
+					Msg("Initializing component behind pointer to interface")
+
+				fieldCtx, err = callPreFieldHook(fieldCtx, v, fieldType.Name, field, logger, options)
+				if err != nil {
+					return err
+				}
+				if err := initStructWithVisited(fieldCtx, field.Elem(), v, fieldPath, i, logger, visited, options); err != nil {
+					return err
+				}
+				if err := callPostFieldHook(fieldCtx, v, fieldType.Name, field, logger, options); err != nil {
 					return err
 				}
 			}
 
-		case reflect.Slice, reflect.Array:
-			// Check if this collection contains structs or pointers to structs
-			hasInitializableElements := false
-			if field.Len() > 0 {
-				elemType := field.Type().Elem()
-				if elemType.Kind() == reflect.Struct ||
-					(elemType.Kind() == reflect.Ptr && elemType.Elem().Kind() == reflect.Struct) ||
-					elemType.Kind() == reflect.Interface {
-					hasInitializableElements = true
+		case reflect.Interface:
+			// Interface fields are only initialized when AutoInit built their
+			// value from a registered constructor (see RegisterConstructor), or
+			// when they are embedded, which makes their value part of the struct
+			embedded := fieldType.Anonymous && !field.IsNil()
+			if embedded || (field.IsNil() && constructNil(field, tag, options)) {
+				msg := "Constructed nil interface field"
+				if embedded {
+					msg = "Initializing embedded interface field"
+				}
+				logger.Trace().
+					Str("path", fieldPathStr).
+					Str("type", field.Elem().Type().String()).
+					Msg(msg)
+
+				fieldCtx, err = callPreFieldHook(fieldCtx, v, fieldType.Name, field, logger, options)
+				if err != nil {
+					return err
+				}
+				if err := initStructWithVisited(fieldCtx, field, v, fieldPath, i, logger, visited, options); err != nil {
+					return err
+				}
+				if err := callPostFieldHook(fieldCtx, v, fieldType.Name, field, logger, options); err != nil {
+					return err
 				}
 			}
 
+		case reflect.Slice, reflect.Array:
+			// Check if this collection holds structs or pointers to structs.
+			// Hooks fire even for nil/empty collections so the parent can populate them.
+			hasInitializableElements := isInitializableElemType(field.Type().Elem())
+
 			// Only call hooks if the collection contains initializable types
 			if hasInitializableElements {
 				// Call parent's PreFieldInit hook for the collection itself
-				if err := callPreFieldHook(ctx, v, fieldType.Name, field, logger); err != nil {
+				fieldCtx, err = callPreFieldHook(fieldCtx, v, fieldType.Name, field, logger, options)
+				if err != nil {
 					return err
 				}
 			}
@@ -347,7 +983,7 @@ func initStructWithVisited(ctx context.Context, v reflect.Value, parent reflect.
 				elemPath := make([]string, len(fieldPath)+1)
 				copy(elemPath, fieldPath)
 				elemPath[len(fieldPath)] = fmt.Sprintf("[%d]", j)
-				if err := initStructWithVisited(ctx, elem, v, elemPath, logger, visited, options); err != nil {
+				if err := initStructWithVisited(fieldCtx, elem, v, elemPath, i, logger, visited, options); err != nil {
 					return err
 				}
 			}
@@ -355,50 +991,63 @@ func initStructWithVisited(ctx context.Context, v reflect.Value, parent reflect.
 			// Only call hooks if the collection contains initializable types
 			if hasInitializableElements {
 				// Call parent's PostFieldInit hook for the collection itself
-				if err := callPostFieldHook(ctx, v, fieldType.Name, field, logger); err != nil {
+				if err := callPostFieldHook(fieldCtx, v, fieldType.Name, field, logger, options); err != nil {
 					return err
 				}
 			}
 
 		case reflect.Map:
 			// Check if this map contains structs or pointers to structs
-			hasInitializableElements := false
-			valueType := field.Type().Elem()
-			if valueType.Kind() == reflect.Struct ||
-				(valueType.Kind() == reflect.Ptr && valueType.Elem().Kind() == reflect.Struct) ||
-				valueType.Kind() == reflect.Interface {
-				hasInitializableElements = true
-			}
+			hasInitializableElements := isInitializableElemType(field.Type().Elem())
 
 			// Only call hooks if the map contains initializable types
 			if hasInitializableElements {
 				// Call parent's PreFieldInit hook for the map itself
-				if err := callPreFieldHook(ctx, v, fieldType.Name, field, logger); err != nil {
+				fieldCtx, err = callPreFieldHook(fieldCtx, v, fieldType.Name, field, logger, options)
+				if err != nil {
 					return err
 				}
 			}
 
-			// Initialize each map value if it's a struct
-			for _, key := range field.MapKeys() {
-				elem := field.MapIndex(key)
-				elemPath := make([]string, len(fieldPath)+1)
-				copy(elemPath, fieldPath)
-				elemPath[len(fieldPath)] = fmt.Sprintf("[%v]", key)
-
-				// Map values are not addressable, so we need to handle them specially
-				if elem.Kind() == reflect.Struct {
-					// For struct values in maps, we need to create a new value,
-					// initialize it, and set it back
-					newElem := reflect.New(elem.Type()).Elem()
-					newElem.Set(elem)
-					if err := initStructWithVisited(ctx, newElem.Addr(), v, elemPath, logger, visited, options); err != nil {
-						return err
+			// Initialize each map value if it's a struct, in sorted key order
+			if !enterMap(visited, field) {
+				logger.Trace().
+					Str("path", pathToString(fieldPath)).
+					Msg("Skipping already visited map (cycle detected)")
+			} else {
+				for _, key := range sortedMapKeys(field) {
+					elem := field.MapIndex(key)
+					elemPath := make([]string, len(fieldPath)+1)
+					copy(elemPath, fieldPath)
+					elemPath[len(fieldPath)] = fmt.Sprintf("[%v]", key)
+
+					// Unwrap interface-typed map values to their dynamic value
+					if elem.Kind() == reflect.Interface && !elem.IsNil() {
+						elem = elem.Elem()
 					}
-					field.SetMapIndex(key, newElem)
-				} else if elem.Kind() == reflect.Ptr && !elem.IsNil() {
-					// For pointer values, we can work with them directly
-					if err := initStructWithVisited(ctx, elem, v, elemPath, logger, visited, options); err != nil {
-						return err
+
+					// Map values are not addressable, so we need to handle them specially
+					if elem.Kind() == reflect.Struct {
+						// For struct values in maps, we need to create a new value,
+						// initialize it, and set it back
+						newElem := reflect.New(elem.Type()).Elem()
+						newElem.Set(elem)
+						elemCtx := withLayerField(withMapCopy(fieldCtx, field, key, newElem))
+						if err := initStructWithVisited(elemCtx, newElem.Addr(), v, elemPath, i, logger, visited, options); err != nil {
+							return err
+						}
+						key := key
+						if err := afterLayer(elemCtx, func() error {
+							field.SetMapIndex(key, newElem)
+							return nil
+						}); err != nil {
+							return err
+						}
+					} else if elem.Kind() == reflect.Ptr && !elem.IsNil() {
+						// For pointer values, we can work with them directly
+						if err := initStructWithVisited(fieldCtx, elem, v, elemPath, i, logger, visited, options); err != nil {
+							return err
+						}
 					}
 				}
 			}
@@ -406,229 +1055,464 @@ func initStructWithVisited(ctx context.Context, v reflect.Value, parent reflect.
 			// Only call hooks if the map contains initializable types
 			if hasInitializableElements {
 				// Call parent's PostFieldInit hook for the map itself
-				if err := callPostFieldHook(ctx, v, fieldType.Name, field, logger); err != nil {
+				if err := callPostFieldHook(fieldCtx, v, fieldType.Name, field, logger, options); err != nil {
 					return err
 				}
 			}
 		}
 	}
 
-	// After initializing all fields, check if this struct itself has Init() method
-	if err := callInitIfExists(ctx, v, parent, path, logger); err != nil {
+	// Components returned by registered factory methods come after the fields
+	return initMethodComponents(ctx, v, path, logger, visited, options)
+}
+
+// fieldSkipReason returns why a struct field must not be traversed,
+// or 0 if it should be processed
+func fieldSkipReason(field reflect.Value, tag tagOptions, options *Options) SkipReason {
+	if !field.CanInterface() {
+		return SkipUnexported
+	}
+	if tag.skip {
+		return SkipTag
+	}
+	if tag.has("weak") {
+		return SkipWeak
+	}
+	if isInjected(tag) {
+		return SkipInjected
+	}
+	// When RequireTags is true, only process fields with autoinit tag
+	// (empty tag "" or specific values like "init" are OK)
+	if options != nil && options.RequireTags && !tag.present {
+		return SkipMissingTag
+	}
+	if feature, ok := tag.value("feature"); ok && !featureEnabled(options, feature) {
+		return SkipFeatureDisabled
+	}
+	if isLeafType(options, field.Type()) {
+		return SkipLeafType
+	}
+	return 0
+}
+
+// featureEnabled reports whether options enable the named feature
+func featureEnabled(options *Options, feature string) bool {
+	return options != nil && options.FeatureEnabled != nil && options.FeatureEnabled(feature)
+}
+
+// builtinLeafTypes are stdlib struct types that hold plain values rather than components
+var builtinLeafTypes = map[reflect.Type]bool{
+	reflect.TypeOf(time.Time{}):     true,
+	reflect.TypeOf(time.Location{}): true,
+	reflect.TypeOf(url.URL{}):       true,
+	reflect.TypeOf(url.Userinfo{}):  true,
+	reflect.TypeOf(big.Int{}):       true,
+	reflect.TypeOf(big.Float{}):     true,
+	reflect.TypeOf(big.Rat{}):       true,
+	reflect.TypeOf(net.IPNet{}):     true,
+	reflect.TypeOf(regexp.Regexp{}): true,
+}
+
+// isLeafType reports whether a field of type t holds leaf values: a leaf struct,
+// or a pointer or collection of them
+func isLeafType(options *Options, t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if builtinLeafTypes[t] {
+		return true
+	}
+	if options != nil {
+		for _, leaf := range options.LeafTypes {
+			if leaf == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowDescend reports whether traversal may descend into the fields of struct type t
+func allowDescend(options *Options, t reflect.Type) bool {
+	return options == nil || options.AllowDescend == nil || options.AllowDescend(t)
+}
+
+// AllowPackages returns an AllowDescend predicate that only descends into struct
+// types declared in packages whose import path starts with one of the given prefixes.
+// Unnamed struct types (e.g. anonymous structs declared inline) are always allowed.
+//
+//	options := &autoinit.Options{
+//	    AllowDescend: autoinit.AllowPackages("github.com/myorg/myapp"),
+//	}
+func AllowPackages(prefixes ...string) func(reflect.Type) bool {
+	return func(t reflect.Type) bool {
+		pkg := t.PkgPath()
+		if pkg == "" {
+			return true
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(pkg, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// initIterableField initializes the components held by an Iterable field, then
+// the container itself, between the parent's field hooks
+func initIterableField(ctx context.Context, parent, field reflect.Value, iterable Iterable, name string, path []string, index int, logger *zerolog.Logger, visited map[uintptr]bool, options *Options) error {
+	ctx, err := callPreFieldHook(ctx, parent, name, field, logger, options)
+	if err != nil {
 		return err
 	}
 
-	// Call PostInit hook if this struct implements it
-	if err := callPostInit(ctx, v, path, logger); err != nil {
+	for _, entry := range iterableEntries(iterable) {
+		elemPath := appendPath(path, fmt.Sprintf("[%v]", entry.key))
+		switch entry.value.Kind() {
+		case reflect.Ptr:
+			if err := initStructWithVisited(ctx, entry.value, parent, elemPath, index, logger, visited, options); err != nil {
+				return err
+			}
+		case reflect.Struct:
+			logger.Trace().
+				Str("path", pathToString(elemPath)).
+				Msg("Skipping struct value in Iterable (changes could not be stored)")
+		}
+	}
+
+	// The container may be a component itself
+	if err := initStructWithVisited(ctx, field, parent, path, index, logger, visited, options); err != nil {
 		return err
 	}
 
-	return nil
+	return callPostFieldHook(ctx, parent, name, field, logger, options)
 }
 
-// callInitIfExists checks if the value has any Init method variant and calls it
-// Priority order: Init(ctx, parent) > Init(ctx) > Init()
-func callInitIfExists(ctx context.Context, v reflect.Value, parent reflect.Value, path []string, logger *zerolog.Logger) error {
-	pathStr := pathToString(path)
+// allowReinit lets the component held by a field tagged reinit be visited
+// again, unless it is one of the components being initialized around it: that
+// reference is a true cycle and would recurse forever
+func allowReinit(ctx context.Context, visited map[uintptr]bool, field reflect.Value, path string, logger *zerolog.Logger) {
+	if visited == nil {
+		return
+	}
+	if field.Kind() == reflect.Interface && !field.IsNil() {
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.Ptr || field.IsNil() {
+		return
+	}
+	ptr := field.Pointer()
+	if chain := getParentChain(ctx); chain != nil {
+		for _, ancestor := range chain.ancestors() {
+			if a := reflect.ValueOf(ancestor); a.IsValid() && a.Type() == field.Type() && a.Pointer() == ptr {
+				logger.Trace().
+					Str("path", path).
+					Msg("Not re-initializing an enclosing component (cycle detected)")
+				return
+			}
+		}
+	}
+	delete(visited, ptr)
+}
 
-	// Get a pointer to the value if it's not already a pointer
+// enterMap marks map m as visited and reports whether this is its first visit.
+// Struct values held in maps are traversed as fresh copies, so pointer-based
+// cycle detection cannot see a cycle through them (e.g. a struct stored as a
+// value in its own map); keying on the map itself catches it. Always reports
+// true when cycle detection is disabled.
+func enterMap(visited map[uintptr]bool, m reflect.Value) bool {
+	if visited == nil || m.IsNil() {
+		return true
+	}
+	ptr := m.Pointer()
+	if visited[ptr] {
+		return false
+	}
+	visited[ptr] = true
+	return true
+}
+
+// isInitializableElemType reports whether collection elements of type t may be
+// components: structs, pointers to structs, or interfaces
+func isInitializableElemType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct ||
+		(t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct) ||
+		t.Kind() == reflect.Interface
+}
+
+// isComponentType reports whether t is a pointer to a struct type implementing
+// an initializer
+func isComponentType(t reflect.Type, options *Options) bool {
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	conventions := options != nil && options.RecognizeConventions
+	return resolveInitializer(reflect.New(t.Elem()), reflect.Value{}, conventions) != nil
+}
+
+// allocatesNil reports whether the nil pointer field is allocated under
+// Options.AllocateNilPointers
+func allocatesNil(field reflect.Value, tag tagOptions, options *Options) bool {
+	return options != nil && options.AllocateNilPointers && field.CanSet() &&
+		!tag.has("optional") && isComponentType(field.Type(), options)
+}
+
+// mayHoldComponents reports whether a field of type t can hold components,
+// either directly or as collection elements
+func mayHoldComponents(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return isInitializableElemType(t.Elem())
+	default:
+		return isInitializableElemType(t)
+	}
+}
+
+// lifecycleCall is a resolved lifecycle method ready to be invoked
+type lifecycleCall struct {
+	method   string // Method signature for logging, e.g. "Init(ctx, parent)"
+	typeName string // type the method was resolved on
+	invoke   func(ctx context.Context) error
+	copied   bool        // method runs on a non-addressable copy, so mutations won't persist
+	receiver interface{} // value the method is called on
+}
+
+// resolveInitializer finds the Init method variant implemented by v.
+// Priority order: Init(ctx, parent) > Init(ctx) > Init()
+// When conventions is true, Start(ctx) is recognized after all Init variants.
+// Returns nil if v is not a component.
+func resolveInitializer(v reflect.Value, parent reflect.Value, conventions bool) *lifecycleCall {
+	// Get a pointer to the value if it's not already a pointer.
+	// If the value can't be addressed, Init is called on the value itself
+	// and changes made by a value receiver won't persist.
 	ptr := v
 	if v.Kind() != reflect.Ptr && v.CanAddr() {
 		ptr = v.Addr()
 	}
-
-	// Prepare parent interface{} if parent is valid
-	var parentInterface interface{}
-	if parent.IsValid() && parent.CanInterface() {
-		if parent.Kind() == reflect.Ptr {
-			parentInterface = parent.Interface()
-		} else if parent.CanAddr() {
-			parentInterface = parent.Addr().Interface()
-		} else {
-			parentInterface = parent.Interface()
+	if call := resolveInitMethod(ptr, parent); call != nil {
+		call.copied = ptr.Kind() != reflect.Ptr
+		call.receiver = ptr.Interface()
+		return call
+	}
+	if conventions && ptr.CanInterface() {
+		if starter, ok := ptr.Interface().(Starter); ok {
+			return &lifecycleCall{
+				method:   "Start(ctx)",
+				typeName: ptr.Type().String(),
+				invoke:   starter.Start,
+				receiver: starter,
+			}
 		}
 	}
+	return nil
+}
 
-	// Check for Init(ctx, parent) - highest priority
-	if initializer, ok := ptr.Interface().(ParentInitializer); ok {
-		logger.Trace().
-			Str("path", pathStr).
-			Str("type", ptr.Type().String()).
-			Str("method", "Init(ctx, parent)").
-			Msg("Calling initializer")
+// resolveInitMethod checks the Init interfaces on a single value
+func resolveInitMethod(v reflect.Value, parent reflect.Value) *lifecycleCall {
+	if !v.CanInterface() {
+		return nil
+	}
 
-		if err := initializer.Init(ctx, parentInterface); err != nil {
-			logger.Error().
-				Str("path", pathStr).
-				Err(err).
-				Msg("Init(ctx, parent) failed")
-			return &InitError{
-				Path:      path,
-				FieldType: ptr.Type().String(),
-				Cause:     err,
-			}
+	typeName := v.Type().String()
+	switch initializer := v.Interface().(type) {
+	case ReflectiveInitializer:
+		if parent.Kind() == reflect.Ptr {
+			parent = parent.Elem()
 		}
-		logger.Trace().
-			Str("path", pathStr).
-			Msg("Init(ctx, parent) completed successfully")
+		return &lifecycleCall{
+			method:   "InitReflect(ctx, parent)",
+			typeName: typeName,
+			invoke: func(ctx context.Context) error {
+				return initializer.InitReflect(ctx, parent)
+			},
+		}
+	case ParentInitializer:
+		parentInterface := parentInterfaceOf(parent)
+		return &lifecycleCall{
+			method:   "Init(ctx, parent)",
+			typeName: typeName,
+			invoke: func(ctx context.Context) error {
+				return initializer.Init(ctx, parentInterface)
+			},
+		}
+	case ContextInitializer:
+		return &lifecycleCall{
+			method:   "Init(ctx)",
+			typeName: typeName,
+			invoke:   initializer.Init,
+		}
+	case SimpleInitializer:
+		return &lifecycleCall{
+			method:   "Init()",
+			typeName: typeName,
+			invoke: func(context.Context) error {
+				return initializer.Init()
+			},
+		}
+	}
+	return nil
+}
+
+// parentInterfaceOf returns the parent passed to Init(ctx, parent): a pointer to
+// the parent struct when possible, nil for the root
+func parentInterfaceOf(parent reflect.Value) interface{} {
+	if !parent.IsValid() || !parent.CanInterface() {
 		return nil
 	}
+	if parent.Kind() == reflect.Ptr {
+		return parent.Interface()
+	} else if parent.CanAddr() {
+		return parent.Addr().Interface()
+	}
+	return parent.Interface()
+}
 
-	// Check for Init(ctx) - second priority
-	if initializer, ok := ptr.Interface().(ContextInitializer); ok {
-		logger.Trace().
-			Str("path", pathStr).
-			Str("type", ptr.Type().String()).
-			Str("method", "Init(ctx)").
-			Msg("Calling initializer")
+// callInitIfExists checks if the value has any Init method variant and calls it
+// Priority order: InitReflect(ctx, parent) > Init(ctx, parent) > Init(ctx) > Init()
+func callInitIfExists(ctx context.Context, v reflect.Value, parent reflect.Value, path []string, index int, logger *zerolog.Logger, options *Options) error {
+	pathStr := pathToString(path)
 
-		if err := initializer.Init(ctx); err != nil {
-			logger.Error().
+	conventions := options != nil && options.RecognizeConventions
+	call := resolveInitializer(v, parent, conventions)
+	if call == nil {
+		// A pointer-receiver initializer is not in the method set of a
+		// non-addressable value; fail rather than skip the component silently
+		if v.Kind() == reflect.Struct && !v.CanAddr() && resolveInitializer(reflect.New(v.Type()), parent, conventions) != nil {
+			logger.WithLevel(errorLogLevel(options)).
 				Str("path", pathStr).
-				Err(err).
-				Msg("Init(ctx) failed")
+				Str("type", v.Type().String()).
+				Msg("Pointer-receiver initializer on a non-addressable value")
 			return &InitError{
-				Path:      path,
-				FieldType: ptr.Type().String(),
-				Cause:     err,
+				Path:       path,
+				FieldIndex: index,
+				FieldType:  v.Type().String(),
+				Cause:      ErrNotAddressable,
 			}
 		}
-		logger.Trace().
-			Str("path", pathStr).
-			Msg("Init(ctx) completed successfully")
 		return nil
 	}
 
-	// Check for Init() - lowest priority
-	if initializer, ok := ptr.Interface().(SimpleInitializer); ok {
-		logger.Trace().
+	if call.copied {
+		// Almost always a bug: e.g. a struct passed by value or returned from a method
+		logger.Warn().
 			Str("path", pathStr).
-			Str("type", ptr.Type().String()).
-			Str("method", "Init()").
-			Msg("Calling initializer")
+			Str("type", call.typeName).
+			Str("method", call.method).
+			Msg("Calling value-receiver initializer on a non-addressable value; changes will not persist")
+		recordWarning(ctx, WarningCopiedReceiver, path,
+			fmt.Sprintf("%s called on a copy of non-addressable %s; changes will not persist", call.method, call.typeName))
+	}
 
-		if err := initializer.Init(); err != nil {
-			logger.Error().
-				Str("path", pathStr).
-				Err(err).
-				Msg("Init() failed")
-			return &InitError{
-				Path:      path,
-				FieldType: ptr.Type().String(),
-				Cause:     err,
-			}
-		}
-		logger.Trace().
-			Str("path", pathStr).
-			Msg("Init() completed successfully")
-		return nil
+	logger.WithLevel(stepLogLevel(options)).
+		Str("path", pathStr).
+		Str("type", call.typeName).
+		Str("method", call.method).
+		Msg("Calling initializer")
+
+	callCtx, release, err := acquireResource(withReportPath(ctx, path), path, logger, options)
+	if err != nil {
+		return err
+	}
+	var restore func()
+	if options != nil && options.RestoreOnFailure {
+		restore = restorePoint(v)
+	}
+	start := time.Now()
+	err = protect(options, func() error {
+		return invokeRecoverable(callCtx, path, logger, func() error {
+			initCtx, cancel := initContext(callCtx, options)
+			defer cancel()
+			return wrapMiddleware(options, call)(initCtx, call.receiver, append([]string(nil), path...))
+		})
+	})
+	release()
+	if err != nil && restore != nil {
+		restore()
 	}
+	advanceProgress(ctx)
+	emitEvent(ctx, options, Event{
+		Phase:      PhaseInit,
+		Path:       path,
+		FieldIndex: index,
+		Type:       call.typeName,
+		Err:        err,
+		Duration:   time.Since(start),
+	})
 
-	// If value receiver, try again with the value itself
-	if v.Kind() != reflect.Ptr && v.CanInterface() {
-		// Check all three interfaces on the value
-		if initializer, ok := v.Interface().(ParentInitializer); ok {
-			if v.CanAddr() {
-				ptr := v.Addr()
-				if init, ok := ptr.Interface().(ParentInitializer); ok {
-					if err := init.Init(ctx, parentInterface); err != nil {
-						return &InitError{
-							Path:      path,
-							FieldType: v.Type().String(),
-							Cause:     err,
-						}
-					}
-					return nil
-				}
-			}
-			// Can't get address, call on value (won't persist changes)
-			if err := initializer.Init(ctx, parentInterface); err != nil {
-				return &InitError{
-					Path:      path,
-					FieldType: v.Type().String(),
-					Cause:     err,
-				}
-			}
-			return nil
+	if err != nil {
+		logger.WithLevel(errorLogLevel(options)).
+			Str("path", pathStr).
+			Err(err).
+			Msg(call.method + " failed")
+		// A failure once the run's context ended is reported as the interruption
+		if interrupted := interruption(ctx, path); interrupted != nil {
+			return interrupted
 		}
-
-		if initializer, ok := v.Interface().(ContextInitializer); ok {
-			if v.CanAddr() {
-				ptr := v.Addr()
-				if init, ok := ptr.Interface().(ContextInitializer); ok {
-					if err := init.Init(ctx); err != nil {
-						return &InitError{
-							Path:      path,
-							FieldType: v.Type().String(),
-							Cause:     err,
-						}
-					}
-					return nil
-				}
-			}
-			// Can't get address, call on value (won't persist changes)
-			if err := initializer.Init(ctx); err != nil {
-				return &InitError{
-					Path:      path,
-					FieldType: v.Type().String(),
-					Cause:     err,
-				}
-			}
-			return nil
+		return &InitError{
+			Path:       path,
+			FieldIndex: index,
+			FieldType:  call.typeName,
+			Cause:      err,
 		}
+	}
 
-		if initializer, ok := v.Interface().(SimpleInitializer); ok {
-			if v.CanAddr() {
-				ptr := v.Addr()
-				if init, ok := ptr.Interface().(SimpleInitializer); ok {
-					if err := init.Init(); err != nil {
-						return &InitError{
-							Path:      path,
-							FieldType: v.Type().String(),
-							Cause:     err,
-						}
-					}
-					return nil
-				}
-			}
-			// Can't get address, call on value (won't persist changes)
-			if err := initializer.Init(); err != nil {
-				return &InitError{
-					Path:      path,
-					FieldType: v.Type().String(),
-					Cause:     err,
-				}
-			}
-			return nil
+	if state := getRunState(ctx); state != nil {
+		// If the run's context ended while Init ran, the interruption reported
+		// at the next component names this one
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			state.interrupt(path, ctxErr)
 		}
+		state.markInitialized(v, path)
+	}
+	if options != nil && options.Report != nil {
+		options.Report.recordInitialized(path)
 	}
 
+	logger.WithLevel(stepLogLevel(options)).
+		Str("path", pathStr).
+		Msg(call.method + " completed successfully")
 	return nil
 }
 
 // callPreInit calls PreInit hook if the struct implements it
-func callPreInit(ctx context.Context, v reflect.Value, path []string, logger *zerolog.Logger) error {
-	return callInitHook(ctx, v, path, logger, "PreInit", func(ptr reflect.Value) error {
+func callPreInit(ctx context.Context, v reflect.Value, path []string, index int, logger *zerolog.Logger, options *Options) error {
+	if options != nil && options.DisablePreInit {
+		return nil
+	}
+	return callInitHook(ctx, v, path, index, logger, options, PhasePreInit, func(ptr reflect.Value) (bool, error) {
 		if preInit, ok := ptr.Interface().(PreInitializer); ok {
-			return preInit.PreInit(ctx)
+			return true, preInit.PreInit(ctx)
 		}
-		return nil
+		return false, nil
 	})
 }
 
 // callPostInit calls PostInit hook if the struct implements it
-func callPostInit(ctx context.Context, v reflect.Value, path []string, logger *zerolog.Logger) error {
-	return callInitHook(ctx, v, path, logger, "PostInit", func(ptr reflect.Value) error {
+func callPostInit(ctx context.Context, v reflect.Value, path []string, index int, logger *zerolog.Logger, options *Options) error {
+	if options != nil && options.DisablePostInit {
+		return nil
+	}
+	return callInitHook(ctx, v, path, index, logger, options, PhasePostInit, func(ptr reflect.Value) (bool, error) {
 		if postInit, ok := ptr.Interface().(PostInitializer); ok {
-			return postInit.PostInit(ctx)
+			return true, postInit.PostInit(ctx)
 		}
-		return nil
+		return false, nil
 	})
 }
 
-// callInitHook is a helper function to call initialization hooks
-func callInitHook(ctx context.Context, v reflect.Value, path []string, logger *zerolog.Logger, hookName string, hookFunc func(reflect.Value) error) error {
+// callInitHook is a helper function to call initialization hooks.
+// hookFunc reports whether the hook is implemented along with its error.
+func callInitHook(ctx context.Context, v reflect.Value, path []string, index int, logger *zerolog.Logger, options *Options, phase EventPhase, hookFunc func(reflect.Value) (bool, error)) error {
 	pathStr := pathToString(path)
+	hookName := string(phase)
 
 	// Get a pointer to the value if it's not already a pointer
 	ptr := v
@@ -641,47 +1525,112 @@ func callInitHook(ctx context.Context, v reflect.Value, path []string, logger *z
 		Str("type", ptr.Type().String()).
 		Msg("Calling " + hookName)
 
-	if err := hookFunc(ptr); err != nil {
-		logger.Error().
+	var called bool
+	start := time.Now()
+	err := protect(options, func() error {
+		var err error
+		called, err = hookFunc(ptr)
+		return err
+	})
+	// A hook only fails, or panics, once it has been called
+	called = called || err != nil
+	if called {
+		emitEvent(ctx, options, Event{
+			Phase:      phase,
+			Path:       path,
+			FieldIndex: index,
+			Type:       ptr.Type().String(),
+			Err:        err,
+			Duration:   time.Since(start),
+		})
+	}
+
+	if err != nil {
+		logger.WithLevel(errorLogLevel(options)).
 			Str("path", pathStr).
 			Err(err).
 			Msg(hookName + " failed")
 		return &InitError{
-			Path:      path,
-			FieldType: ptr.Type().String(),
-			Cause:     err,
+			Path:       path,
+			FieldIndex: index,
+			FieldType:  ptr.Type().String(),
+			Cause:      err,
 		}
 	}
 
-	logger.Trace().
+	// Hooks are looked up on every component; only report those that ran
+	level := zerolog.TraceLevel
+	if called {
+		level = stepLogLevel(options)
+	}
+	logger.WithLevel(level).
 		Str("path", pathStr).
 		Msg(hookName + " completed successfully")
 
 	return nil
 }
 
-// callPreFieldHook calls parent's PreFieldInit hook if it implements PreFieldHook
-func callPreFieldHook(ctx context.Context, parent reflect.Value, fieldName string, fieldValue reflect.Value, logger *zerolog.Logger) error {
-	return callFieldHook(ctx, parent, fieldName, fieldValue, logger, "PreFieldInit", func(hook interface{}, fieldInterface interface{}) error {
+// callPreFieldHook calls parent's PreFieldInit hook if it implements PreFieldHook,
+// then the matching Pre functions of its TypedFieldHooks, then its
+// PreFieldInitContext hook if it implements ContextMutatingPreFieldHook.
+// It returns the context to initialize the field with.
+func callPreFieldHook(ctx context.Context, parent reflect.Value, fieldName string, fieldValue reflect.Value, logger *zerolog.Logger, options *Options) (context.Context, error) {
+	if (options != nil && options.DisableFieldHooks) || !lifecycleActive(ctx, options) {
+		return ctx, nil
+	}
+	if err := callFieldHook(ctx, parent, fieldName, fieldValue, logger, options, "PreFieldInit", func(hook interface{}, fieldInterface interface{}) error {
 		if h, ok := hook.(PreFieldHook); ok {
 			return h.PreFieldInit(ctx, fieldName, fieldInterface)
 		}
 		return nil
+	}); err != nil {
+		return ctx, err
+	}
+	if err := callFieldHook(ctx, parent, fieldName, fieldValue, logger, options, "FieldHooks.Pre", func(hook interface{}, fieldInterface interface{}) error {
+		return callTypedFieldHooks(ctx, hook, fieldName, fieldInterface, false)
+	}); err != nil {
+		return ctx, err
+	}
+
+	fieldCtx := ctx
+	err := callFieldHook(ctx, parent, fieldName, fieldValue, logger, options, "PreFieldInitContext", func(hook interface{}, fieldInterface interface{}) error {
+		h, ok := hook.(ContextMutatingPreFieldHook)
+		if !ok {
+			return nil
+		}
+		childCtx, err := h.PreFieldInitContext(ctx, fieldName, fieldInterface)
+		if err == nil && childCtx != nil {
+			fieldCtx = childCtx
+		}
+		return err
 	})
+	return fieldCtx, err
 }
 
-// callPostFieldHook calls parent's PostFieldInit hook if it implements PostFieldHook
-func callPostFieldHook(ctx context.Context, parent reflect.Value, fieldName string, fieldValue reflect.Value, logger *zerolog.Logger) error {
-	return callFieldHook(ctx, parent, fieldName, fieldValue, logger, "PostFieldInit", func(hook interface{}, fieldInterface interface{}) error {
-		if h, ok := hook.(PostFieldHook); ok {
-			return h.PostFieldInit(ctx, fieldName, fieldInterface)
-		}
+// callPostFieldHook calls parent's PostFieldInit hook if it implements PostFieldHook,
+// then the matching Post functions of its TypedFieldHooks
+func callPostFieldHook(ctx context.Context, parent reflect.Value, fieldName string, fieldValue reflect.Value, logger *zerolog.Logger, options *Options) error {
+	if (options != nil && options.DisableFieldHooks) || !lifecycleActive(ctx, options) {
 		return nil
+	}
+	// In a layered run the hooks wait for the lifecycle of the component
+	return afterLayer(ctx, func() error {
+		if err := callFieldHook(ctx, parent, fieldName, fieldValue, logger, options, "PostFieldInit", func(hook interface{}, fieldInterface interface{}) error {
+			if h, ok := hook.(PostFieldHook); ok {
+				return h.PostFieldInit(ctx, fieldName, fieldInterface)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return callFieldHook(ctx, parent, fieldName, fieldValue, logger, options, "FieldHooks.Post", func(hook interface{}, fieldInterface interface{}) error {
+			return callTypedFieldHooks(ctx, hook, fieldName, fieldInterface, true)
+		})
 	})
 }
 
 // callFieldHook is a helper function to call field hooks
-func callFieldHook(ctx context.Context, parent reflect.Value, fieldName string, fieldValue reflect.Value, logger *zerolog.Logger, hookName string, hookFunc func(interface{}, interface{}) error) error {
+func callFieldHook(ctx context.Context, parent reflect.Value, fieldName string, fieldValue reflect.Value, logger *zerolog.Logger, options *Options, hookName string, hookFunc func(interface{}, interface{}) error) error {
 	if !parent.IsValid() {
 		return nil
 	}
@@ -717,8 +1666,8 @@ func callFieldHook(ctx context.Context, parent reflect.Value, fieldName string,
 		Str("field", fieldName).
 		Msg("Calling " + hookName + " hook")
 
-	if err := hookFunc(parentPtr.Interface(), fieldInterface); err != nil {
-		logger.Error().
+	if err := protect(options, func() error { return hookFunc(parentPtr.Interface(), fieldInterface) }); err != nil {
+		logger.WithLevel(errorLogLevel(options)).
 			Str("field", fieldName).
 			Err(err).
 			Msg(hookName + " hook failed")
@@ -736,3 +1685,22 @@ func callFieldHook(ctx context.Context, parent reflect.Value, fieldName string,
 func AutoInitWithOptions(ctx context.Context, target interface{}, options *Options) error {
 	return WithOptions(ctx, target, options)
 }
+
+// requiresSerialInit reports whether a field is declared as needing serial
+// initialization, either by tag or by implementing SerialInitializer
+func requiresSerialInit(field reflect.Value, tag tagOptions) bool {
+	if tag.has("serial") {
+		return true
+	}
+	if !field.CanInterface() {
+		return false
+	}
+	if _, ok := field.Interface().(SerialInitializer); ok {
+		return true
+	}
+	if field.Kind() != reflect.Ptr && field.CanAddr() {
+		_, ok := field.Addr().Interface().(SerialInitializer)
+		return ok
+	}
+	return false
+}
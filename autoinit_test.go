@@ -3,6 +3,7 @@ package autoinit
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -497,3 +498,229 @@ func TestInitializationOrder(t *testing.T) {
 		t.Error("Third field value changed unexpectedly")
 	}
 }
+
+// Test that the declaration index pinpoints which of several same-typed fields failed
+func TestInitErrorFieldIndex(t *testing.T) {
+	type MultiDB struct {
+		Name    string
+		First   *Database
+		Second  *Database
+		Third   *Database
+		Replica *Database
+	}
+
+	app := &MultiDB{
+		First:   &Database{},
+		Second:  &Database{},
+		Third:   &Database{ShouldFail: true},
+		Replica: &Database{},
+	}
+
+	var events []Event
+	err := WithOptions(context.Background(), app, &Options{
+		OnEvent: func(e Event) { events = append(events, e) },
+	})
+	if err == nil {
+		t.Fatal("expected error but got nil")
+	}
+
+	initErr, ok := err.(*InitError)
+	if !ok {
+		t.Fatalf("expected *InitError, got %T", err)
+	}
+	if initErr.GetFieldIndex() != 3 {
+		t.Errorf("FieldIndex = %d; want 3", initErr.GetFieldIndex())
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("got %d events; want 3", len(events))
+	}
+	for i, e := range events {
+		if e.FieldIndex != i+1 {
+			t.Errorf("event %d (%s) FieldIndex = %d; want %d", i, e.PathString(), e.FieldIndex, i+1)
+		}
+	}
+	if events[2].Err == nil {
+		t.Error("last event should carry the Init error")
+	}
+}
+
+// Test that root and collection element errors report the expected index
+func TestInitErrorFieldIndexRootAndCollections(t *testing.T) {
+	err := AutoInit(context.Background(), &Database{ShouldFail: true})
+	initErr, ok := err.(*InitError)
+	if !ok {
+		t.Fatalf("expected *InitError, got %T", err)
+	}
+	if initErr.FieldIndex != -1 {
+		t.Errorf("root FieldIndex = %d; want -1", initErr.FieldIndex)
+	}
+
+	app := &ComplexApp{
+		Services: []Service{
+			{Name: "service1", Database: &Database{ShouldFail: true}},
+		},
+	}
+	err = AutoInit(context.Background(), app)
+	initErr, ok = err.(*InitError)
+	if !ok {
+		t.Fatalf("expected *InitError, got %T", err)
+	}
+	// Database is the second field of Service
+	if initErr.FieldIndex != 1 {
+		t.Errorf("nested FieldIndex = %d; want 1", initErr.FieldIndex)
+	}
+}
+
+// Test initializing a struct passed by value
+func TestAutoInitValue(t *testing.T) {
+	original := ComplexApp{
+		Services: []Service{{Name: "service1", Database: &Database{}}},
+	}
+
+	app, err := AutoInitValue(context.Background(), original, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !app.Initialized {
+		t.Error("returned copy should be initialized")
+	}
+	if original.Initialized {
+		t.Error("original value should not be modified")
+	}
+	if !app.Services[0].Database.Connected {
+		t.Error("nested components should be initialized")
+	}
+
+	// Pointers are initialized in place
+	component := &SimpleComponent{}
+	same, err := AutoInitValue(context.Background(), component, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if same != component || !component.Initialized {
+		t.Error("pointer target should be initialized in place and returned")
+	}
+
+	if _, err := AutoInitValue(context.Background(), 42, nil); err == nil {
+		t.Error("expected error for non-struct value")
+	}
+}
+
+type ctxValueKey string
+
+// ctxValueReader copies seeded context values during Init
+type ctxValueReader struct {
+	Region string
+	Env    string
+}
+
+func (r *ctxValueReader) Init(ctx context.Context) error {
+	r.Region, _ = ctx.Value(ctxValueKey("region")).(string)
+	r.Env, _ = ctx.Value(ctxValueKey("env")).(string)
+	return nil
+}
+
+func TestContextValues(t *testing.T) {
+	type App struct {
+		Reader *ctxValueReader
+	}
+
+	ctx := context.WithValue(context.Background(), ctxValueKey("env"), "prod")
+	ctx = context.WithValue(ctx, ctxValueKey("region"), "us-east-1")
+
+	app := &App{Reader: &ctxValueReader{}}
+	err := WithOptions(ctx, app, &Options{
+		ContextValues: map[interface{}]interface{}{ctxValueKey("region"): "eu-west-1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Options values overlay the passed context; other values remain visible
+	if app.Reader.Region != "eu-west-1" || app.Reader.Env != "prod" {
+		t.Errorf("got region=%q env=%q", app.Reader.Region, app.Reader.Env)
+	}
+}
+
+func TestInitErrorRootCause(t *testing.T) {
+	root := errors.New("connection refused")
+	inner := &InitError{Path: []string{"DB"}, FieldType: "*Database", Cause: root}
+	wrapped := fmt.Errorf("subtree failed: %w", inner)
+	outer := &InitError{Path: []string{"Module"}, FieldType: "*Module", Cause: wrapped}
+
+	if got := outer.RootCause(); got != root {
+		t.Errorf("RootCause() = %v; want %v", got, root)
+	}
+	if got := inner.RootCause(); got != root {
+		t.Errorf("RootCause() of a single level = %v; want %v", got, root)
+	}
+	if !errors.Is(outer, root) {
+		t.Error("errors.Is should reach the root cause")
+	}
+	var found *InitError
+	if !errors.As(wrapped, &found) || found != inner {
+		t.Error("errors.As should find the nested InitError")
+	}
+}
+
+// errChainModule initializes its own subtree, hidden from the outer run,
+// with a nested AutoInit
+type errChainModule struct {
+	storage *errChainStorage
+}
+
+func (m *errChainModule) Init(ctx context.Context) error {
+	return AutoInit(ctx, m.storage)
+}
+
+type errChainStorage struct {
+	Primary *Database
+}
+
+func TestInitErrorChain(t *testing.T) {
+	type App struct {
+		Name   string
+		Module *errChainModule
+	}
+	app := &App{Module: &errChainModule{storage: &errChainStorage{Primary: &Database{ShouldFail: true}}}}
+
+	err := AutoInit(context.Background(), app)
+	var initErr *InitError
+	if !errors.As(err, &initErr) {
+		t.Fatalf("expected *InitError, got %T", err)
+	}
+	// The flat path stays that of the run that returned the error
+	if strings.Join(initErr.Path, ".") != "Module" {
+		t.Errorf("Path = %v; want [Module]", initErr.Path)
+	}
+
+	chain := initErr.Chain()
+	if len(chain) != 2 {
+		t.Fatalf("chain has %d links; want 2", len(chain))
+	}
+	if inner := chain[1]; strings.Join(inner.Path, ".") != "Primary" || inner.FieldType != "*autoinit.Database" {
+		t.Errorf("innermost link = %v %s", inner.Path, inner.FieldType)
+	}
+	if got := strings.Join(initErr.FullPath(), "."); got != "Module.Primary" {
+		t.Errorf("FullPath() = %s; want Module.Primary", got)
+	}
+	if !strings.Contains(err.Error(), "field 'Module.Primary' of type *autoinit.Database") {
+		t.Errorf("Error() = %q; want the full path of the innermost component", err.Error())
+	}
+
+	// A wrapped nested InitError gives the same path in the message as in FullPath
+	wrapped := &InitError{Path: []string{"Module"}, FieldType: "*errChainModule", Cause: fmt.Errorf("storage: %w", chain[1])}
+	if got := strings.Join(wrapped.FullPath(), "."); got != "Module.Primary" {
+		t.Errorf("wrapped FullPath() = %s; want Module.Primary", got)
+	}
+	if !strings.Contains(wrapped.Error(), "field 'Module.Primary' of type *autoinit.Database") {
+		t.Errorf("wrapped Error() = %q; want the full path of the innermost component", wrapped.Error())
+	}
+
+	// A flat error is a chain of one
+	single := &InitError{Path: []string{"DB"}, FieldType: "*Database", Cause: errors.New("boom")}
+	if len(single.Chain()) != 1 || strings.Join(single.FullPath(), ".") != "DB" {
+		t.Errorf("single error chain = %v, full path %v", single.Chain(), single.FullPath())
+	}
+}
@@ -0,0 +1,78 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// benchLeaf is a component without hooks
+type benchLeaf struct {
+	Name  string
+	Ready bool
+}
+
+func (l *benchLeaf) Init() error {
+	l.Ready = true
+	return nil
+}
+
+type benchModule struct {
+	Leaves []*benchLeaf
+	Config struct {
+		Host string
+		Port int
+	}
+}
+
+type benchApp struct {
+	Modules []*benchModule
+}
+
+// newBenchApp builds a hook-free tree of modules*leaves components
+func newBenchApp(modules, leaves int) *benchApp {
+	app := &benchApp{}
+	for i := 0; i < modules; i++ {
+		module := &benchModule{}
+		for j := 0; j < leaves; j++ {
+			module.Leaves = append(module.Leaves, &benchLeaf{Name: fmt.Sprintf("%d.%d", i, j)})
+		}
+		app.Modules = append(app.Modules, module)
+	}
+	return app
+}
+
+// BenchmarkHookFreeTree measures the overhead of hook lookups on a tree that
+// implements none, compared with disabling them:
+//
+//	go test -bench HookFreeTree -benchmem
+func BenchmarkHookFreeTree(b *testing.B) {
+	logger := zerolog.Nop()
+	cases := []struct {
+		name    string
+		options *Options
+	}{
+		{"hooks", &Options{Logger: &logger}},
+		{"hooks_disabled", &Options{
+			Logger:            &logger,
+			DisablePreInit:    true,
+			DisablePostInit:   true,
+			DisableFieldHooks: true,
+		}},
+	}
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				app := newBenchApp(10, 20)
+				b.StartTimer()
+				if err := WithOptions(context.Background(), app, c.options); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
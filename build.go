@@ -0,0 +1,298 @@
+package autoinit
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TypeKey is the key of a BuildTree description naming the registered type
+// of the component it describes. It is reserved: the leading @ keeps it from
+// matching an exported field, so a component may have a field named Type.
+const TypeKey = "@type"
+
+// typeRegistry holds the types registered with RegisterType, keyed by name
+var typeRegistry = struct {
+	sync.RWMutex
+	types map[string]reflect.Type
+}{types: make(map[string]reflect.Type)}
+
+// RegisterType registers struct type T under name, so that BuildTree can
+// construct it:
+//
+//	autoinit.RegisterType[Database]("database")
+//
+// The registry is process-wide; register types during program setup.
+// Registering a name again replaces the earlier type. RegisterType panics if
+// T is not a struct type or name is empty.
+func RegisterType[T any](name string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("autoinit: RegisterType requires a struct type, got %s", t))
+	}
+	if name == "" {
+		panic("autoinit: RegisterType requires a name")
+	}
+
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+	typeRegistry.types[name] = t
+}
+
+// registeredType returns the type registered under name
+func registeredType(name string) (reflect.Type, bool) {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+	t, ok := typeRegistry.types[name]
+	return t, ok
+}
+
+// isRegisteredType reports whether struct type t was registered under any name
+func isRegisteredType(t reflect.Type) bool {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+	for _, registered := range typeRegistry.types {
+		if registered == t {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildTree constructs a component tree from a declarative description, as
+// decoded from JSON or YAML into a map, and returns a pointer to its root,
+// ready to be passed to AutoInit:
+//
+//	{"@type": "app", "Name": "api", "Store": {"@type": "database", "DSN": "..."}}
+//
+// The TypeKey entry names the registered type (see RegisterType) of the
+// component described; it is required for the root and for interface fields,
+// whose registered type must implement the interface, and optional for struct
+// and pointer fields, where it must match the declared type. Other keys name
+// exported fields, matched case-insensitively. Values are assigned by kind:
+// scalars are converted to the field's type (whole numbers only for integer
+// fields, and only within the range of the type), lists fill slices and maps
+// with string keys fill maps, element by element.
+//
+// Only registered types are constructed: a description for a struct type
+// that was not registered is an error, so a description cannot instantiate
+// arbitrary types. Fields left out of the description keep their zero value.
+// AutoInit then initializes the tree by its usual rules, so a component
+// stored in a plain (non-embedded) interface field is built but not
+// initialized, while one in a slice or map of interfaces is.
+func BuildTree(description map[string]interface{}) (interface{}, error) {
+	name, ok := description[TypeKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("autoinit: build <root>: missing %q", TypeKey)
+	}
+	t, ok := registeredType(name)
+	if !ok {
+		return nil, fmt.Errorf("autoinit: build <root>: unregistered type %q", name)
+	}
+	root := reflect.New(t)
+	if err := buildStruct(root.Elem(), description, nil); err != nil {
+		return nil, err
+	}
+	return root.Interface(), nil
+}
+
+// buildStruct assigns the fields of struct v from description
+func buildStruct(v reflect.Value, description map[string]interface{}, path []string) error {
+	t := v.Type()
+	// Sorted so that the first error reported does not depend on map order
+	keys := make([]string, 0, len(description))
+	for key := range description {
+		if key != TypeKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := t.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, key)
+		})
+		if !ok || !field.IsExported() || len(field.Index) != 1 {
+			return fmt.Errorf("autoinit: build %s: %s has no exported field %q", pathToString(path), t, key)
+		}
+		fieldPath := append(append([]string(nil), path...), field.Name)
+		if err := buildValue(v.Field(field.Index[0]), description[key], fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildValue assigns value, taken from a description, to dst
+func buildValue(dst reflect.Value, value interface{}, path []string) error {
+	if value == nil {
+		return nil
+	}
+	fail := func(format string, args ...interface{}) error {
+		return fmt.Errorf("autoinit: build %s: %s", pathToString(path), fmt.Sprintf(format, args...))
+	}
+
+	switch dst.Kind() {
+	case reflect.Struct, reflect.Ptr, reflect.Interface:
+		description, ok := value.(map[string]interface{})
+		if !ok {
+			return fail("expected a description of %s, got %T", dst.Type(), value)
+		}
+		t, err := describedType(dst.Type(), description)
+		if err != nil {
+			return fail("%v", err)
+		}
+		component := reflect.New(t)
+		if err := buildStruct(component.Elem(), description, path); err != nil {
+			return err
+		}
+		if dst.Kind() == reflect.Struct {
+			dst.Set(component.Elem())
+		} else {
+			dst.Set(component)
+		}
+		return nil
+
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return fail("expected a list for %s, got %T", dst.Type(), value)
+		}
+		slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := buildValue(slice.Index(i), item, append(append([]string(nil), path...), fmt.Sprintf("[%d]", i))); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+		return nil
+
+	case reflect.Map:
+		entries, ok := value.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			return fail("expected a map with string keys for %s, got %T", dst.Type(), value)
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), len(entries))
+		for key, entry := range entries {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := buildValue(elem, entry, append(append([]string(nil), path...), fmt.Sprintf("[%s]", key))); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+		}
+		dst.Set(m)
+		return nil
+	}
+
+	src := reflect.ValueOf(value)
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// Decoded JSON numbers are float64
+		if src.Kind() == reflect.Float32 || src.Kind() == reflect.Float64 {
+			if f := src.Float(); f != math.Trunc(f) {
+				return fail("%v is not a whole number", value)
+			}
+		}
+	}
+	switch dst.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if (src.CanInt() && src.Int() < 0) || (src.CanFloat() && src.Float() < 0) {
+			return fail("%v is negative", value)
+		}
+	}
+	if !isScalarKind(src.Kind()) || !src.Type().ConvertibleTo(dst.Type()) ||
+		(src.Kind() == reflect.String) != (dst.Kind() == reflect.String) {
+		return fail("cannot assign %T to %s", value, dst.Type())
+	}
+	if overflows(dst, src) {
+		return fail("%v overflows %s", value, dst.Type())
+	}
+	dst.Set(src.Convert(dst.Type()))
+	return nil
+}
+
+// overflows reports whether the scalar src, already checked to be a whole
+// non-negative number where dst requires one, is out of the range of dst
+func overflows(dst, src reflect.Value) bool {
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch {
+		case src.CanInt():
+			return dst.OverflowInt(src.Int())
+		case src.CanUint():
+			return src.Uint() > math.MaxInt64 || dst.OverflowInt(int64(src.Uint()))
+		case src.CanFloat():
+			// Converting a float outside the int64 range is undefined
+			f := src.Float()
+			return f < math.MinInt64 || f >= -math.MinInt64 || dst.OverflowInt(int64(f))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch {
+		case src.CanInt():
+			return dst.OverflowUint(uint64(src.Int()))
+		case src.CanUint():
+			return dst.OverflowUint(src.Uint())
+		case src.CanFloat():
+			f := src.Float()
+			return f >= 2*float64(-math.MinInt64) || dst.OverflowUint(uint64(f))
+		}
+	case reflect.Float32, reflect.Float64:
+		if src.CanFloat() {
+			return dst.OverflowFloat(src.Float())
+		}
+	}
+	return false
+}
+
+// describedType returns the registered struct type a description builds for
+// a field of type declared
+func describedType(declared reflect.Type, description map[string]interface{}) (reflect.Type, error) {
+	want := declared
+	if want.Kind() == reflect.Ptr {
+		want = want.Elem()
+		if want.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("cannot build %s", declared)
+		}
+	}
+
+	name, named := description[TypeKey].(string)
+	if !named {
+		if want.Kind() == reflect.Interface {
+			return nil, fmt.Errorf("missing %q for interface %s", TypeKey, declared)
+		}
+		if want.Kind() != reflect.Struct || !isRegisteredType(want) {
+			return nil, fmt.Errorf("unregistered type %s", want)
+		}
+		return want, nil
+	}
+
+	t, ok := registeredType(name)
+	if !ok {
+		return nil, fmt.Errorf("unregistered type %q", name)
+	}
+	switch {
+	case want.Kind() == reflect.Interface:
+		if !reflect.PointerTo(t).Implements(want) {
+			return nil, fmt.Errorf("type %q (%s) does not implement %s", name, reflect.PointerTo(t), want)
+		}
+	case t != want:
+		return nil, fmt.Errorf("type %q (%s) does not match %s", name, t, declared)
+	}
+	return t, nil
+}
+
+// isScalarKind reports whether values of kind k are assigned by conversion
+func isScalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
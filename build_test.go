@@ -0,0 +1,171 @@
+package autoinit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type buildStore interface {
+	Name() string
+}
+
+type buildDatabase struct {
+	DSN         string
+	PoolSize    int
+	initialized bool
+}
+
+func (d *buildDatabase) Init() error {
+	d.initialized = true
+	return nil
+}
+
+func (d *buildDatabase) Name() string { return "database:" + d.DSN }
+
+type buildCache struct {
+	Size int
+}
+
+func (c *buildCache) Name() string { return "cache" }
+
+type buildApp struct {
+	Name    string
+	Type    string
+	Debug   bool
+	Primary *buildDatabase
+	Stores  []buildStore
+	Limits  map[string]float64
+	Cache   *buildCache
+}
+
+func init() {
+	RegisterType[buildApp]("app")
+	RegisterType[buildDatabase]("database")
+	RegisterType[buildCache]("cache")
+}
+
+func TestBuildTree(t *testing.T) {
+	var description map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"@type": "app",
+		"name": "api",
+		"type": "internal",
+		"Debug": true,
+		"Primary": {"@type": "database", "DSN": "postgres://primary", "PoolSize": 8},
+		"Stores": [{"@type": "cache", "Size": 64}, {"@type": "database", "DSN": "sqlite://"}],
+		"Limits": {"rps": 2.5},
+		"Cache": {"Size": 16}
+	}`), &description)
+	if err != nil {
+		t.Fatalf("invalid description: %v", err)
+	}
+
+	built, err := BuildTree(description)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	app, ok := built.(*buildApp)
+	if !ok {
+		t.Fatalf("BuildTree returned %T", built)
+	}
+	if app.Name != "api" || app.Type != "internal" || !app.Debug || app.Limits["rps"] != 2.5 || app.Cache.Size != 16 {
+		t.Errorf("unexpected fields: %+v", app)
+	}
+	primary := app.Primary
+	if primary == nil || primary.DSN != "postgres://primary" || primary.PoolSize != 8 {
+		t.Errorf("Primary = %#v", app.Primary)
+	}
+	if len(app.Stores) != 2 || app.Stores[0].Name() != "cache" || app.Stores[1].Name() != "database:sqlite://" {
+		t.Errorf("Stores = %v", app.Stores)
+	}
+
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !primary.initialized || !app.Stores[1].(*buildDatabase).initialized {
+		t.Error("built components were not initialized")
+	}
+}
+
+func TestBuildTreeErrors(t *testing.T) {
+	type unregistered struct{ Port int }
+	tests := []struct {
+		name        string
+		description map[string]interface{}
+		want        string
+	}{
+		{"missing root type", map[string]interface{}{"Name": "api"}, `missing "@type"`},
+		{"unregistered root", map[string]interface{}{"@type": "server"}, `unregistered type "server"`},
+		{"unknown field", map[string]interface{}{"@type": "app", "Port": 80}, `has no exported field "Port"`},
+		{"interface without type", map[string]interface{}{"@type": "app", "Stores": []interface{}{map[string]interface{}{}}}, `build Stores.[0]: missing "@type"`},
+		{"type not implementing", map[string]interface{}{"@type": "app", "Stores": []interface{}{map[string]interface{}{"@type": "app"}}}, "does not implement"},
+		{"mismatched type", map[string]interface{}{"@type": "app", "Cache": map[string]interface{}{"@type": "database"}}, "does not match"},
+		{"fractional integer", map[string]interface{}{"@type": "database", "PoolSize": 1.5}, "build PoolSize: 1.5 is not a whole number"},
+		{"wrong scalar", map[string]interface{}{"@type": "database", "DSN": 5}, "cannot assign int to string"},
+		{"nested error path", map[string]interface{}{"@type": "app", "Stores": []interface{}{
+			map[string]interface{}{"@type": "cache", "Size": "big"},
+		}}, "build Stores.[0].Size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildTree(tt.description)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// Only registered types are constructed
+	var app struct{ Config *unregistered }
+	err := buildStruct(reflect.ValueOf(&app).Elem(), map[string]interface{}{"Config": map[string]interface{}{"Port": 80}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "unregistered type") {
+		t.Errorf("expected unregistered type error, got %v", err)
+	}
+}
+
+func TestBuildTreeOverflow(t *testing.T) {
+	type limits struct {
+		Small int8
+		Byte  uint8
+		Count int
+		Ratio float32
+	}
+	tests := []struct {
+		field string
+		value interface{}
+		want  string
+	}{
+		{"Small", 127, ""},
+		{"Small", -128, ""},
+		{"Small", 128, "128 overflows int8"},
+		{"Small", -129, "-129 overflows int8"},
+		{"Small", 300.0, "300 overflows int8"},
+		{"Byte", 255, ""},
+		{"Byte", uint64(256), "256 overflows uint8"},
+		{"Byte", 256.0, "256 overflows uint8"},
+		{"Count", 1e30, "1e+30 overflows int"},
+		{"Count", -1e30, "-1e+30 overflows int"},
+		{"Count", uint64(1 << 63), "9223372036854775808 overflows int"},
+		{"Ratio", 3.4e38, ""},
+		{"Ratio", 1e39, "1e+39 overflows float32"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s=%v", tt.field, tt.value), func(t *testing.T) {
+			var got limits
+			err := buildStruct(reflect.ValueOf(&got).Elem(), map[string]interface{}{tt.field: tt.value}, nil)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v (%+v)", tt.want, err, got)
+			}
+		})
+	}
+}
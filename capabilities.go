@@ -0,0 +1,158 @@
+package autoinit
+
+import (
+	"io"
+	"reflect"
+)
+
+// Capabilities reports which lifecycle interfaces a component implements. A
+// method with the right name but the wrong signature does not count; see
+// CheckStruct to find such methods.
+type Capabilities struct {
+	SimpleInit                  bool // Init() error
+	ContextInit                 bool // Init(ctx) error
+	ParentInit                  bool // Init(ctx, parent) error
+	ReflectiveInit              bool // InitReflect(ctx, parent reflect.Value) error
+	Start                       bool // Start(ctx) error, called with Options.RecognizeConventions
+	PreInit                     bool // PreInit(ctx) error
+	PostInit                    bool // PostInit(ctx) error
+	PreFieldHook                bool // PreFieldInit(ctx, fieldName, fieldValue) error
+	ContextMutatingPreFieldHook bool // PreFieldInitContext(ctx, fieldName, fieldValue) (ctx, error)
+	PostFieldHook               bool // PostFieldInit(ctx, fieldName, fieldValue) error
+	TypedFieldHooks             bool // FieldHooks() []FieldHook
+	Link                        bool // Link(ctx, parent) error
+	Shutdown                    bool // Shutdown(ctx) error
+	Close                       bool // Close() error, called by AutoShutdown with Options.RecognizeConventions
+	Serial                      bool // SerialInitializer
+	Singleton                   bool // Singleton
+	ConfigValidator             bool // ConfigValidator
+	Identifiable                bool // Identifiable
+	Iterable                    bool // Iterable
+	DependencyDeclarer          bool // Requires() []Requirement
+}
+
+// capabilityInterfaces maps each capability to its name and interface, in the
+// order of the lifecycle
+var capabilityInterfaces = []struct {
+	name  string
+	iface reflect.Type
+	flag  func(*Capabilities) *bool
+}{
+	{"SimpleInitializer", reflect.TypeOf((*SimpleInitializer)(nil)).Elem(), func(c *Capabilities) *bool { return &c.SimpleInit }},
+	{"ContextInitializer", reflect.TypeOf((*ContextInitializer)(nil)).Elem(), func(c *Capabilities) *bool { return &c.ContextInit }},
+	{"ParentInitializer", reflect.TypeOf((*ParentInitializer)(nil)).Elem(), func(c *Capabilities) *bool { return &c.ParentInit }},
+	{"ReflectiveInitializer", reflect.TypeOf((*ReflectiveInitializer)(nil)).Elem(), func(c *Capabilities) *bool { return &c.ReflectiveInit }},
+	{"Starter", reflect.TypeOf((*Starter)(nil)).Elem(), func(c *Capabilities) *bool { return &c.Start }},
+	{"PreInitializer", reflect.TypeOf((*PreInitializer)(nil)).Elem(), func(c *Capabilities) *bool { return &c.PreInit }},
+	{"PostInitializer", reflect.TypeOf((*PostInitializer)(nil)).Elem(), func(c *Capabilities) *bool { return &c.PostInit }},
+	{"PreFieldHook", reflect.TypeOf((*PreFieldHook)(nil)).Elem(), func(c *Capabilities) *bool { return &c.PreFieldHook }},
+	{"ContextMutatingPreFieldHook", reflect.TypeOf((*ContextMutatingPreFieldHook)(nil)).Elem(), func(c *Capabilities) *bool { return &c.ContextMutatingPreFieldHook }},
+	{"PostFieldHook", reflect.TypeOf((*PostFieldHook)(nil)).Elem(), func(c *Capabilities) *bool { return &c.PostFieldHook }},
+	{"TypedFieldHooks", reflect.TypeOf((*TypedFieldHooks)(nil)).Elem(), func(c *Capabilities) *bool { return &c.TypedFieldHooks }},
+	{"Linker", reflect.TypeOf((*Linker)(nil)).Elem(), func(c *Capabilities) *bool { return &c.Link }},
+	{"Shutdowner", reflect.TypeOf((*Shutdowner)(nil)).Elem(), func(c *Capabilities) *bool { return &c.Shutdown }},
+	{"io.Closer", reflect.TypeOf((*io.Closer)(nil)).Elem(), func(c *Capabilities) *bool { return &c.Close }},
+	{"SerialInitializer", reflect.TypeOf((*SerialInitializer)(nil)).Elem(), func(c *Capabilities) *bool { return &c.Serial }},
+	{"Singleton", reflect.TypeOf((*Singleton)(nil)).Elem(), func(c *Capabilities) *bool { return &c.Singleton }},
+	{"ConfigValidator", reflect.TypeOf((*ConfigValidator)(nil)).Elem(), func(c *Capabilities) *bool { return &c.ConfigValidator }},
+	{"Identifiable", reflect.TypeOf((*Identifiable)(nil)).Elem(), func(c *Capabilities) *bool { return &c.Identifiable }},
+	{"Iterable", iterableType, func(c *Capabilities) *bool { return &c.Iterable }},
+	{"DependencyDeclarer", reflect.TypeOf((*DependencyDeclarer)(nil)).Elem(), func(c *Capabilities) *bool { return &c.DependencyDeclarer }},
+}
+
+// ComponentCapabilities returns the lifecycle interfaces implemented by
+// component, e.g. for documentation generators or to check that a component
+// implements what was intended:
+//
+//	caps := autoinit.ComponentCapabilities(&Server{})
+//	fmt.Println(caps.Initializer(false), caps.Names())
+//
+// component may be a struct, a pointer to one or a nil pointer of the type.
+// Methods with pointer receivers count for struct values too, as AutoInit
+// calls them on the addressable value.
+func ComponentCapabilities(component interface{}) Capabilities {
+	var caps Capabilities
+	t := reflect.TypeOf(component)
+	if t == nil {
+		return caps
+	}
+	if t.Kind() != reflect.Ptr {
+		t = reflect.PointerTo(t)
+	}
+	for _, c := range capabilityInterfaces {
+		*c.flag(&caps) = t.Implements(c.iface)
+	}
+	return caps
+}
+
+// Names returns the names of the implemented interfaces in lifecycle order
+func (c Capabilities) Names() []string {
+	var names []string
+	for _, capability := range capabilityInterfaces {
+		if *capability.flag(&c) {
+			names = append(names, capability.name)
+		}
+	}
+	return names
+}
+
+// Initializer returns the initializer AutoInit calls for the component, in
+// the form used in logs such as "Init(ctx)", or "" if there is none. Start(ctx)
+// is only reported if conventions is true (see Options.RecognizeConventions).
+func (c Capabilities) Initializer(conventions bool) string {
+	switch {
+	case c.ReflectiveInit:
+		return "InitReflect(ctx, parent)"
+	case c.ParentInit:
+		return "Init(ctx, parent)"
+	case c.ContextInit:
+		return "Init(ctx)"
+	case c.SimpleInit:
+		return "Init()"
+	case c.Start && conventions:
+		return "Start(ctx)"
+	default:
+		return ""
+	}
+}
+
+// Capability names a lifecycle interface the way Capabilities.Names does,
+// e.g. "Shutdowner" or "io.Closer", for capability-based discovery with
+// WithCapability and SearchOption.ByCapability
+type Capability string
+
+// Capabilities commonly searched for, e.g. to collect the components a
+// shutdown manager must stop. Any name returned by Capabilities.Names is a
+// valid Capability.
+const (
+	CapabilityShutdown     Capability = "Shutdowner"
+	CapabilityClose        Capability = "io.Closer"
+	CapabilityLink         Capability = "Linker"
+	CapabilityIdentifiable Capability = "Identifiable"
+	CapabilityIterable     Capability = "Iterable"
+)
+
+// implementsCapability reports whether component v implements the interface
+// named by c. As for ComponentCapabilities, methods with pointer receivers
+// count for struct values. An unknown capability is implemented by nothing.
+func implementsCapability(v reflect.Value, c Capability) bool {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return false
+	}
+	t := v.Type()
+	if t.Kind() != reflect.Ptr {
+		t = reflect.PointerTo(t)
+	}
+	for _, capability := range capabilityInterfaces {
+		if capability.name == string(c) {
+			return t.Implements(capability.iface)
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// capableComponent implements a selection of lifecycle interfaces
+type capableComponent struct {
+	Name string
+}
+
+func (c *capableComponent) Init(ctx context.Context) error                { return nil }
+func (c *capableComponent) PostInit(ctx context.Context) error            { return nil }
+func (c *capableComponent) Shutdown(ctx context.Context) error            { return nil }
+func (c *capableComponent) PreInit() error                                { return nil } // wrong signature
+func (c capableComponent) ComponentID() string                            { return c.Name }
+func (c *capableComponent) Start(ctx context.Context) error               { return nil }
+func (c *capableComponent) Link(ctx context.Context, p interface{}) error { return nil }
+
+func TestComponentCapabilities(t *testing.T) {
+	want := Capabilities{
+		ContextInit:  true,
+		Start:        true,
+		PostInit:     true,
+		Link:         true,
+		Shutdown:     true,
+		Identifiable: true,
+	}
+	for _, component := range []interface{}{&capableComponent{}, capableComponent{}, (*capableComponent)(nil)} {
+		if got := ComponentCapabilities(component); got != want {
+			t.Errorf("ComponentCapabilities(%T) = %+v, want %+v", component, got, want)
+		}
+	}
+
+	caps := ComponentCapabilities(&capableComponent{})
+	if names, want := caps.Names(), []string{"ContextInitializer", "Starter", "PostInitializer", "Linker", "Shutdowner", "Identifiable"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Names() = %v, want %v", names, want)
+	}
+	if got := caps.Initializer(false); got != "Init(ctx)" {
+		t.Errorf("Initializer = %q, want Init(ctx)", got)
+	}
+	if got := (Capabilities{Start: true}).Initializer(false); got != "" {
+		t.Errorf("Start should only count with conventions, got %q", got)
+	}
+	if got := ComponentCapabilities(nil); got != (Capabilities{}) {
+		t.Errorf("ComponentCapabilities(nil) = %+v", got)
+	}
+}
+
+// capabilityCloser can be closed but not shut down
+type capabilityCloser struct {
+	Name string
+}
+
+func (c *capabilityCloser) Close() error { return nil }
+
+func TestCapabilityDiscovery(t *testing.T) {
+	type App struct {
+		Config  *capabilityCloser
+		Plain   *capabilityCloser `json:"plain"`
+		Service *capableComponent
+		Workers []capableComponent
+		Missing *capableComponent
+		Self    *capableComponent
+	}
+	app := &App{
+		Config:  &capabilityCloser{Name: "config"},
+		Plain:   &capabilityCloser{Name: "plain"},
+		Service: &capableComponent{Name: "service"},
+		Workers: []capableComponent{{Name: "worker"}},
+		Self:    &capableComponent{Name: "self"},
+	}
+	ctx := context.Background()
+
+	// The finder collects every component that can be shut down, except self
+	finder := NewComponentFinder(ctx, app.Self, app)
+	var names []string
+	for _, found := range finder.FindAll(&SearchOption{ByCapability: CapabilityShutdown}) {
+		names = append(names, found.(*capableComponent).Name)
+	}
+	if !reflect.DeepEqual(names, []string{"service", "worker"}) {
+		t.Errorf("FindAll(Shutdowner) = %v, want [service worker]", names)
+	}
+
+	// WithCapability composes with the other filters of As
+	var closer *capabilityCloser
+	if !As(ctx, app.Self, app, &closer, WithCapability(CapabilityClose), WithJSONTag("plain")) || closer.Name != "plain" {
+		t.Errorf("As with Close capability found %v", closer)
+	}
+	var service *capableComponent
+	if As(ctx, app.Self, app, &service, WithCapability(CapabilityClose)) {
+		t.Errorf("As found %v, which cannot be closed", service.Name)
+	}
+	if As(ctx, app.Self, app, &closer, WithCapability("NoSuchInterface")) {
+		t.Error("an unknown capability should match nothing")
+	}
+}
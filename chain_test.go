@@ -0,0 +1,44 @@
+package autoinit
+
+import (
+	"context"
+	"testing"
+)
+
+// chainMiddleware links itself behind the previously initialized middleware
+type chainMiddleware struct {
+	Name string
+	Next *chainMiddleware `autoinit:"weak"`
+}
+
+func (m *chainMiddleware) Init(ctx context.Context) error {
+	if prev, ok := PreviousOfType(ctx, m); ok {
+		prev.Next = m
+	}
+	return nil
+}
+
+func TestPreviousOfType(t *testing.T) {
+	type App struct {
+		Auth    *chainMiddleware
+		Stack   []*chainMiddleware
+		Handler *chainMiddleware `autoinit:"priority=1"`
+	}
+
+	auth := &chainMiddleware{Name: "auth"}
+	logging := &chainMiddleware{Name: "logging"}
+	handler := &chainMiddleware{Name: "handler"}
+	app := &App{Auth: auth, Stack: []*chainMiddleware{logging}, Handler: handler}
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if auth.Next != logging || logging.Next != handler || handler.Next != nil {
+		t.Errorf("chain not assembled: auth.Next=%v logging.Next=%v handler.Next=%v", auth.Next, logging.Next, handler.Next)
+	}
+
+	// Outside a run there is no previous component
+	if _, ok := PreviousOfType(context.Background(), auth); ok {
+		t.Error("expected no previous component outside of a run")
+	}
+}
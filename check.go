@@ -0,0 +1,302 @@
+package autoinit
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CheckNonNil reports component fields that were left nil, catching the common
+// mistake of declaring a component field but forgetting to assign &X{} before
+// AutoInit, which would otherwise skip the component silently. A field is
+// reported if it is a nil pointer to a struct type that implements an
+// initializer (an Init method, or Start with options.RecognizeConventions).
+// Fields tagged `autoinit:"optional"` are allowed to be nil, and fields that
+// options.AllocateNilPointers would allocate are not reported.
+//
+// The tree is traversed with the same rules as AutoInit, so skipped fields are
+// not reported. Each problem is returned as a *NilFieldError, joined together.
+//
+//	if err := autoinit.CheckNonNil(app, nil); err != nil {
+//	    log.Fatal(err)
+//	}
+func CheckNonNil(target interface{}, options *Options) error {
+	v, err := resolveTarget(target, "check")
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if err := walkTree(v, options, func(node componentNode) error {
+		t := node.value.Type()
+		if !allowDescend(options, t) {
+			return nil
+		}
+		order, err := fieldOrder(t, options)
+		if err != nil {
+			return err
+		}
+		for _, i := range order {
+			field := node.value.Field(i)
+			fieldType := t.Field(i)
+			tag := parseTag(fieldType)
+			if fieldSkipReason(field, tag, options) != 0 || tag.has("optional") {
+				continue
+			}
+			if field.Kind() != reflect.Ptr || !field.IsNil() || !isComponentType(field.Type(), options) {
+				continue
+			}
+			if allocatesNil(field, tag, options) {
+				continue
+			}
+			errs = append(errs, &NilFieldError{
+				Path:      appendPath(node.path, fieldType.Name),
+				FieldType: field.Type().String(),
+			})
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// Issue is a potential wiring problem found by CheckStruct
+type Issue struct {
+	Path    []string // Path to the field from the checked type; empty for the type itself
+	Type    string   // Type the problem was found on
+	Message string   // Description of the problem
+}
+
+// String formats the issue with its path
+func (i Issue) String() string {
+	return fmt.Sprintf("%s (%s): %s", pathToString(i.Path), i.Type, i.Message)
+}
+
+// knownTagFlags and knownTagKeys are the options understood in autoinit tags
+var (
+	knownTagFlags = map[string]bool{"serial": true, "optional": true, "weak": true, "inject": true, "reinit": true}
+	knownTagKeys  = map[string]bool{"group": true, "priority": true, "timeout": true, "results": true, "phase": true, "feature": true, "resource": true, "inject": true}
+)
+
+// lifecycleMethods maps lifecycle method names to the interfaces a method of
+// that name must implement to be called by AutoInit
+var lifecycleMethods = map[string][]reflect.Type{
+	"Init": {
+		reflect.TypeOf((*SimpleInitializer)(nil)).Elem(),
+		reflect.TypeOf((*ContextInitializer)(nil)).Elem(),
+		reflect.TypeOf((*ParentInitializer)(nil)).Elem(),
+	},
+	"InitReflect":   {reflect.TypeOf((*ReflectiveInitializer)(nil)).Elem()},
+	"PreInit":       {reflect.TypeOf((*PreInitializer)(nil)).Elem()},
+	"PostInit":      {reflect.TypeOf((*PostInitializer)(nil)).Elem()},
+	"PreFieldInit":  {reflect.TypeOf((*PreFieldHook)(nil)).Elem()},
+	"PostFieldInit": {reflect.TypeOf((*PostFieldHook)(nil)).Elem()},
+	"FieldHooks":    {reflect.TypeOf((*TypedFieldHooks)(nil)).Elem()},
+	"Link":          {reflect.TypeOf((*Linker)(nil)).Elem()},
+	"Shutdown":      {reflect.TypeOf((*Shutdowner)(nil)).Elem()},
+	"Requires":      {reflect.TypeOf((*DependencyDeclarer)(nil)).Elem()},
+}
+
+// CheckStruct statically checks a struct type, and every struct type reachable
+// through its fields, for wiring mistakes that would otherwise only show at
+// runtime, if at all. No instance is needed, so it fits in a unit test:
+//
+//	for _, issue := range autoinit.CheckStruct(reflect.TypeOf(App{})) {
+//	    t.Error(issue)
+//	}
+//
+// It reports:
+//   - lifecycle methods (Init, PreInit, PostInit, Link, Shutdown, ...) whose
+//     signature does not match, so AutoInit silently never calls them
+//   - unknown autoinit tag options and invalid priority=, timeout= or group= values
+//   - autoinit tags on unexported fields, which are never traversed
+//   - sibling fields of the same component type, which make As without a
+//     filter ambiguous
+//
+// Fields are followed with AutoInit's default rules: "-" and weak fields, leaf
+// types and interface fields are not descended into. Each struct type is checked
+// once, at the first path it is reached by. t may be a struct type or a pointer
+// to one.
+func CheckStruct(t reflect.Type) []Issue {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return []Issue{{Type: t.String(), Message: "not a struct type"}}
+	}
+
+	var issues []Issue
+	checkStructType(t, []string{}, make(map[reflect.Type]bool), &issues)
+	return issues
+}
+
+// checkStructType appends the issues of struct type t, then recurses into its fields
+func checkStructType(t reflect.Type, path []string, checked map[reflect.Type]bool, issues *[]Issue) {
+	if checked[t] {
+		return
+	}
+	checked[t] = true
+
+	for _, issue := range lifecycleIssues(t) {
+		*issues = append(*issues, Issue{Path: path, Type: t.String(), Message: issue})
+	}
+
+	siblings := make(map[reflect.Type][]string)
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		fieldPath := appendPath(path, fieldType.Name)
+		tag := parseTag(fieldType)
+
+		if !fieldType.IsExported() {
+			if tag.present && !fieldType.Anonymous {
+				*issues = append(*issues, Issue{Path: fieldPath, Type: fieldType.Type.String(),
+					Message: "autoinit tag on an unexported field has no effect"})
+			}
+			continue
+		}
+		for _, issue := range tagIssues(tag) {
+			*issues = append(*issues, Issue{Path: fieldPath, Type: fieldType.Type.String(), Message: issue})
+		}
+		if componentStructType(fieldType.Type) != nil {
+			siblings[fieldType.Type] = append(siblings[fieldType.Type], fieldType.Name)
+		}
+		if tag.skip || tag.has("weak") || isInjected(tag) || isLeafType(nil, fieldType.Type) {
+			continue
+		}
+
+		elemPath := fieldPath
+		elem := fieldType.Type
+		for {
+			switch elem.Kind() {
+			case reflect.Ptr:
+				elem = elem.Elem()
+				continue
+			case reflect.Slice, reflect.Array, reflect.Map:
+				elem = elem.Elem()
+				elemPath = appendPath(elemPath, "[]")
+				continue
+			}
+			break
+		}
+		if elem.Kind() == reflect.Struct {
+			checkStructType(elem, elemPath, checked, issues)
+		}
+	}
+
+	// Report in declaration order of the first field of each ambiguous type
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		names := siblings[fieldType.Type]
+		if len(names) < 2 || names[0] != fieldType.Name {
+			continue
+		}
+		*issues = append(*issues, Issue{Path: path, Type: fieldType.Type.String(),
+			Message: fmt.Sprintf("fields %s share a type; As without a filter cannot tell them apart", strings.Join(names, ", "))})
+	}
+}
+
+// componentStructType returns the struct type of a struct or pointer-to-struct
+// field type that As can resolve, nil otherwise
+func componentStructType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || isLeafType(nil, t) {
+		return nil
+	}
+	return t
+}
+
+// lifecycleIssues reports lifecycle methods of struct type t with the wrong signature
+func lifecycleIssues(t reflect.Type) []string {
+	ptr := reflect.PointerTo(t)
+	var issues []string
+	for i := 0; i < ptr.NumMethod(); i++ {
+		method := ptr.Method(i)
+		expected, ok := lifecycleMethods[method.Name]
+		if !ok {
+			continue
+		}
+		implemented := false
+		for _, iface := range expected {
+			if ptr.Implements(iface) {
+				implemented = true
+				break
+			}
+		}
+		if !implemented {
+			issues = append(issues, fmt.Sprintf("method %s has signature %s, which AutoInit does not recognize, so it is never called",
+				method.Name, methodSignature(method.Type)))
+		}
+	}
+	return issues
+}
+
+// methodSignature formats a method type without its receiver
+func methodSignature(t reflect.Type) string {
+	in := make([]reflect.Type, 0, t.NumIn()-1)
+	for i := 1; i < t.NumIn(); i++ {
+		in = append(in, t.In(i))
+	}
+	out := make([]reflect.Type, 0, t.NumOut())
+	for i := 0; i < t.NumOut(); i++ {
+		out = append(out, t.Out(i))
+	}
+	return reflect.FuncOf(in, out, t.IsVariadic()).String()
+}
+
+// tagIssues reports unknown options and invalid values in a parsed tag
+func tagIssues(tag tagOptions) []string {
+	var issues []string
+	for _, option := range unknownTagOptions(tag) {
+		issues = append(issues, fmt.Sprintf("unknown autoinit tag option %q", option))
+	}
+	if _, err := tag.priority(); err != nil {
+		issues = append(issues, err.Error())
+	}
+	if _, err := tag.timeout(); err != nil {
+		issues = append(issues, err.Error())
+	}
+	if group, ok := tag.value("group"); ok && group == "" {
+		issues = append(issues, "empty autoinit group name")
+	}
+	if phase, ok := tag.value("phase"); ok && phase == "" {
+		issues = append(issues, "empty autoinit phase name")
+	}
+	if feature, ok := tag.value("feature"); ok && feature == "" {
+		issues = append(issues, "empty autoinit feature name")
+	}
+	if resource, ok := tag.value("resource"); ok && resource == "" {
+		issues = append(issues, "empty autoinit resource class")
+	}
+	return issues
+}
+
+// unknownTagOptions returns the options of a parsed tag AutoInit does not know
+func unknownTagOptions(tag tagOptions) []string {
+	var unknown []string
+	for _, flag := range sortedKeys(tag.flags) {
+		if !knownTagFlags[flag] {
+			unknown = append(unknown, flag)
+		}
+	}
+	for _, key := range sortedKeys(tag.values) {
+		if !knownTagKeys[key] {
+			unknown = append(unknown, key+"="+tag.values[key])
+		}
+	}
+	return unknown
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,139 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestCheckNonNil(t *testing.T) {
+	type Module struct {
+		Database *Database
+		Cache    *SimpleComponent `autoinit:"optional"`
+	}
+	type App struct {
+		Config   *SimpleComponent
+		Module   *Module
+		Excluded *SimpleComponent       `autoinit:"-"`
+		Plain    *struct{ Name string } // no Init
+		Services []*Service             // elements are not checked
+	}
+
+	err := CheckNonNil(&App{Module: &Module{}, Services: []*Service{nil}}, nil)
+	if err == nil {
+		t.Fatal("expected nil component fields to be reported")
+	}
+
+	var paths []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var nilErr *NilFieldError
+		if !errors.As(e, &nilErr) {
+			t.Fatalf("expected *NilFieldError, got %T", e)
+		}
+		paths = append(paths, pathToString(nilErr.Path))
+	}
+	if expected := []string{"Module.Database", "Config"}; !reflect.DeepEqual(paths, expected) {
+		t.Errorf("reported paths = %v; want %v", paths, expected)
+	}
+
+	complete := &App{Config: &SimpleComponent{}, Module: &Module{Database: &Database{}}}
+	if err := CheckNonNil(complete, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAllocateNilPointers(t *testing.T) {
+	type Module struct {
+		Database *Database
+		Cache    *SimpleComponent `autoinit:"optional"`
+	}
+	type App struct {
+		Config   *SimpleComponent
+		Module   *Module // no Init: not a component, left nil
+		Service  *Service
+		Excluded *SimpleComponent `autoinit:"-"`
+		Plain    *struct{ Name string }
+	}
+
+	logger := zerolog.Nop()
+	options := &Options{Logger: &logger, AllocateNilPointers: true}
+	app := &App{}
+	if err := CheckNonNil(app, options); err != nil {
+		t.Errorf("allocated fields should not be reported: %v", err)
+	}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if app.Config == nil || !app.Config.Initialized {
+		t.Error("Config should be allocated and initialized")
+	}
+	if app.Service == nil || !app.Service.Initialized || app.Service.Database == nil {
+		t.Error("Service and its database should be allocated and initialized")
+	}
+	if app.Module != nil || app.Excluded != nil || app.Plain != nil {
+		t.Error("non-component and excluded fields should stay nil")
+	}
+
+	// Component fields of non-component structs are allocated too
+	withModule := &App{Module: &Module{}}
+	if err := WithOptions(context.Background(), withModule, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if withModule.Module.Database == nil || !withModule.Module.Database.Connected {
+		t.Error("nested component should be allocated and initialized")
+	}
+	if withModule.Module.Cache != nil {
+		t.Error("optional field should stay nil")
+	}
+}
+
+// miswiredComponent has lifecycle methods AutoInit will never call
+type miswiredComponent struct {
+	Next *miswiredComponent // recursive types are checked once
+}
+
+func (m *miswiredComponent) Init(timeout int) error { return nil }
+
+func (m *miswiredComponent) PostInit() {}
+
+type miswiredApp struct {
+	Primary   *Database
+	Secondary *Database
+	Broken    *miswiredComponent   `autoinit:"serial"`
+	Typo      *SimpleComponent     `autoinit:"optinal,priority=high"`
+	Skipped   *miswiredComponent   `autoinit:"-"`
+	Workers   []*miswiredComponent `autoinit:"timeout=0s"`
+	hidden    *SimpleComponent     `autoinit:"init"`
+}
+
+func TestCheckStruct(t *testing.T) {
+	var messages []string
+	for _, issue := range CheckStruct(reflect.TypeOf(&miswiredApp{})) {
+		messages = append(messages, issue.String())
+	}
+
+	expected := []string{
+		`Broken (autoinit.miswiredComponent): method Init has signature func(int) error, which AutoInit does not recognize, so it is never called`,
+		`Broken (autoinit.miswiredComponent): method PostInit has signature func(), which AutoInit does not recognize, so it is never called`,
+		`Typo (*autoinit.SimpleComponent): unknown autoinit tag option "optinal"`,
+		`Typo (*autoinit.SimpleComponent): invalid autoinit priority "high": must be an integer`,
+		`Workers ([]*autoinit.miswiredComponent): invalid autoinit timeout "0s": must be a positive duration such as 5s`,
+		`hidden (*autoinit.SimpleComponent): autoinit tag on an unexported field has no effect`,
+		`<root> (*autoinit.Database): fields Primary, Secondary share a type; As without a filter cannot tell them apart`,
+		`<root> (*autoinit.miswiredComponent): fields Broken, Skipped share a type; As without a filter cannot tell them apart`,
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("issues =\n%q\nwant\n%q", messages, expected)
+	}
+
+	if issues := CheckStruct(reflect.TypeOf(SimpleComponent{})); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+	if issues := CheckStruct(reflect.TypeOf(0)); len(issues) != 1 {
+		t.Errorf("expected an issue for a non-struct type, got %v", issues)
+	}
+}
@@ -0,0 +1,72 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+)
+
+const componentOptionsKey contextKey = "autoinit:componentOptions"
+
+// componentOption is a value addressed to components of one type
+type componentOption struct {
+	target reflect.Type // Component type with any pointer stripped
+	value  interface{}
+}
+
+// WithComponentOption returns a context carrying opt for components of targetType,
+// so a single component can receive runtime parameters that are not part of its
+// struct. targetType may be the struct type or a pointer to it. The component
+// retrieves the value with ComponentOption during Init:
+//
+//	ctx = autoinit.WithComponentOption(ctx, reflect.TypeOf(&Database{}), DatabaseOptions{PoolSize: 20})
+//
+//	func (d *Database) Init(ctx context.Context) error {
+//	    if opts, ok := autoinit.ComponentOption[DatabaseOptions](ctx, d); ok {
+//	        d.poolSize = opts.PoolSize
+//	    }
+//	    return nil
+//	}
+//
+// A component type may receive several options of different types. Adding an
+// option of the same type again replaces the earlier one.
+func WithComponentOption(ctx context.Context, targetType reflect.Type, opt interface{}) context.Context {
+	existing, _ := ctx.Value(componentOptionsKey).([]componentOption)
+
+	// Copy so contexts derived earlier are not affected
+	options := make([]componentOption, len(existing), len(existing)+1)
+	copy(options, existing)
+	options = append(options, componentOption{target: derefType(targetType), value: opt})
+
+	return context.WithValue(ctx, componentOptionsKey, options)
+}
+
+// ComponentOption returns the option of type T addressed to self's type via
+// WithComponentOption. It returns false if there is none.
+func ComponentOption[T any](ctx context.Context, self interface{}) (T, bool) {
+	var zero T
+	if ctx == nil || self == nil {
+		return zero, false
+	}
+
+	options, _ := ctx.Value(componentOptionsKey).([]componentOption)
+	selfType := derefType(reflect.TypeOf(self))
+
+	// Later registrations take precedence
+	for i := len(options) - 1; i >= 0; i-- {
+		if options[i].target != selfType {
+			continue
+		}
+		if value, ok := options[i].value.(T); ok {
+			return value, true
+		}
+	}
+	return zero, false
+}
+
+// derefType strips one level of pointer from t
+func derefType(t reflect.Type) reflect.Type {
+	if t != nil && t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,56 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type poolOptions struct {
+	Size int
+}
+
+// pooledComponent reads its pool size from a component option
+type pooledComponent struct {
+	PoolSize int
+}
+
+func (p *pooledComponent) Init(ctx context.Context) error {
+	if opts, ok := ComponentOption[poolOptions](ctx, p); ok {
+		p.PoolSize = opts.Size
+	}
+	return nil
+}
+
+func TestComponentOption(t *testing.T) {
+	type App struct {
+		Pool  *pooledComponent
+		Other *SimpleComponent
+	}
+
+	ctx := WithComponentOption(context.Background(), reflect.TypeOf(pooledComponent{}), poolOptions{Size: 10})
+	ctx = WithComponentOption(ctx, reflect.TypeOf(&SimpleComponent{}), poolOptions{Size: 99})
+	// A later option of the same type replaces the earlier one
+	overridden := WithComponentOption(ctx, reflect.TypeOf(&pooledComponent{}), poolOptions{Size: 20})
+
+	logger := zerolog.Nop()
+	app := &App{Pool: &pooledComponent{}, Other: &SimpleComponent{}}
+	if err := WithOptions(ctx, app, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Pool.PoolSize != 10 {
+		t.Errorf("PoolSize = %d; want 10", app.Pool.PoolSize)
+	}
+
+	if opts, ok := ComponentOption[poolOptions](overridden, &pooledComponent{}); !ok || opts.Size != 20 {
+		t.Errorf("expected overridden option, got %+v (ok=%v)", opts, ok)
+	}
+	if _, ok := ComponentOption[string](ctx, &pooledComponent{}); ok {
+		t.Error("options of another type should not match")
+	}
+	if _, ok := ComponentOption[poolOptions](context.Background(), &pooledComponent{}); ok {
+		t.Error("expected no option without WithComponentOption")
+	}
+}
@@ -0,0 +1,114 @@
+package autoinit
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConfigValidator is implemented by config-driven components that want AutoInit
+// to verify their configuration survives initialization unchanged, catching
+// fields that Init (or a child's Init, through a shared pointer) silently
+// overwrites. ValidatedConfig returns the configuration to check, typically a
+// pointer to the struct it was loaded into, and the function serializing it:
+//
+//	func (a *App) ValidatedConfig() (interface{}, func(interface{}) ([]byte, error)) {
+//	    return &a.Config, yaml.Marshal
+//	}
+//
+// The configuration is serialized when the component's lifecycle starts and
+// again after its PostInit; if the results differ, the component fails with a
+// *ConfigDriftError listing the changed lines. Serialization errors fail it too.
+type ConfigValidator interface {
+	ValidatedConfig() (config interface{}, marshal func(interface{}) ([]byte, error))
+}
+
+// ConfigDriftError reports configuration that changed during initialization
+type ConfigDriftError struct {
+	// Diff lists the serialized lines removed ("- ") and added ("+ ")
+	Diff []string
+}
+
+// Error implements the error interface
+func (e *ConfigDriftError) Error() string {
+	return "configuration changed during initialization:\n" + strings.Join(e.Diff, "\n")
+}
+
+// configSnapshot is the serialized configuration of a ConfigValidator taken
+// before its lifecycle
+type configSnapshot struct {
+	config  interface{}
+	marshal func(interface{}) ([]byte, error)
+	before  []byte
+}
+
+// snapshotConfig serializes the configuration of struct v if it implements
+// ConfigValidator; it returns nil otherwise
+func snapshotConfig(v reflect.Value) (*configSnapshot, error) {
+	ptr := v
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		ptr = v.Addr()
+	}
+	if !ptr.CanInterface() {
+		return nil, nil
+	}
+	validator, ok := ptr.Interface().(ConfigValidator)
+	if !ok {
+		return nil, nil
+	}
+	config, marshal := validator.ValidatedConfig()
+	if marshal == nil {
+		return nil, nil
+	}
+	before, err := marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("serialize config: %w", err)
+	}
+	return &configSnapshot{config: config, marshal: marshal, before: before}, nil
+}
+
+// verify serializes the configuration again and compares it with the snapshot
+func (s *configSnapshot) verify() error {
+	if s == nil {
+		return nil
+	}
+	after, err := s.marshal(s.config)
+	if err != nil {
+		return fmt.Errorf("serialize config: %w", err)
+	}
+	if bytes.Equal(s.before, after) {
+		return nil
+	}
+	return &ConfigDriftError{Diff: diffLines(string(s.before), string(after))}
+}
+
+// diffLines returns the lines of before missing from after, prefixed "- ",
+// followed by the lines of after missing from before, prefixed "+ "
+func diffLines(before, after string) []string {
+	count := func(text string) map[string]int {
+		counts := make(map[string]int)
+		for _, line := range strings.Split(text, "\n") {
+			counts[line]++
+		}
+		return counts
+	}
+	inBefore, inAfter := count(before), count(after)
+
+	var diff []string
+	for _, line := range strings.Split(before, "\n") {
+		if inAfter[line] > 0 {
+			inAfter[line]--
+			continue
+		}
+		diff = append(diff, "- "+line)
+	}
+	for _, line := range strings.Split(after, "\n") {
+		if inBefore[line] > 0 {
+			inBefore[line]--
+			continue
+		}
+		diff = append(diff, "+ "+line)
+	}
+	return diff
+}
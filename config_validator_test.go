@@ -0,0 +1,57 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type validatedServerConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+// validatedListener applies a default port, overwriting the loaded configuration
+type validatedListener struct {
+	Config *validatedServerConfig
+}
+
+func (l *validatedListener) Init() error {
+	if l.Config.Port == 8080 {
+		l.Config.Port = 9090
+	}
+	return nil
+}
+
+type validatedApp struct {
+	Config   validatedServerConfig
+	Listener *validatedListener
+}
+
+func (a *validatedApp) ValidatedConfig() (interface{}, func(interface{}) ([]byte, error)) {
+	return &a.Config, yaml.Marshal
+}
+
+func TestConfigValidator(t *testing.T) {
+	// Init leaves the configuration alone
+	app := &validatedApp{Config: validatedServerConfig{Host: "localhost", Port: 80}}
+	app.Listener = &validatedListener{Config: &app.Config}
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A child overwrites a loaded field through a shared pointer
+	app = &validatedApp{Config: validatedServerConfig{Host: "localhost", Port: 8080}}
+	app.Listener = &validatedListener{Config: &app.Config}
+	err := AutoInit(context.Background(), app)
+	var drift *ConfigDriftError
+	if !errors.As(err, &drift) {
+		t.Fatalf("expected a ConfigDriftError, got %v", err)
+	}
+	if want := []string{"- port: 8080", "+ port: 9090"}; !reflect.DeepEqual(drift.Diff, want) {
+		t.Errorf("Diff = %q, want %q", drift.Diff, want)
+	}
+}
@@ -0,0 +1,73 @@
+package autoinit
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// constructorRegistry holds the constructors registered with RegisterConstructor,
+// keyed by interface type
+var constructorRegistry = struct {
+	sync.RWMutex
+	constructors map[reflect.Type]func() interface{}
+}{constructors: make(map[reflect.Type]func() interface{})}
+
+// RegisterConstructor registers the default implementation of interface I. With
+// Options.AllocateNilPointers set, AutoInit fills nil fields declared as I with
+// the value constructor returns and initializes it like any other component, so
+// a struct can declare `Store DataStore` without assigning it:
+//
+//	autoinit.RegisterConstructor[DataStore](func() DataStore { return &MemoryStore{} })
+//
+// Constructors are matched by the exact declared type of the field: a constructor
+// for I is never used for a field of another interface, even one I's values
+// implement, so at most one constructor applies to a field and there is nothing
+// to resolve. Registering a constructor for the same I again replaces the
+// earlier one. Fields tagged `autoinit:"-"` or `autoinit:"optional"` are left
+// nil, as are fields whose constructor returns nil.
+//
+// Constructed values are initialized once, when they are built. Tree walks such
+// as AutoShutdown do not descend into interface fields, so shut them down from
+// the owning component if needed.
+//
+// The registry is process-wide; register constructors during program setup.
+// RegisterConstructor panics if I is not an interface type.
+func RegisterConstructor[I any](constructor func() I) {
+	t := reflect.TypeOf((*I)(nil)).Elem()
+	if t.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("autoinit: RegisterConstructor requires an interface type, got %s", t))
+	}
+
+	constructorRegistry.Lock()
+	defer constructorRegistry.Unlock()
+	constructorRegistry.constructors[t] = func() interface{} {
+		return constructor()
+	}
+}
+
+// constructorFor returns the constructor registered for interface type t
+func constructorFor(t reflect.Type) (func() interface{}, bool) {
+	constructorRegistry.RLock()
+	defer constructorRegistry.RUnlock()
+	constructor, ok := constructorRegistry.constructors[t]
+	return constructor, ok
+}
+
+// constructNil fills the nil interface field with the value of its registered
+// constructor under Options.AllocateNilPointers. It reports whether it did.
+func constructNil(field reflect.Value, tag tagOptions, options *Options) bool {
+	if options == nil || !options.AllocateNilPointers || !field.CanSet() || tag.has("optional") {
+		return false
+	}
+	constructor, ok := constructorFor(field.Type())
+	if !ok {
+		return false
+	}
+	value := reflect.ValueOf(constructor())
+	if !value.IsValid() || isNilValue(value) {
+		return false
+	}
+	field.Set(value)
+	return true
+}
@@ -0,0 +1,73 @@
+package autoinit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type dataStore interface {
+	Get(key string) string
+}
+
+// memoryStore is the registered default dataStore
+type memoryStore struct {
+	Initialized bool
+}
+
+func (m *memoryStore) Init() error {
+	m.Initialized = true
+	return nil
+}
+
+func (m *memoryStore) Get(key string) string { return key }
+
+type unregisteredStore interface {
+	Put(key string)
+}
+
+func TestRegisterConstructor(t *testing.T) {
+	RegisterConstructor[dataStore](func() dataStore { return &memoryStore{} })
+
+	type App struct {
+		Store    dataStore
+		Optional dataStore `autoinit:"optional"`
+		Other    unregisteredStore
+		Assigned dataStore
+	}
+
+	logger := zerolog.Nop()
+	assigned := &memoryStore{}
+	app := &App{Assigned: assigned}
+	if err := WithOptions(context.Background(), app, &Options{Logger: &logger, AllocateNilPointers: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, ok := app.Store.(*memoryStore)
+	if !ok || !store.Initialized {
+		t.Fatalf("Store should be constructed and initialized, got %#v", app.Store)
+	}
+	if app.Optional != nil || app.Other != nil {
+		t.Error("optional and unregistered interface fields should stay nil")
+	}
+	if app.Assigned != assigned || assigned.Initialized {
+		t.Error("assigned interface fields should be left alone")
+	}
+
+	// Constructors only apply with AllocateNilPointers
+	plain := &App{}
+	if err := WithOptions(context.Background(), plain, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plain.Store != nil {
+		t.Error("Store should stay nil without AllocateNilPointers")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a non-interface type")
+		}
+	}()
+	RegisterConstructor[*memoryStore](func() *memoryStore { return nil })
+}
@@ -0,0 +1,105 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// lifecycleRecorder logs its lifecycle calls and fails on request
+type lifecycleRecorder struct {
+	Name        string
+	Child       *lifecycleRecorder
+	FailPreInit bool
+	FailInit    bool
+	log         *[]string
+}
+
+func (r *lifecycleRecorder) PreInit(ctx context.Context) error {
+	*r.log = append(*r.log, "pre "+r.Name)
+	if r.FailPreInit {
+		return errors.New("pre-init failed")
+	}
+	return nil
+}
+
+func (r *lifecycleRecorder) Init() error {
+	*r.log = append(*r.log, "init "+r.Name)
+	if r.FailInit {
+		return errors.New("init failed")
+	}
+	return nil
+}
+
+func (r *lifecycleRecorder) PostInit(ctx context.Context) error {
+	*r.log = append(*r.log, "post "+r.Name)
+	return nil
+}
+
+type continueApp struct {
+	Parent  *lifecycleRecorder
+	Guarded *lifecycleRecorder
+	Sibling *lifecycleRecorder
+	log     *[]string
+}
+
+func (a *continueApp) Init() error {
+	*a.log = append(*a.log, "init app")
+	return nil
+}
+
+func TestContinueOnError(t *testing.T) {
+	var log []string
+	app := &continueApp{
+		// Child succeeds, parent Init fails
+		Parent: &lifecycleRecorder{Name: "parent", FailInit: true, log: &log,
+			Child: &lifecycleRecorder{Name: "child", log: &log}},
+		// PreInit fails, so the child is never reached
+		Guarded: &lifecycleRecorder{Name: "guarded", FailPreInit: true, log: &log,
+			Child: &lifecycleRecorder{Name: "unreached", log: &log}},
+		Sibling: &lifecycleRecorder{Name: "sibling", log: &log},
+		log:     &log,
+	}
+
+	logger := zerolog.Nop()
+	err := WithOptions(context.Background(), app, &Options{Logger: &logger, ContinueOnError: true})
+	if err == nil {
+		t.Fatal("expected joined errors")
+	}
+
+	expected := []string{
+		"pre parent", "pre child", "init child", "post child", "init parent", // no "post parent"
+		"pre guarded", // no fields, Init or PostInit
+		"pre sibling", "init sibling", "post sibling",
+		"init app",
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("calls = %v\nwant    %v", log, expected)
+	}
+
+	var failedPaths []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var initErr *InitError
+		if !errors.As(e, &initErr) {
+			t.Fatalf("expected *InitError, got %T", e)
+		}
+		failedPaths = append(failedPaths, pathToString(initErr.Path))
+	}
+	if !reflect.DeepEqual(failedPaths, []string{"Parent", "Guarded"}) {
+		t.Errorf("failed paths = %v", failedPaths)
+	}
+
+	// Without the option the first failure stops traversal
+	log = nil
+	err = WithOptions(context.Background(), app, &Options{Logger: &logger})
+	var initErr *InitError
+	if !errors.As(err, &initErr) || pathToString(initErr.Path) != "Parent" {
+		t.Errorf("expected fail-fast error for Parent, got %v", err)
+	}
+	if log[len(log)-1] != "init parent" {
+		t.Errorf("expected traversal to stop at the parent, got %v", log)
+	}
+}
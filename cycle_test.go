@@ -2,7 +2,9 @@ package autoinit
 
 import (
 	"context"
+	"reflect"
 	"testing"
+	"time"
 )
 
 // Test types for cycle detection
@@ -233,3 +235,53 @@ func TestMapWithCycle(t *testing.T) {
 	t.Logf("Map with cycle initialization complete - cycle detection prevented infinite loop")
 	t.Logf("NodeA: %d init(s), NodeB: %d init(s)", nodeA.InitCount, nodeB.InitCount)
 }
+
+// MapValueNode holds struct values in a map that can contain the node itself
+type MapValueNode struct {
+	Name     string
+	Children map[string]MapValueNode
+	initLog  *[]string
+}
+
+func (n *MapValueNode) Init() error {
+	*n.initLog = append(*n.initLog, n.Name)
+	return nil
+}
+
+func TestMapStructValueCycle(t *testing.T) {
+	var initLog []string
+	root := &MapValueNode{Name: "root", Children: map[string]MapValueNode{}, initLog: &initLog}
+	// The stored copy shares root's map, so traversing it reaches the same map again
+	root.Children["self"] = *root
+	root.Children["leaf"] = MapValueNode{Name: "leaf", initLog: &initLog}
+
+	done := make(chan error, 1)
+	go func() { done <- AutoInit(context.Background(), root) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AutoInit did not terminate on a cycle through map struct values")
+	}
+
+	// Each map entry is initialized once, followed by the root
+	expected := []string{"leaf", "root", "root"}
+	if !reflect.DeepEqual(initLog, expected) {
+		t.Errorf("init log = %v; want %v", initLog, expected)
+	}
+
+	// Tree walks (AutoShutdown, ReInit) stop at the cycle too
+	var walked []string
+	if err := walkTree(reflect.ValueOf(root), nil, func(node componentNode) error {
+		walked = append(walked, pathToString(node.path))
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected walk error: %v", err)
+	}
+	if expected := []string{"Children.[leaf]", "Children.[self]", "<root>"}; !reflect.DeepEqual(walked, expected) {
+		t.Errorf("walked = %v; want %v", walked, expected)
+	}
+}
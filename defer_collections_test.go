@@ -0,0 +1,76 @@
+package autoinit
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type deferComponent struct {
+	Name  string
+	order *[]string
+}
+
+func (c *deferComponent) Init() error {
+	*c.order = append(*c.order, c.Name)
+	return nil
+}
+
+type deferApp struct {
+	Workers  []*deferComponent
+	Database *deferComponent
+	Handlers map[string]*deferComponent
+	Cache    *deferComponent
+	Urgent   []*deferComponent `autoinit:"priority=-1"`
+	Metrics  *deferComponent   `autoinit:"priority=1"`
+}
+
+func newDeferApp(order *[]string) *deferApp {
+	component := func(name string) *deferComponent {
+		return &deferComponent{Name: name, order: order}
+	}
+	return &deferApp{
+		Workers:  []*deferComponent{component("worker1"), component("worker2")},
+		Database: component("database"),
+		Handlers: map[string]*deferComponent{"a": component("handlerA")},
+		Cache:    component("cache"),
+		Urgent:   []*deferComponent{component("urgent")},
+		Metrics:  component("metrics"),
+	}
+}
+
+func TestDeferCollections(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *Options
+		want    string
+	}{
+		{
+			name:    "collections inline by default",
+			options: &Options{},
+			want:    "urgent,worker1,worker2,database,handlerA,cache,metrics",
+		},
+		{
+			name:    "collections after the other fields",
+			options: &Options{DeferCollections: true},
+			want:    "database,cache,metrics,urgent,worker1,worker2,handlerA",
+		},
+		{
+			name:    "both passes reversed",
+			options: &Options{DeferCollections: true, ReverseFieldOrder: true},
+			want:    "cache,database,metrics,urgent,handlerA,worker1,worker2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order []string
+			if err := WithOptions(context.Background(), newDeferApp(&order), tt.options); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Join(order, ","); got != tt.want {
+				t.Errorf("order = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
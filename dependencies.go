@@ -0,0 +1,227 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Dependency records that a component found another one during a run, with
+// As, a ComponentFinder, FindInMap or an inject field
+type Dependency struct {
+	From     []string // Path of the component that searched, nil if it is not in the tree
+	FromType string   // Type of the component that searched
+	To       []string // Path of the component found, nil if it is not in the tree, e.g. in an additional root
+	ToType   string   // Type of the component found
+	// Via describes the search, e.g. "As(*app.Database, field=PrimaryDB)"
+	Via string
+}
+
+// FromString returns the searching component's path in dot-separated form
+func (d Dependency) FromString() string {
+	return dependencyPath(d.From)
+}
+
+// ToString returns the found component's path in dot-separated form
+func (d Dependency) ToString() string {
+	return dependencyPath(d.To)
+}
+
+// String describes the dependency, e.g.
+// "Auth -> Storage.Primary (*app.Database) via As(*app.Database)"
+func (d Dependency) String() string {
+	return fmt.Sprintf("%s -> %s (%s) via %s", d.FromString(), d.ToString(), d.ToType, d.Via)
+}
+
+// dependencyPath formats the path of a dependency end, "<outside>" if it is
+// not in the tree
+func dependencyPath(path []string) string {
+	if path == nil {
+		return "<outside>"
+	}
+	return pathToString(path)
+}
+
+// DependenciesOf returns the dependencies found by the component at the given
+// dot-separated path ("<root>" for the target), in the order they were found
+func (r *InitReport) DependenciesOf(path string) []Dependency {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deps []Dependency
+	for _, d := range r.Dependencies {
+		if d.From != nil && pathToString(d.From) == path {
+			deps = append(deps, d)
+		}
+	}
+	return deps
+}
+
+// pendingDependency is a dependency whose ends are identified by their
+// component keys until the run resolves them to paths
+type pendingDependency struct {
+	dependency Dependency
+	from, to   componentKey
+}
+
+// recordDiscovery records in the report of the run on ctx, if any, that self
+// found the component found via the search described by via
+func recordDiscovery(ctx context.Context, self interface{}, found reflect.Value, via string) {
+	sink := reportSinkOf(ctx)
+	if sink == nil || self == nil || !found.IsValid() {
+		return
+	}
+	for found.Kind() == reflect.Interface && !found.IsNil() {
+		found = found.Elem()
+	}
+	from, _ := keyOf(reflect.ValueOf(self))
+	to, _ := keyOf(found)
+
+	r := sink.report
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = append(r.pending, pendingDependency{
+		dependency: Dependency{
+			FromType: reflect.TypeOf(self).String(),
+			ToType:   found.Type().String(),
+			Via:      via,
+		},
+		from: from,
+		to:   to,
+	})
+}
+
+// componentPaths maps the components of the tree rooted at v, and the values
+// of their interface fields, to their paths, the first path each is reached by
+func componentPaths(v reflect.Value, options *Options) map[componentKey][]string {
+	paths := make(map[componentKey][]string)
+	add := func(value reflect.Value, path []string) {
+		if key, ok := keyOf(value); ok {
+			if _, seen := paths[key]; !seen {
+				paths[key] = append([]string{}, path...)
+			}
+		}
+	}
+	_ = walkTree(v, options, func(node componentNode) error {
+		add(node.value, node.path)
+
+		// Interface fields are not traversed, but As and the finders return
+		// what they hold
+		s := node.value
+		for (s.Kind() == reflect.Ptr || s.Kind() == reflect.Interface) && !s.IsNil() {
+			s = s.Elem()
+		}
+		if s.Kind() != reflect.Struct {
+			return nil
+		}
+		for i := 0; i < s.NumField(); i++ {
+			field, fieldType := s.Field(i), s.Type().Field(i)
+			if fieldType.IsExported() && !fieldType.Anonymous && field.Kind() == reflect.Interface && !field.IsNil() {
+				add(field.Elem(), appendPath(node.path, fieldType.Name))
+			}
+		}
+		return nil
+	})
+	return paths
+}
+
+// resolveDependencies resolves the ends of the pending dependencies to their
+// paths and adds them to Dependencies. declared maps the components of the
+// tree as it was before the run. Components created during the run are looked
+// up in the tree rooted at v as it is now, except below the component that
+// found them, where they were only stored as a reference. Ends outside the tree
+// are left nil.
+func (r *InitReport) resolveDependencies(v reflect.Value, options *Options, declared map[componentKey][]string) {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	var current map[componentKey][]string
+	resolve := func(key componentKey, below []string) []string {
+		if path, ok := declared[key]; ok {
+			return path
+		}
+		if current == nil {
+			current = componentPaths(v, options)
+		}
+		path, ok := current[key]
+		if !ok || (below != nil && hasPathPrefix(path, below)) {
+			return nil
+		}
+		return path
+	}
+
+	resolved := make([]Dependency, 0, len(pending))
+	for _, p := range pending {
+		p.dependency.From = resolve(p.from, nil)
+		p.dependency.To = resolve(p.to, p.dependency.From)
+		resolved = append(resolved, p.dependency)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Dependencies = append(r.Dependencies, resolved...)
+}
+
+// hasPathPrefix reports whether path is prefix or below it
+func hasPathPrefix(path, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// describeSearch formats a search for Dependency.Via, e.g.
+// "As(*app.Database, field=PrimaryDB)"
+func describeSearch(method string, target reflect.Type, criteria ...string) string {
+	args := make([]string, 0, len(criteria)+1)
+	if target != nil {
+		args = append(args, target.String())
+	}
+	for _, c := range criteria {
+		if c != "" {
+			args = append(args, c)
+		}
+	}
+	return method + "(" + strings.Join(args, ", ") + ")"
+}
+
+// describeFilters formats As filters for Dependency.Via
+func describeFilters(filters []Filter) []string {
+	descriptions := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		descriptions = append(descriptions, describeFilter(filter))
+	}
+	return descriptions
+}
+
+// describeFilter formats one As filter
+func describeFilter(filter Filter) string {
+	switch f := filter.(type) {
+	case fieldNameFilter:
+		return "field=" + f.name
+	case jsonTagFilter:
+		return "json=" + f.tag
+	case customTagFilter:
+		return f.key + "=" + f.value
+	case capabilityFilter:
+		return "capability=" + string(f.capability)
+	case embeddedMatchFilter:
+		return "embedded"
+	case notFilter:
+		return "not(" + describeFilter(f.filter) + ")"
+	case orFilter:
+		return "or(" + strings.Join(describeFilters(f.filters), ", ") + ")"
+	default:
+		return fmt.Sprintf("%T", filter)
+	}
+}
@@ -0,0 +1,155 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type depDatabase struct {
+	Name string
+}
+
+type depCache struct {
+	Name string
+}
+
+type depAuth struct {
+	DB    *depDatabase
+	Cache *depCache
+}
+
+func (a *depAuth) Init(ctx context.Context, parent interface{}) error {
+	As(ctx, a, parent, &a.DB, WithFieldName("Primary"))
+	a.Cache, _ = NewComponentFinder(ctx, a, parent).Find(&SearchOption{ByType: reflect.TypeOf(&depCache{})}).(*depCache)
+	return nil
+}
+
+type depModule struct {
+	Auth     *depAuth
+	Injected *depDatabase `autoinit:"inject=Backup"`
+}
+
+type depApp struct {
+	Primary *depDatabase
+	Backup  *depDatabase
+	Cache   *depCache
+	Module  *depModule
+}
+
+func TestReportDependencies(t *testing.T) {
+	app := &depApp{
+		Primary: &depDatabase{Name: "primary"},
+		Backup:  &depDatabase{Name: "backup"},
+		Cache:   &depCache{Name: "cache"},
+		Module:  &depModule{Auth: &depAuth{}},
+	}
+	report := &InitReport{}
+	if err := WithOptions(context.Background(), app, &Options{Report: report}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Module.Auth searches Module's fields only; it finds nothing with As there,
+	// but the finder climbs to the Cache of the app
+	deps := report.DependenciesOf("Module.Auth")
+	if len(deps) != 1 {
+		t.Fatalf("expected one dependency of Module.Auth, got %v", deps)
+	}
+	if deps[0].ToString() != "Cache" || deps[0].Via != "Find(type=*autoinit.depCache)" {
+		t.Errorf("unexpected dependency %v", deps[0])
+	}
+
+	deps = report.DependenciesOf("Module")
+	if len(deps) != 1 || deps[0].ToString() != "Backup" || deps[0].Via != "inject=Backup(*autoinit.depDatabase)" {
+		t.Errorf("expected Module to be injected with Backup, got %v", deps)
+	}
+}
+
+func TestReportDependenciesAs(t *testing.T) {
+	// Auth is declared first, so its fields reach the databases before the
+	// app's own fields do once Init assigned them
+	type App struct {
+		Auth    *depAuth
+		Primary *depDatabase
+		Backup  *depDatabase
+		Cache   *depCache
+	}
+	app := &App{
+		Primary: &depDatabase{Name: "primary"},
+		Backup:  &depDatabase{Name: "backup"},
+		Cache:   &depCache{Name: "cache"},
+		Auth:    &depAuth{},
+	}
+	report := &InitReport{}
+	if err := WithOptions(context.Background(), app, &Options{Report: report}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deps := report.DependenciesOf("Auth")
+	if len(deps) != 2 {
+		t.Fatalf("expected two dependencies of Auth, got %v", deps)
+	}
+	// Auth got the primary database, not the backup
+	db := deps[0]
+	if db.ToString() != "Primary" || db.ToType != "*autoinit.depDatabase" || db.Via != "As(*autoinit.depDatabase, field=Primary)" {
+		t.Errorf("unexpected dependency %v", db)
+	}
+	if db.FromType != "*autoinit.depAuth" {
+		t.Errorf("FromType = %s", db.FromType)
+	}
+	if got := db.String(); got != "Auth -> Primary (*autoinit.depDatabase) via As(*autoinit.depDatabase, field=Primary)" {
+		t.Errorf("String() = %q", got)
+	}
+	if deps[1].ToString() != "Cache" {
+		t.Errorf("expected the finder to find Cache, got %v", deps[1])
+	}
+}
+
+func TestReportDependenciesOutsideTree(t *testing.T) {
+	shared := &depCache{Name: "shared"}
+	type App struct {
+		Auth *depAuth
+	}
+	app := &App{Auth: &depAuth{}}
+	report := &InitReport{}
+	ctx := WithAdditionalRoots(context.Background(), &struct{ Cache *depCache }{Cache: shared})
+	if err := WithOptions(ctx, app, &Options{Report: report}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deps := report.DependenciesOf("Auth")
+	if len(deps) != 1 || deps[0].To != nil || deps[0].ToString() != "<outside>" {
+		t.Errorf("expected the shared cache outside the tree, got %v", deps)
+	}
+}
+
+type depStore interface {
+	Get() string
+}
+
+func (d *depDatabase) Get() string { return d.Name }
+
+type depReader struct {
+	Store depStore
+}
+
+func (r *depReader) Init(ctx context.Context, parent interface{}) error {
+	As(ctx, r, parent, &r.Store)
+	return nil
+}
+
+func TestReportDependenciesInterfaceField(t *testing.T) {
+	// Interface fields are not traversed, but what they hold is in the tree
+	type App struct {
+		Store  depStore
+		Reader *depReader
+	}
+	app := &App{Store: &depDatabase{Name: "db"}, Reader: &depReader{}}
+	report := &InitReport{}
+	if err := WithOptions(context.Background(), app, &Options{Report: report}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deps := report.DependenciesOf("Reader")
+	if len(deps) != 1 || deps[0].ToString() != "Store" {
+		t.Errorf("expected Reader to depend on Store, got %v", deps)
+	}
+}
@@ -0,0 +1,109 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// thirdPartyClient stands in for a large external struct with its own Init
+type thirdPartyClient struct {
+	Internals   *SimpleComponent
+	Initialized bool
+}
+
+func (c *thirdPartyClient) Init() error {
+	c.Initialized = true
+	return nil
+}
+
+type descendApp struct {
+	Client *thirdPartyClient
+	Local  *SimpleComponent
+}
+
+func TestAllowDescendStopsTraversal(t *testing.T) {
+	app := &descendApp{
+		Client: &thirdPartyClient{Internals: &SimpleComponent{}},
+		Local:  &SimpleComponent{},
+	}
+
+	options := &Options{
+		AllowDescend: func(t reflect.Type) bool {
+			return t != reflect.TypeOf(thirdPartyClient{})
+		},
+	}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !app.Client.Initialized {
+		t.Error("disallowed type should still be initialized")
+	}
+	if app.Client.Internals.Initialized {
+		t.Error("fields of a disallowed type should not be traversed")
+	}
+	if !app.Local.Initialized {
+		t.Error("allowed fields should be initialized")
+	}
+}
+
+func TestAllowPackages(t *testing.T) {
+	allow := AllowPackages("github.com/telnet2/")
+
+	if !allow(reflect.TypeOf(descendApp{})) {
+		t.Error("types in an allowed package should be allowed")
+	}
+	if allow(reflect.TypeOf(time.Time{})) {
+		t.Error("stdlib types should not be allowed")
+	}
+	if !allow(reflect.TypeOf(struct{ A int }{})) {
+		t.Error("unnamed struct types should be allowed")
+	}
+	if !strings.HasPrefix(reflect.TypeOf(descendApp{}).PkgPath(), "github.com/telnet2/") {
+		t.Fatal("unexpected package path for test type")
+	}
+}
+
+// Test that stdlib value types and configured LeafTypes are not traversed or initialized
+func TestLeafTypes(t *testing.T) {
+	type App struct {
+		Started  time.Time
+		Deadline *time.Time
+		History  []time.Time
+		Client   *thirdPartyClient
+		Local    *SimpleComponent
+	}
+
+	now := time.Now()
+	app := &App{
+		Started:  now,
+		Deadline: &now,
+		History:  []time.Time{now},
+		Client:   &thirdPartyClient{Internals: &SimpleComponent{}},
+		Local:    &SimpleComponent{},
+	}
+
+	report := &InitReport{}
+	options := &Options{
+		LeafTypes: []reflect.Type{reflect.TypeOf(thirdPartyClient{})},
+		Report:    report,
+	}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, path := range []string{"Started", "Deadline", "History", "Client"} {
+		if reason, ok := report.SkipReasonFor(path); !ok || reason != SkipLeafType {
+			t.Errorf("%s: expected leaf type skip, got %v (recorded=%v)", path, reason, ok)
+		}
+	}
+	if app.Client.Initialized || app.Client.Internals.Initialized {
+		t.Error("configured leaf type should be neither initialized nor traversed")
+	}
+	if !app.Local.Initialized {
+		t.Error("other fields should be initialized")
+	}
+}
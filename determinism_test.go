@@ -0,0 +1,126 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type orderedComponent struct {
+	Name string
+}
+
+func (c *orderedComponent) Init() error {
+	return nil
+}
+
+type orderedHooked struct {
+	Child *orderedComponent
+}
+
+func (h *orderedHooked) PreInit(ctx context.Context) error {
+	return nil
+}
+
+func (h *orderedHooked) Init(ctx context.Context) error {
+	return nil
+}
+
+func (h *orderedHooked) PostInit(ctx context.Context) error {
+	return nil
+}
+
+type orderedApp struct {
+	Services map[string]*orderedComponent
+	ByID     map[int]orderedComponent
+	Workers  []*orderedComponent
+	Hooked   orderedHooked
+}
+
+func newOrderedApp() *orderedApp {
+	app := &orderedApp{
+		Services: make(map[string]*orderedComponent),
+		ByID:     make(map[int]orderedComponent),
+		Hooked:   orderedHooked{Child: &orderedComponent{Name: "child"}},
+	}
+	for _, name := range []string{"zeta", "alpha", "mu", "beta", "omega", "gamma", "delta", "kappa"} {
+		app.Services[name] = &orderedComponent{Name: name}
+	}
+	for i := 10; i > 0; i-- {
+		app.ByID[i] = orderedComponent{}
+	}
+	for i := 0; i < 3; i++ {
+		app.Workers = append(app.Workers, &orderedComponent{})
+	}
+	return app
+}
+
+func recordInitOrder(t *testing.T) []string {
+	t.Helper()
+	var order []string
+	logger := zerolog.Nop()
+	options := &Options{
+		Logger: &logger,
+		OnEvent: func(e Event) {
+			order = append(order, string(e.Phase)+" "+e.PathString())
+		},
+	}
+	if err := WithOptions(context.Background(), newOrderedApp(), options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return order
+}
+
+// TestDeterministicInitOrder runs the same tree many times and asserts
+// the lifecycle call sequence never changes
+func TestDeterministicInitOrder(t *testing.T) {
+	first := recordInitOrder(t)
+	if len(first) == 0 {
+		t.Fatal("no events recorded")
+	}
+
+	for i := 0; i < 50; i++ {
+		if got := recordInitOrder(t); !reflect.DeepEqual(got, first) {
+			t.Fatalf("run %d produced a different order:\n got: %v\nwant: %v", i, got, first)
+		}
+	}
+}
+
+// TestMapKeysSorted verifies map components are initialized in sorted key order
+func TestMapKeysSorted(t *testing.T) {
+	order := recordInitOrder(t)
+
+	expected := []string{
+		"Init Services.[alpha]",
+		"Init Services.[beta]",
+		"Init Services.[delta]",
+		"Init Services.[gamma]",
+		"Init Services.[kappa]",
+		"Init Services.[mu]",
+		"Init Services.[omega]",
+		"Init Services.[zeta]",
+		"Init ByID.[1]",
+		"Init ByID.[2]",
+	}
+	if !reflect.DeepEqual(order[:len(expected)], expected) {
+		t.Errorf("unexpected order:\n got: %v\nwant: %v", order[:len(expected)], expected)
+	}
+}
+
+// TestEventPhases verifies hooks and Init report events in lifecycle order
+func TestEventPhases(t *testing.T) {
+	order := recordInitOrder(t)
+
+	expectedTail := []string{
+		"PreInit Hooked",
+		"Init Hooked.Child",
+		"Init Hooked",
+		"PostInit Hooked",
+	}
+	tail := order[len(order)-len(expectedTail):]
+	if !reflect.DeepEqual(tail, expectedTail) {
+		t.Errorf("unexpected phases:\n got: %v\nwant: %v", tail, expectedTail)
+	}
+}
@@ -0,0 +1,88 @@
+package autoinit
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+)
+
+// findDuplicateInits inspects the direct fields of struct v for components that
+// would be initialized more than once and returns a description of each problem:
+//
+//   - an embedded component whose Init is promoted to v (v declares no Init of
+//     its own), so the same Init runs once for the field and once for v
+//   - several fields referencing the same component pointer while cycle
+//     detection is disabled
+func findDuplicateInits(v reflect.Value, options *Options) []string {
+	var problems []string
+	t := v.Type()
+
+	if embedded, ok := promotedInitSource(v); ok {
+		problems = append(problems, fmt.Sprintf(
+			"Init of embedded field %s is promoted to %s and will run twice: once for the field and once for %s",
+			embedded, t, t))
+	}
+
+	if options != nil && options.DisableCycleDetection {
+		seen := make(map[uintptr]string)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if !field.CanInterface() || field.Kind() != reflect.Ptr || field.IsNil() {
+				continue
+			}
+			if resolveInitializer(field, reflect.Value{}, false) == nil {
+				continue
+			}
+			name := t.Field(i).Name
+			if other, dup := seen[field.Pointer()]; dup {
+				problems = append(problems, fmt.Sprintf(
+					"fields %s and %s reference the same component and it will be initialized twice", other, name))
+				continue
+			}
+			seen[field.Pointer()] = name
+		}
+	}
+
+	return problems
+}
+
+// promotedInitSource returns the name of the embedded component field whose
+// Init method is promoted to struct v, if v does not declare Init itself
+func promotedInitSource(v reflect.Value) (string, bool) {
+	if !v.CanAddr() {
+		return "", false
+	}
+	method, ok := v.Addr().Type().MethodByName("Init")
+	if !ok || !isPromotedMethod(method) {
+		return "", false
+	}
+
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		fieldType := t.Field(i)
+		field := v.Field(i)
+		if !fieldType.Anonymous || !field.CanInterface() {
+			continue
+		}
+		if fieldType.Tag.Get("autoinit") == "-" {
+			continue
+		}
+		if resolveInitializer(field, reflect.Value{}, false) != nil {
+			return fieldType.Name, true
+		}
+	}
+	return "", false
+}
+
+// isPromotedMethod reports whether a method was promoted from an embedded field.
+// The compiler implements promoted methods as autogenerated wrappers, which is
+// the only way to tell them apart from declared methods through reflection.
+func isPromotedMethod(method reflect.Method) bool {
+	pc := method.Func.Pointer()
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return false
+	}
+	file, _ := fn.FileLine(pc)
+	return file == "<autogenerated>"
+}
@@ -0,0 +1,105 @@
+package autoinit
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type CountingBase struct {
+	InitCount int
+}
+
+func (b *CountingBase) Init() error {
+	b.InitCount++
+	return nil
+}
+
+// PromotedInit embeds CountingBase without declaring its own Init
+type PromotedInit struct {
+	CountingBase
+}
+
+// OwnInit embeds CountingBase but declares its own Init
+type OwnInit struct {
+	CountingBase
+	Initialized bool
+}
+
+func (o *OwnInit) Init() error {
+	o.Initialized = true
+	return nil
+}
+
+func runWithWarnings(t *testing.T, target interface{}, options *Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.WarnLevel)
+	options.Logger = &logger
+	if err := WithOptions(context.Background(), target, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestDetectPromotedInit(t *testing.T) {
+	type App struct {
+		Promoted PromotedInit
+	}
+
+	app := &App{}
+	warnings := runWithWarnings(t, app, &Options{DetectDuplicateInit: true})
+
+	// The promoted Init really runs twice
+	if app.Promoted.InitCount != 2 {
+		t.Errorf("expected the promoted Init to run twice, got %d", app.Promoted.InitCount)
+	}
+	if !strings.Contains(warnings, "CountingBase is promoted") || !strings.Contains(warnings, `"path":"Promoted"`) {
+		t.Errorf("expected a promoted-Init warning, got: %s", warnings)
+	}
+}
+
+func TestDetectDuplicateInitNoFalsePositives(t *testing.T) {
+	type App struct {
+		Own    OwnInit
+		Shared *SimpleComponent
+		Alias  *SimpleComponent
+	}
+
+	shared := &SimpleComponent{}
+	app := &App{Shared: shared, Alias: shared}
+
+	// A declared Init and cycle-protected shared pointers are fine
+	if warnings := runWithWarnings(t, app, &Options{DetectDuplicateInit: true}); warnings != "" {
+		t.Errorf("expected no warnings, got: %s", warnings)
+	}
+	if app.Own.InitCount != 1 || !app.Own.Initialized {
+		t.Error("embedded and outer Init should each run once")
+	}
+
+	// Without the option nothing is reported
+	if warnings := runWithWarnings(t, &struct{ P PromotedInit }{}, &Options{}); warnings != "" {
+		t.Errorf("expected no warnings without DetectDuplicateInit, got: %s", warnings)
+	}
+}
+
+func TestDetectSharedPointerWithoutCycleDetection(t *testing.T) {
+	type App struct {
+		Shared *CountingBase
+		Alias  *CountingBase
+	}
+
+	shared := &CountingBase{}
+	app := &App{Shared: shared, Alias: shared}
+	warnings := runWithWarnings(t, app, &Options{DetectDuplicateInit: true, DisableCycleDetection: true})
+
+	if shared.InitCount != 2 {
+		t.Errorf("expected the shared component to init twice, got %d", shared.InitCount)
+	}
+	if !strings.Contains(warnings, "fields Shared and Alias reference the same component") {
+		t.Errorf("expected a shared-pointer warning, got: %s", warnings)
+	}
+}
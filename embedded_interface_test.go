@@ -0,0 +1,88 @@
+package autoinit
+
+import (
+	"context"
+	"testing"
+)
+
+// EmbeddedStore is exported because unexported embedded fields are not traversed
+type EmbeddedStore interface {
+	Get(key string) string
+}
+
+type embeddedConfig struct {
+	Prefix string
+}
+
+// embeddedMemoryStore is the dynamic value of the embedded interface
+type embeddedMemoryStore struct {
+	Config      *embeddedConfig
+	initialized bool
+	shutdown    bool
+}
+
+func (s *embeddedMemoryStore) Init() error {
+	s.initialized = true
+	return nil
+}
+
+func (s *embeddedMemoryStore) Shutdown(ctx context.Context) error {
+	s.shutdown = true
+	return nil
+}
+
+func (s *embeddedMemoryStore) Get(key string) string {
+	return s.Config.Prefix + key
+}
+
+type embeddedConsumer struct {
+	Store  *embeddedMemoryStore
+	Config *embeddedConfig
+}
+
+func (c *embeddedConsumer) Init(ctx context.Context, parent interface{}) error {
+	As(ctx, c, parent, &c.Store)
+	As(ctx, c, parent, &c.Config)
+	return nil
+}
+
+func TestEmbeddedInterface(t *testing.T) {
+	type App struct {
+		EmbeddedStore
+		Consumer *embeddedConsumer
+	}
+
+	store := &embeddedMemoryStore{Config: &embeddedConfig{Prefix: "app:"}}
+	app := &App{EmbeddedStore: store, Consumer: &embeddedConsumer{}}
+
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !store.initialized {
+		t.Error("component in the embedded interface was not initialized")
+	}
+	if app.Get("key") != "app:key" {
+		t.Errorf("promoted method returned %q", app.Get("key"))
+	}
+
+	// The embedded value and its own fields are discoverable by siblings
+	if app.Consumer.Store != store {
+		t.Errorf("As did not find the embedded interface value, got %v", app.Consumer.Store)
+	}
+	if app.Consumer.Config != store.Config {
+		t.Errorf("As did not search the embedded interface value, got %v", app.Consumer.Config)
+	}
+
+	if err := AutoShutdown(context.Background(), app, nil); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if !store.shutdown {
+		t.Error("component in the embedded interface was not shut down")
+	}
+
+	// A nil embedded interface is skipped
+	empty := &App{Consumer: &embeddedConsumer{}}
+	if err := AutoInit(context.Background(), empty); err != nil {
+		t.Fatalf("unexpected error with nil embedded interface: %v", err)
+	}
+}
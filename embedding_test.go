@@ -0,0 +1,102 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// EmbeddingBase is composed into components by embedding. It is exported
+// because values reached through unexported embedded fields cannot be returned.
+type EmbeddingBase struct {
+	Name string
+}
+
+type embeddingServer struct {
+	EmbeddingBase
+	Port int
+}
+
+// embeddingWorker embeds the base through another, exported, embedded struct
+type embeddingWorker struct {
+	*EmbeddingMiddle
+}
+
+type EmbeddingMiddle struct {
+	EmbeddingBase
+	Queue string
+}
+
+type embeddingSearcher struct {
+	Base  *EmbeddingBase
+	Bases []interface{}
+}
+
+func (s *embeddingSearcher) Init(ctx context.Context, parent interface{}) error {
+	As(ctx, s, parent, &s.Base, MatchEmbedded())
+	s.Bases = NewComponentFinder(ctx, s, parent).FindAll(&SearchOption{
+		ByType:        reflect.TypeOf(&EmbeddingBase{}),
+		MatchEmbedded: true,
+	})
+	return nil
+}
+
+func TestMatchEmbedded(t *testing.T) {
+	type App struct {
+		Server   *embeddingServer
+		Worker   embeddingWorker
+		Searcher *embeddingSearcher
+	}
+
+	app := &App{
+		Server:   &embeddingServer{EmbeddingBase: EmbeddingBase{Name: "server"}},
+		Worker:   embeddingWorker{&EmbeddingMiddle{EmbeddingBase: EmbeddingBase{Name: "worker"}}},
+		Searcher: &embeddingSearcher{},
+	}
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The embedded base is returned in place, so changes reach the component
+	if app.Searcher.Base != &app.Server.EmbeddingBase {
+		t.Fatalf("As returned %v, want the server's embedded base", app.Searcher.Base)
+	}
+	if len(app.Searcher.Bases) != 2 ||
+		app.Searcher.Bases[0] != &app.Server.EmbeddingBase ||
+		app.Searcher.Bases[1] != &app.Worker.EmbeddingBase {
+		t.Errorf("FindAll returned %v", app.Searcher.Bases)
+	}
+
+	// Without the option, embedding does not match
+	var base *EmbeddingBase
+	if As(context.Background(), app.Searcher, app, &base) {
+		t.Errorf("As matched an embedding component without MatchEmbedded: %v", base)
+	}
+	if found := NewComponentFinder(context.Background(), app.Searcher, app).Find(&SearchOption{
+		ByType: reflect.TypeOf(&EmbeddingBase{}),
+	}); found != nil {
+		t.Errorf("Find matched an embedding component without MatchEmbedded: %v", found)
+	}
+
+	// Other filters still apply to the field holding the component
+	if !As(context.Background(), app.Searcher, app, &base, MatchEmbedded(), WithFieldName("Worker")) || base.Name != "worker" {
+		t.Errorf("expected the worker's base, got %v", base)
+	}
+}
+
+func TestMatchEmbeddedNestedPanics(t *testing.T) {
+	tests := map[string]func(){
+		"Not": func() { Not(MatchEmbedded()) },
+		"Or":  func() { Or(WithFieldName("Worker"), MatchEmbedded()) },
+	}
+	for name, combine := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected %s(MatchEmbedded()) to panic", name)
+				}
+			}()
+			combine()
+		})
+	}
+}
@@ -1,25 +1,73 @@
 package autoinit
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 )
 
+// ErrNotAddressable is the cause of the InitError returned for a component whose
+// initializer has a pointer receiver but that AutoInit can only reach as a
+// non-addressable value, e.g. inside a target passed by value or a struct value
+// stored in an interface. Its Init cannot be called; pass or store a pointer.
+var ErrNotAddressable = errors.New("initializer has a pointer receiver but the component is not addressable; pass or store a pointer to it")
+
+// ErrDuplicateSingleton is the cause of the InitError returned when AutoInit
+// reaches a second, distinct instance of a component type implementing Singleton
+var ErrDuplicateSingleton = errors.New("duplicate instance of singleton component")
+
+// ErrUnresolvedInjection is the cause of the InitError returned for a field
+// tagged `autoinit:"inject"` for which no component was found after all
+// components initialized
+var ErrUnresolvedInjection = errors.New("no component to inject")
+
 // InitError represents an error that occurred during initialization
 type InitError struct {
-	Path      []string // Full path to the failing field
-	FieldType string   // Type of the field that failed
-	Cause     error    // Original error from Init()
+	Path       []string // Full path to the failing field
+	FieldIndex int      // Declaration index of the field in its parent struct (-1 for the root)
+	FieldType  string   // Type of the field that failed
+	Cause      error    // Original error from Init()
 }
 
-// Error implements the error interface with detailed context
+// Error implements the error interface with detailed context. When the cause
+// holds an InitError, e.g. returned by a nested AutoInit on a subtree, the
+// message names the innermost component of Chain by its FullPath.
 func (e *InitError) Error() string {
-	if len(e.Path) == 0 {
-		return fmt.Sprintf("failed to initialize %s: %v", e.FieldType, e.Cause)
+	chain := e.Chain()
+	inner := chain[len(chain)-1]
+	path := e.FullPath()
+
+	if len(path) == 0 {
+		return fmt.Sprintf("failed to initialize %s: %v", inner.FieldType, inner.Cause)
 	}
 
-	pathStr := strings.Join(e.Path, ".")
-	return fmt.Sprintf("failed to initialize field '%s' of type %s: %v", pathStr, e.FieldType, e.Cause)
+	pathStr := strings.Join(path, ".")
+	return fmt.Sprintf("failed to initialize field '%s' of type %s: %v", pathStr, inner.FieldType, inner.Cause)
+}
+
+// Chain returns e followed by the InitErrors nested in its cause chain,
+// outermost first. A component whose Init runs AutoInit on its own subtree
+// and returns the error adds a link, each carrying the path of its own run.
+// errors.As stops at the outermost InitError; the last link is the innermost.
+func (e *InitError) Chain() []*InitError {
+	chain := []*InitError{e}
+	var inner *InitError
+	for cause := e.Cause; errors.As(cause, &inner); cause = inner.Cause {
+		chain = append(chain, inner)
+	}
+	return chain
+}
+
+// FullPath returns the path of the innermost failing component from the root
+// of the outermost run: the paths of every link of Chain joined together
+func (e *InitError) FullPath() []string {
+	var path []string
+	for _, link := range e.Chain() {
+		path = append(path, link.Path...)
+	}
+	return path
 }
 
 // Unwrap returns the underlying error for error unwrapping support
@@ -27,12 +75,161 @@ func (e *InitError) Unwrap() error {
 	return e.Cause
 }
 
+// RootCause returns the original component error, unwrapping through any
+// InitErrors nested in the cause chain (directly or behind other wrappers), so
+// callers can classify a failure regardless of nesting depth:
+//
+//	var opErr *net.OpError
+//	if errors.As(initErr.RootCause(), &opErr) { ... }
+//
+// errors.Is and errors.As already traverse the whole chain via Unwrap.
+func (e *InitError) RootCause() error {
+	cause := e.Cause
+	var inner *InitError
+	for errors.As(cause, &inner) {
+		cause = inner.Cause
+	}
+	return cause
+}
+
 // GetPath returns the full path to the field that failed initialization
 func (e *InitError) GetPath() []string {
 	return e.Path
 }
 
+// GetFieldIndex returns the declaration index of the failing field in its parent struct.
+// Collection elements report the index of their collection field; the root reports -1.
+func (e *InitError) GetFieldIndex() int {
+	return e.FieldIndex
+}
+
 // GetFieldType returns the type of the field that failed
 func (e *InitError) GetFieldType() string {
 	return e.FieldType
 }
+
+// NilFieldError reports a component field left nil, as found by CheckNonNil
+type NilFieldError struct {
+	Path      []string // Full path to the nil field
+	FieldType string   // Declared type of the field
+}
+
+// Error implements the error interface with detailed context
+func (e *NilFieldError) Error() string {
+	pathStr := strings.Join(e.Path, ".")
+	return fmt.Sprintf("component field '%s' of type %s is nil; assign it or tag it autoinit:\"optional\"", pathStr, e.FieldType)
+}
+
+// ShutdownError represents an error that occurred while shutting down a component
+type ShutdownError struct {
+	Path      []string // Full path to the failing field
+	FieldType string   // Type of the field that failed
+	Cause     error    // Original error from Shutdown() or Close()
+}
+
+// Error implements the error interface with detailed context
+func (e *ShutdownError) Error() string {
+	if len(e.Path) == 0 {
+		return fmt.Sprintf("failed to shut down %s: %v", e.FieldType, e.Cause)
+	}
+
+	pathStr := strings.Join(e.Path, ".")
+	return fmt.Sprintf("failed to shut down field '%s' of type %s: %v", pathStr, e.FieldType, e.Cause)
+}
+
+// Unwrap returns the underlying error for error unwrapping support
+func (e *ShutdownError) Unwrap() error {
+	return e.Cause
+}
+
+// PanicError is the cause reported when a component panics and the panic is
+// recovered (see RecoverMiddleware)
+type PanicError struct {
+	Value interface{} // Value passed to panic
+	Stack []byte      // Stack trace captured where the panic was recovered
+}
+
+// Error implements the error interface
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// InterruptedError is returned when the context passed to AutoInit ends, e.g.
+// at a startup deadline, before the tree is initialized. AutoInit stops at the
+// next component instead of walking the rest of the tree, even components whose
+// Init ignores ctx, and reports how far it got:
+//
+//	var interrupted *autoinit.InterruptedError
+//	if errors.As(err, &interrupted) {
+//	    log.Printf("startup stuck in %s", strings.Join(interrupted.InProgress, "."))
+//	}
+//
+// It unwraps to the context's error, so errors.Is(err, context.DeadlineExceeded)
+// holds. With ContinueOnError it is returned joined with the failures collected
+// before the interruption.
+type InterruptedError struct {
+	LastCompleted []string // Path of the last component whose Init completed, nil if none did
+	InProgress    []string // Path of the component whose Init was running when the context ended, or of the next one to start
+	Cause         error    // The context's error
+}
+
+// Error implements the error interface
+func (e *InterruptedError) Error() string {
+	last := "none"
+	if e.LastCompleted != nil {
+		last = pathToString(e.LastCompleted)
+	}
+	return fmt.Sprintf("initialization interrupted in %s (last completed: %s): %v", pathToString(e.InProgress), last, e.Cause)
+}
+
+// Unwrap returns the context's error
+func (e *InterruptedError) Unwrap() error {
+	return e.Cause
+}
+
+// isInterrupted reports whether err stems from the run's context ending
+func isInterrupted(err error) bool {
+	var interrupted *InterruptedError
+	return errors.As(err, &interrupted)
+}
+
+const errorCollectorKey contextKey = "autoinit:errorCollector"
+
+// errorCollector gathers component failures when Options.ContinueOnError is set
+type errorCollector struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// withErrorCollector attaches a new collector to ctx
+func withErrorCollector(ctx context.Context) (context.Context, *errorCollector) {
+	collector := &errorCollector{}
+	return context.WithValue(ctx, errorCollectorKey, collector), collector
+}
+
+// getErrorCollector returns the collector of the current run, if any
+func getErrorCollector(ctx context.Context) *errorCollector {
+	collector, _ := ctx.Value(errorCollectorKey).(*errorCollector)
+	return collector
+}
+
+func (c *errorCollector) add(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errs = append(c.errs, err)
+}
+
+// join returns all collected failures as one error, nil if there were none
+func (c *errorCollector) join() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return errors.Join(c.errs...)
+}
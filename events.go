@@ -0,0 +1,140 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// EventPhase identifies the lifecycle step an Event describes
+type EventPhase string
+
+const (
+	// PhasePreInit is reported after a component's PreInit hook runs
+	PhasePreInit EventPhase = "PreInit"
+	// PhaseInit is reported after a component's Init method runs
+	PhaseInit EventPhase = "Init"
+	// PhasePostInit is reported after a component's PostInit hook runs
+	PhasePostInit EventPhase = "PostInit"
+	// PhaseLink is reported after a component's Link method runs
+	PhaseLink EventPhase = "Link"
+	// PhaseBeforeTypeHook is reported after a TypeHook.Before function runs
+	PhaseBeforeTypeHook EventPhase = "BeforeTypeHook"
+	// PhaseAfterTypeHook is reported after a TypeHook.After function runs
+	PhaseAfterTypeHook EventPhase = "AfterTypeHook"
+)
+
+// Event describes a single lifecycle call made during AutoInit.
+// Events are only reported for methods a component actually implements.
+type Event struct {
+	Phase      EventPhase    // Lifecycle step that ran
+	Path       []string      // Path to the component (empty for the root)
+	FieldIndex int           // Declaration index of the field in its parent struct (-1 for the root)
+	Type       string        // Type the method was called on
+	Err        error         // Error returned by the method, if any
+	TraceID    string        // Trace ID of the run (see WithTraceID), if any
+	Duration   time.Duration // How long the method took, including middleware
+}
+
+// PathString returns the dot-separated path of the component
+func (e Event) PathString() string {
+	return pathToString(e.Path)
+}
+
+// emitEvent reports an event to the OnEvent callback if one is configured
+func emitEvent(ctx context.Context, options *Options, event Event) {
+	if options == nil || options.OnEvent == nil {
+		return
+	}
+	// Copy the path so callers can retain it safely
+	event.Path = append([]string(nil), event.Path...)
+	event.TraceID = TraceIDFromContext(ctx)
+	_, unlock := lockCallbacks(ctx)
+	defer unlock()
+	options.OnEvent(event)
+}
+
+const traceIDKey contextKey = "autoinit:traceID"
+
+// WithTraceID returns a context carrying a correlation ID for a run. AutoInit
+// and AutoShutdown add it to every log line (as "trace_id") and Event, so the
+// lifecycle logs of one application boot can be found in aggregated logs.
+// Options.TraceID takes precedence over an ID carried by the context.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// TraceIDFromContext returns the trace ID carried by ctx, or "" if there is none.
+// Components can use it to tag their own startup logs.
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(traceIDKey).(string)
+	return id
+}
+
+// withRunTraceID applies Options.TraceID to ctx and returns a logger that
+// includes the effective trace ID, if any, in every line
+func withRunTraceID(ctx context.Context, options *Options, logger zerolog.Logger) (context.Context, zerolog.Logger) {
+	if options != nil && options.TraceID != "" {
+		ctx = WithTraceID(ctx, options.TraceID)
+	}
+	if id := TraceIDFromContext(ctx); id != "" {
+		logger = logger.With().Str("trace_id", id).Logger()
+	}
+	return ctx, logger
+}
+
+const runLoggerKey contextKey = "autoinit:logger"
+
+// withRunLogger stores the run's logger on ctx for code that only receives the
+// context, such as As and ComponentFinder
+func withRunLogger(ctx context.Context, logger zerolog.Logger) context.Context {
+	return context.WithValue(ctx, runLoggerKey, logger)
+}
+
+// runLogger returns the logger of the run ctx belongs to, or a disabled logger
+// outside of a run
+func runLogger(ctx context.Context) *zerolog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(runLoggerKey).(zerolog.Logger); ok {
+			return &logger
+		}
+	}
+	nop := zerolog.Nop()
+	return &nop
+}
+
+// sortedMapKeys returns the keys of a map in a deterministic order so that
+// map-held components are always initialized in the same sequence.
+// Keys of ordered kinds are compared by value; all others by their formatted form.
+func sortedMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+	return keys
+}
+
+// lessKey orders two map keys of the same type
+func lessKey(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	default:
+		return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+	}
+}
@@ -0,0 +1,132 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/rs/zerolog"
+)
+
+// ComponentFactory builds a component on demand. A field holding a slice of
+// factories ([]ComponentFactory, or []func() interface{}) is a deferred
+// construction list: AutoInit calls each factory in order and initializes the
+// component it returns as a child of the struct, under paths such as
+// "Factories.[0]". Factories should return pointers to structs.
+//
+// The built components are stored in the sibling slice field named by the
+// field's `autoinit:"results=Name"` tag, in factory order, so they stay
+// reachable by tree walks such as AutoShutdown and by the finder:
+//
+//	type App struct {
+//	    Factories  []autoinit.ComponentFactory `autoinit:"results=Components"`
+//	    Components []interface{}
+//	}
+//
+// The results field may be any slice type whose elements accept the built
+// components, e.g. []Plugin; it is replaced on every run. Nil factories and
+// nil results leave a zero entry. Without a results tag the components are
+// initialized but not stored.
+type ComponentFactory func() interface{}
+
+var componentFactoryType = reflect.TypeOf(ComponentFactory(nil))
+
+// isFactoryList reports whether field is a slice of component factories
+func isFactoryList(field reflect.Value) bool {
+	if field.Kind() != reflect.Slice {
+		return false
+	}
+	elem := field.Type().Elem()
+	return elem.Kind() == reflect.Func && elem.ConvertibleTo(componentFactoryType)
+}
+
+// factoryResultsField returns the field of parent named by the results=Name
+// option of tag, or an invalid value if the tag has none
+func factoryResultsField(parent reflect.Value, tag tagOptions) (reflect.Value, error) {
+	name, ok := tag.value("results")
+	if !ok {
+		return reflect.Value{}, nil
+	}
+	results := parent.FieldByName(name)
+	if !results.IsValid() {
+		return reflect.Value{}, fmt.Errorf("autoinit results field %q not found", name)
+	}
+	if results.Kind() != reflect.Slice || !results.CanSet() {
+		return reflect.Value{}, fmt.Errorf("autoinit results field %q must be a settable slice field", name)
+	}
+	return results, nil
+}
+
+// initFactoryField builds and initializes the components of the factory list
+// field of struct v, storing them in its results field
+func initFactoryField(ctx context.Context, v reflect.Value, field reflect.Value, tag tagOptions, fieldName string, fieldPath []string, index int, logger *zerolog.Logger, visited map[uintptr]bool, options *Options) error {
+	// A phased run builds the components once, in the pass of the field's phase
+	if !inSelectedPhase(ctx) {
+		return nil
+	}
+
+	results, err := factoryResultsField(v, tag)
+	if err != nil {
+		return fmt.Errorf("field %s.%s: %w", v.Type().String(), fieldName, err)
+	}
+
+	ctx, err = callPreFieldHook(ctx, v, fieldName, field, logger, options)
+	if err != nil {
+		return err
+	}
+
+	// Store the results up front, so components built before a failure are kept
+	if results.IsValid() {
+		results.Set(reflect.MakeSlice(results.Type(), field.Len(), field.Len()))
+	}
+
+	for j := 0; j < field.Len(); j++ {
+		elemPath := appendPath(fieldPath, fmt.Sprintf("[%d]", j))
+		factory := field.Index(j)
+		if factory.IsNil() {
+			logger.Trace().
+				Str("path", pathToString(elemPath)).
+				Msg("Skipping nil factory")
+			continue
+		}
+
+		var component reflect.Value
+		if err := protect(options, func() error {
+			build := factory.Convert(componentFactoryType).Interface().(ComponentFactory)
+			component = reflect.ValueOf(build())
+			return nil
+		}); err != nil {
+			return &InitError{
+				Path:       elemPath,
+				FieldIndex: index,
+				FieldType:  factory.Type().String(),
+				Cause:      err,
+			}
+		}
+		if !component.IsValid() || isNilValue(component) {
+			logger.Trace().
+				Str("path", pathToString(elemPath)).
+				Msg("Factory returned nil")
+			continue
+		}
+
+		if results.IsValid() {
+			elemType := results.Type().Elem()
+			if !component.Type().AssignableTo(elemType) {
+				return &InitError{
+					Path:       elemPath,
+					FieldIndex: index,
+					FieldType:  component.Type().String(),
+					Cause:      fmt.Errorf("factory result is not assignable to %s", elemType),
+				}
+			}
+			results.Index(j).Set(component)
+		}
+
+		if err := initStructWithVisited(ctx, component, v, elemPath, index, logger, visited, options); err != nil {
+			return err
+		}
+	}
+
+	return callPostFieldHook(ctx, v, fieldName, field, logger, options)
+}
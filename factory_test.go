@@ -0,0 +1,106 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type factoryPlugin struct {
+	Name        string
+	Initialized bool
+	Shutdowns   *int
+}
+
+func (p *factoryPlugin) Init() error {
+	if p.Name == "broken" {
+		return errors.New("plugin failed")
+	}
+	p.Initialized = true
+	return nil
+}
+
+func (p *factoryPlugin) Shutdown(ctx context.Context) error {
+	*p.Shutdowns++
+	return nil
+}
+
+func TestFactoryListField(t *testing.T) {
+	type App struct {
+		Factories []ComponentFactory `autoinit:"results=Plugins"`
+		Plugins   []*factoryPlugin
+	}
+
+	var shutdowns int
+	newPlugin := func(name string) ComponentFactory {
+		return func() interface{} { return &factoryPlugin{Name: name, Shutdowns: &shutdowns} }
+	}
+	app := &App{Factories: []ComponentFactory{
+		newPlugin("a"),
+		nil,
+		func() interface{} { return nil },
+		newPlugin("b"),
+	}}
+	report := &InitReport{}
+	if err := WithOptions(context.Background(), app, &Options{Report: report}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(app.Plugins) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(app.Plugins))
+	}
+	if app.Plugins[1] != nil || app.Plugins[2] != nil {
+		t.Errorf("nil factories and results should leave nil entries: %+v", app.Plugins)
+	}
+	for _, i := range []int{0, 3} {
+		if p := app.Plugins[i]; p == nil || !p.Initialized {
+			t.Errorf("plugin %d not built and initialized: %+v", i, p)
+		}
+	}
+	report.AssertInitialized(t, "Factories.[0]", "Factories.[3]")
+
+	// The results field keeps the components reachable by tree walks
+	if err := AutoShutdown(context.Background(), app, nil); err != nil {
+		t.Fatalf("AutoShutdown failed: %v", err)
+	}
+	if shutdowns != 2 {
+		t.Errorf("expected 2 shutdowns, got %d", shutdowns)
+	}
+}
+
+func TestFactoryListFieldErrors(t *testing.T) {
+	type Untyped struct {
+		Factories  []func() interface{} `autoinit:"results=Components"`
+		Components []interface{}
+	}
+	app := &Untyped{Factories: []func() interface{}{
+		func() interface{} { return &factoryPlugin{Name: "ok"} },
+		func() interface{} { return &factoryPlugin{Name: "broken"} },
+	}}
+	err := AutoInit(context.Background(), app)
+	var initErr *InitError
+	if !errors.As(err, &initErr) || pathToString(initErr.GetPath()) != "Factories.[1]" {
+		t.Fatalf("expected failure at Factories.[1], got %v", err)
+	}
+	if len(app.Components) != 2 || !app.Components[0].(*factoryPlugin).Initialized {
+		t.Errorf("components built before the failure should be stored: %+v", app.Components)
+	}
+
+	type Mismatched struct {
+		Factories []ComponentFactory `autoinit:"results=Names"`
+		Names     []string
+	}
+	err = AutoInit(context.Background(), &Mismatched{Factories: []ComponentFactory{
+		func() interface{} { return &factoryPlugin{} },
+	}})
+	if err == nil {
+		t.Error("expected an error for a result not assignable to the results field")
+	}
+
+	type Missing struct {
+		Factories []ComponentFactory `autoinit:"results=Nowhere"`
+	}
+	if err := AutoInit(context.Background(), &Missing{}); err == nil {
+		t.Error("expected an error for a missing results field")
+	}
+}
@@ -0,0 +1,79 @@
+package autoinit
+
+import (
+	"context"
+	"testing"
+)
+
+type featureAnalytics struct {
+	Endpoint    string
+	initialized bool
+	shutdown    bool
+}
+
+func (a *featureAnalytics) Init() error {
+	a.initialized = true
+	return nil
+}
+
+func (a *featureAnalytics) Shutdown(ctx context.Context) error {
+	a.shutdown = true
+	return nil
+}
+
+func TestFeatureTag(t *testing.T) {
+	type App struct {
+		Core      *featureAnalytics
+		Analytics *featureAnalytics `autoinit:"feature=beta_analytics"`
+	}
+	newApp := func() *App {
+		return &App{Core: &featureAnalytics{}, Analytics: &featureAnalytics{}}
+	}
+	features := map[string]bool{"beta_analytics": true}
+	options := &Options{FeatureEnabled: func(name string) bool { return features[name] }}
+
+	// Enabled: the field is initialized and shut down
+	app := newApp()
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !app.Core.initialized || !app.Analytics.initialized {
+		t.Error("expected both components to be initialized with the feature on")
+	}
+	if err := AutoShutdown(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if !app.Analytics.shutdown {
+		t.Error("expected the feature component to be shut down")
+	}
+
+	// Disabled: the field is skipped and reported
+	features["beta_analytics"] = false
+	app = newApp()
+	report := &InitReport{}
+	options.Report = report
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !app.Core.initialized || app.Analytics.initialized {
+		t.Error("expected only the core component to be initialized with the feature off")
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].Reason != SkipFeatureDisabled {
+		t.Errorf("skipped = %v, want Analytics with SkipFeatureDisabled", report.Skipped)
+	}
+	if err := AutoShutdown(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if app.Analytics.shutdown {
+		t.Error("a component of a disabled feature was shut down")
+	}
+
+	// Without FeatureEnabled every feature is off
+	app = newApp()
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Analytics.initialized {
+		t.Error("a feature component was initialized without FeatureEnabled")
+	}
+}
@@ -0,0 +1,98 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+)
+
+// TypedFieldHooks is implemented by parents that only care about children of
+// certain types. Instead of switching on the field name in PreFieldInit and
+// PostFieldInit, the parent lists a FieldHook per type:
+//
+//	func (a *App) FieldHooks() []autoinit.FieldHook {
+//	    return []autoinit.FieldHook{
+//	        autoinit.OnFieldInit(func(ctx context.Context, name string, h http.Handler) error {
+//	            a.router.Handle("/"+name, h)
+//	            return nil
+//	        }),
+//	    }
+//	}
+//
+// FieldHooks is called for every field the field hooks fire for. A hook's
+// Pre runs after PreFieldInit and before PreFieldInitContext, its Post after
+// PostFieldInit. Options.DisableFieldHooks disables them as well.
+type TypedFieldHooks interface {
+	FieldHooks() []FieldHook
+}
+
+// FieldHook is a field hook restricted to fields whose value matches Type by
+// the rules of TypeMatches. The fieldValue passed to Pre and Post is the same
+// as for PreFieldInit: the pointer held by a pointer field, otherwise a
+// pointer to the field. Either function may be nil. Use OnFieldInit and
+// BeforeFieldInit to build hooks with typed callbacks.
+type FieldHook struct {
+	Type reflect.Type
+	Pre  func(ctx context.Context, fieldName string, fieldValue interface{}) error
+	Post func(ctx context.Context, fieldName string, fieldValue interface{}) error
+}
+
+// OnFieldInit returns a FieldHook that calls fn after a field of type T was
+// initialized, like PostFieldInit
+func OnFieldInit[T any](fn func(ctx context.Context, fieldName string, field T) error) FieldHook {
+	return FieldHook{Type: reflect.TypeOf((*T)(nil)).Elem(), Post: typedFieldFunc(fn)}
+}
+
+// BeforeFieldInit returns a FieldHook that calls fn before a field of type T
+// is initialized, like PreFieldInit
+func BeforeFieldInit[T any](fn func(ctx context.Context, fieldName string, field T) error) FieldHook {
+	return FieldHook{Type: reflect.TypeOf((*T)(nil)).Elem(), Pre: typedFieldFunc(fn)}
+}
+
+// typedFieldFunc adapts a typed callback to the fieldValue passed to field
+// hooks, dereferencing the pointer to a value field if T is the field's type
+func typedFieldFunc[T any](fn func(ctx context.Context, fieldName string, field T) error) func(context.Context, string, interface{}) error {
+	return func(ctx context.Context, fieldName string, fieldValue interface{}) error {
+		if typed, ok := fieldValue.(T); ok {
+			return fn(ctx, fieldName, typed)
+		}
+		if v := reflect.ValueOf(fieldValue); v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().CanInterface() {
+			if typed, ok := v.Elem().Interface().(T); ok {
+				return fn(ctx, fieldName, typed)
+			}
+		}
+		return nil
+	}
+}
+
+// callTypedFieldHooks calls the Pre (or Post) functions of the parent's
+// FieldHooks whose type matches fieldValue
+func callTypedFieldHooks(ctx context.Context, hook interface{}, fieldName string, fieldValue interface{}, post bool) error {
+	h, ok := hook.(TypedFieldHooks)
+	if !ok || fieldValue == nil {
+		return nil
+	}
+	value := reflect.ValueOf(fieldValue)
+	for _, fieldHook := range h.FieldHooks() {
+		fn := fieldHook.Pre
+		if post {
+			fn = fieldHook.Post
+		}
+		if fn == nil || !fieldHookMatches(value, fieldHook.Type) {
+			continue
+		}
+		if err := fn(ctx, fieldName, fieldValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldHookMatches reports whether a field, passed to hooks as value, matches
+// target. value is usually a pointer to the field, so the field itself is
+// tried as well.
+func fieldHookMatches(value reflect.Value, target reflect.Type) bool {
+	if TypeMatches(value, target) {
+		return true
+	}
+	return value.Kind() == reflect.Ptr && !value.IsNil() && TypeMatches(value.Elem(), target)
+}
@@ -0,0 +1,111 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fieldHookDatabase struct {
+	DSN         string
+	initialized bool
+}
+
+func (d *fieldHookDatabase) Init() error {
+	d.initialized = true
+	return nil
+}
+
+type fieldHookCache struct {
+	Size int
+}
+
+type fieldHookNamed interface {
+	HookName() string
+}
+
+type fieldHookPlugin struct {
+	Name string
+}
+
+func (p *fieldHookPlugin) HookName() string { return p.Name }
+
+// fieldHookApp only hooks into its databases and named plugins
+type fieldHookApp struct {
+	Primary *fieldHookDatabase
+	Replica fieldHookDatabase
+	Cache   *fieldHookCache
+	Plugin  *fieldHookPlugin
+
+	before []string
+	after  []string
+	failOn string
+}
+
+func (a *fieldHookApp) FieldHooks() []FieldHook {
+	return []FieldHook{
+		BeforeFieldInit(func(ctx context.Context, name string, db *fieldHookDatabase) error {
+			if db.initialized {
+				return errors.New("database initialized before its pre hook")
+			}
+			a.before = append(a.before, name)
+			return nil
+		}),
+		OnFieldInit(func(ctx context.Context, name string, db *fieldHookDatabase) error {
+			if !db.initialized {
+				return errors.New("database not initialized in its post hook")
+			}
+			if name == a.failOn {
+				return errors.New("rejected " + name)
+			}
+			a.after = append(a.after, name)
+			return nil
+		}),
+		OnFieldInit(func(ctx context.Context, name string, named fieldHookNamed) error {
+			a.after = append(a.after, "named:"+named.HookName())
+			return nil
+		}),
+	}
+}
+
+func TestTypedFieldHooks(t *testing.T) {
+	newApp := func() *fieldHookApp {
+		return &fieldHookApp{
+			Primary: &fieldHookDatabase{},
+			Cache:   &fieldHookCache{},
+			Plugin:  &fieldHookPlugin{Name: "metrics"},
+		}
+	}
+
+	app := newApp()
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(app.before, ",") != "Primary,Replica" {
+		t.Errorf("pre hooks called for %v", app.before)
+	}
+	if strings.Join(app.after, ",") != "Primary,Replica,named:metrics" {
+		t.Errorf("post hooks called for %v", app.after)
+	}
+
+	// A hook error fails the field like a PostFieldInit error
+	app = newApp()
+	app.failOn = "Replica"
+	if err := AutoInit(context.Background(), app); err == nil || !strings.Contains(err.Error(), "rejected Replica") {
+		t.Errorf("expected the hook error, got %v", err)
+	}
+
+	// DisableFieldHooks disables typed hooks too
+	app = newApp()
+	if err := WithOptions(context.Background(), app, &Options{DisableFieldHooks: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(app.before) != 0 || len(app.after) != 0 {
+		t.Errorf("typed hooks ran with DisableFieldHooks: %v %v", app.before, app.after)
+	}
+
+	if !ComponentCapabilities(app).TypedFieldHooks {
+		t.Error("TypedFieldHooks capability not reported")
+	}
+}
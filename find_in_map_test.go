@@ -0,0 +1,92 @@
+package autoinit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/telnet2/autoinit"
+)
+
+type mapService struct {
+	Name string
+}
+
+type serviceName string
+
+// mapConsumer looks up services by key from its position in the tree
+type mapConsumer struct {
+	Key      interface{}
+	Service  *mapService
+	Value    mapService
+	Found    bool
+	FoundVal bool
+}
+
+func (c *mapConsumer) Init(ctx context.Context, parent interface{}) error {
+	c.Service, c.Found = autoinit.FindInMap[*mapService](ctx, c, parent, c.Key)
+	c.Value, c.FoundVal = autoinit.FindInMap[mapService](ctx, c, parent, c.Key)
+	return nil
+}
+
+type mapModule struct {
+	Consumer *mapConsumer
+}
+
+type mapApp struct {
+	Services map[string]*mapService
+	Values   map[serviceName]mapService
+	Consumer *mapConsumer
+	Module   *mapModule
+}
+
+func TestFindInMap(t *testing.T) {
+	billing := &mapService{Name: "billing"}
+	app := &mapApp{
+		Services: map[string]*mapService{"billing": billing},
+		Values:   map[serviceName]mapService{"billing": {Name: "billing-value"}},
+		Consumer: &mapConsumer{Key: "billing"},
+		Module:   &mapModule{Consumer: &mapConsumer{Key: "billing"}},
+	}
+	if err := autoinit.AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, consumer := range map[string]*mapConsumer{"sibling": app.Consumer, "nested": app.Module.Consumer} {
+		if !consumer.Found || consumer.Service != billing {
+			t.Errorf("%s: expected the billing service from the Services map, got %v", name, consumer.Service)
+		}
+		// "billing" is converted to the serviceName key type
+		if !consumer.FoundVal || consumer.Value.Name != "billing-value" {
+			t.Errorf("%s: expected a copy of the billing value, got %+v", name, consumer.Value)
+		}
+	}
+
+	// Elements of a value map are copies
+	app.Consumer.Value.Name = "changed"
+	if app.Values["billing"].Name != "billing-value" {
+		t.Errorf("changing the copy should not change the map element")
+	}
+
+	missing := &mapApp{
+		Services: map[string]*mapService{"billing": billing},
+		Consumer: &mapConsumer{Key: "shipping"},
+	}
+	if err := autoinit.AutoInit(context.Background(), missing); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if missing.Consumer.Found || missing.Consumer.Service != nil {
+		t.Errorf("expected no service for a missing key, got %v", missing.Consumer.Service)
+	}
+
+	// Keys of another kind never match
+	wrongKind := &mapApp{
+		Services: map[string]*mapService{"1": billing},
+		Consumer: &mapConsumer{Key: 1},
+	}
+	if err := autoinit.AutoInit(context.Background(), wrongKind); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wrongKind.Consumer.Found {
+		t.Errorf("expected an int key not to match a string-keyed map")
+	}
+}
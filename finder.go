@@ -2,10 +2,32 @@ package autoinit
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"reflect"
 	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
 )
 
+// DefaultMaxSearchDepth is the number of ancestor levels a finder search
+// climbs above the searching component before giving up, unless
+// Options.MaxSearchDepth, WithMaxSearchDepth or SearchOption.MaxDepth sets
+// another limit. It bounds searches from components nested in very deep or
+// runaway trees; a search cut short by it logs a warning.
+const DefaultMaxSearchDepth = 100
+
+const searchDepthKey contextKey = "autoinit:searchDepth"
+
+// WithMaxSearchDepth returns a context whose finder searches climb at most
+// depth ancestor levels, for searches made outside AutoInit; within a run,
+// set Options.MaxSearchDepth. A depth of 0 or less restores
+// DefaultMaxSearchDepth.
+func WithMaxSearchDepth(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, searchDepthKey, depth)
+}
+
 // SearchOption configures how to search for components
 type SearchOption struct {
 	// Search by type match
@@ -20,6 +42,78 @@ type SearchOption struct {
 	// Search by custom tag
 	ByCustomTag string
 	TagKey      string // e.g., "component" for `component:"cache"`
+
+	// Search by custom tag value prefix and/or suffix, under TagKey. With both
+	// set a value must have both, e.g. prefix "db-" and suffix "-ro" match
+	// `component:"db-orders-ro"`. Use FindAll to collect every match.
+	ByTagPrefix string
+	ByTagSuffix string
+
+	// Restrict the search to the subtree rooted at the nearest ancestor of this
+	// type, e.g. to find the Cache of the enclosing Module rather than one of
+	// the App. The ancestor's own fields are searched, levels above it are not.
+	// Nothing is found if there is no such ancestor.
+	WithinAncestorType reflect.Type
+
+	// MatchEmbedded widens ByType to components that embed it, directly or
+	// through other embedded structs, e.g. to find anything that embeds
+	// BaseComponent. The embedded value is returned rather than the component,
+	// addressed within it when possible, so results still fit ByType.
+	MatchEmbedded bool
+
+	// Search by capability: components implementing the lifecycle interface
+	// it names, e.g. CapabilityShutdown, whatever their type. Use FindAll to
+	// collect them all, e.g. for a shutdown manager.
+	ByCapability Capability
+
+	// MaxDepth limits the number of ancestor levels searched, overriding the
+	// limit of the context (see WithMaxSearchDepth); 0 uses that limit
+	MaxDepth int
+}
+
+// String describes the criteria set on the option, for logging
+func (opt *SearchOption) String() string {
+	if opt == nil {
+		return "<nil>"
+	}
+	var criteria []string
+	if opt.ByType != nil {
+		if opt.MatchEmbedded {
+			criteria = append(criteria, "embeds="+opt.ByType.String())
+		} else {
+			criteria = append(criteria, "type="+opt.ByType.String())
+		}
+	}
+	if opt.ByFieldName != "" {
+		criteria = append(criteria, "field="+opt.ByFieldName)
+	}
+	if opt.ByJSONTag != "" {
+		criteria = append(criteria, "json="+opt.ByJSONTag)
+	}
+	if opt.ByCustomTag != "" && opt.TagKey != "" {
+		criteria = append(criteria, opt.TagKey+"="+opt.ByCustomTag)
+	}
+	if (opt.ByTagPrefix != "" || opt.ByTagSuffix != "") && opt.TagKey != "" {
+		criteria = append(criteria, opt.TagKey+"="+opt.ByTagPrefix+"*"+opt.ByTagSuffix)
+	}
+	if opt.ByCapability != "" {
+		criteria = append(criteria, "capability="+string(opt.ByCapability))
+	}
+	if opt.WithinAncestorType != nil {
+		criteria = append(criteria, "within="+opt.WithinAncestorType.String())
+	}
+	return strings.Join(criteria, " ")
+}
+
+// maxDepth returns the number of ancestor levels a search with opt may climb
+func (cf *ComponentFinder) maxDepth(opt *SearchOption) int {
+	if opt != nil && opt.MaxDepth > 0 {
+		return opt.MaxDepth
+	}
+	if depth, ok := cf.ctx.Value(searchDepthKey).(int); ok && depth > 0 {
+		return depth
+	}
+	return DefaultMaxSearchDepth
 }
 
 // ComponentFinder provides methods to find related components
@@ -43,8 +137,70 @@ func NewComponentFinder(ctx context.Context, self, parent interface{}) *Componen
 // 1. Siblings at current level
 // 2. Parent's siblings (aunts/uncles)
 // 3. Grandparent's siblings, etc.
+// 4. Roots added with WithAdditionalRoots
 func (cf *ComponentFinder) Find(opt *SearchOption) interface{} {
-	return cf.searchHierarchy(cf.parent, cf.self, opt, 0)
+	var result interface{}
+	if scope, ok := cf.scopeLevel(opt); ok {
+		cf.searchHierarchy(cf.parent, cf.self, opt, 0, scope, func(found interface{}) bool {
+			result = found
+			return true
+		})
+	}
+	if result == nil {
+		cf.searchRoots(opt, func(found interface{}) bool {
+			result = found
+			return true
+		})
+	}
+	if result == nil {
+		cf.logger().Debug().
+			Str("option", opt.String()).
+			Msg("Finder: no matching component found")
+	} else {
+		cf.recordFound(result, "Find", opt)
+	}
+	return result
+}
+
+// recordFound records in the run's report that the finder's component found
+// result with method
+func (cf *ComponentFinder) recordFound(result interface{}, method string, opt *SearchOption) {
+	recordDiscovery(cf.ctx, cf.self, reflect.ValueOf(result), describeSearch(method, nil, opt.String()))
+}
+
+// FindAll returns every component matching opt, in the order Find would
+// consider them: siblings first, then each ancestor level up to the root (or
+// to opt.WithinAncestorType), then any additional roots. Combined with
+// ByTagPrefix it collects components grouped by a tag naming convention:
+//
+//	dbs := finder.FindAll(&autoinit.SearchOption{TagKey: "component", ByTagPrefix: "db-"})
+func (cf *ComponentFinder) FindAll(opt *SearchOption) []interface{} {
+	var results []interface{}
+	collect := func(found interface{}) bool {
+		if !containsComponent(results, found) {
+			results = append(results, found)
+		}
+		return false
+	}
+	if scope, ok := cf.scopeLevel(opt); ok {
+		cf.searchHierarchy(cf.parent, cf.self, opt, 0, scope, collect)
+	}
+	cf.searchRoots(opt, collect)
+	for _, result := range results {
+		cf.recordFound(result, "FindAll", opt)
+	}
+	return results
+}
+
+// containsComponent reports whether found is already in results. Levels of the
+// hierarchy can overlap, so the same component may be reached twice.
+func containsComponent(results []interface{}, found interface{}) bool {
+	for _, result := range results {
+		if sameComponent(result, found) {
+			return true
+		}
+	}
+	return false
 }
 
 // FindSibling searches only among siblings at the same level
@@ -52,30 +208,81 @@ func (cf *ComponentFinder) FindSibling(opt *SearchOption) interface{} {
 	if cf.parent == nil {
 		return nil
 	}
-	return cf.searchSiblings(cf.parent, cf.self, opt)
+	if _, ok := cf.scopeLevel(opt); !ok {
+		return nil
+	}
+	var result interface{}
+	cf.searchSiblings(cf.parent, cf.self, opt, func(found interface{}) bool {
+		result = found
+		return true
+	})
+	if result != nil {
+		cf.recordFound(result, "FindSibling", opt)
+	}
+	return result
 }
 
 // FindAncestor searches up the parent chain for a matching component
 func (cf *ComponentFinder) FindAncestor(opt *SearchOption) interface{} {
-	return cf.searchAncestors(cf.parent, opt)
+	scope, ok := cf.scopeLevel(opt)
+	if !ok {
+		return nil
+	}
+	result := cf.searchAncestors(cf.parent, opt, scope)
+	if result != nil {
+		cf.recordFound(result, "FindAncestor", opt)
+	}
+	return result
 }
 
-// searchHierarchy implements the full search algorithm
-func (cf *ComponentFinder) searchHierarchy(current interface{}, exclude interface{}, opt *SearchOption, depth int) interface{} {
-	if current == nil || depth > 10 { // Prevent infinite recursion
-		return nil
+// scopeLevel returns the highest parent chain level (1 = parent) a search may
+// reach: the level of the nearest ancestor of opt.WithinAncestorType, or no
+// limit if it is unset. ok is false if there is no such ancestor.
+func (cf *ComponentFinder) scopeLevel(opt *SearchOption) (level int, ok bool) {
+	if opt == nil || opt.WithinAncestorType == nil {
+		return math.MaxInt, true
+	}
+	if chain := cf.getParentChain(); chain != nil && chain.Len() > 0 {
+		// Level 0 of the chain is the component itself
+		for level := 1; level < chain.Len(); level++ {
+			if TypeMatches(reflect.ValueOf(chain.GetParent(level)), opt.WithinAncestorType) {
+				return level, true
+			}
+		}
+		return 0, false
+	}
+	// Without a chain only the parent is known
+	if TypeMatches(reflect.ValueOf(cf.parent), opt.WithinAncestorType) {
+		return 1, true
+	}
+	return 0, false
+}
+
+// searchHierarchy implements the full search algorithm, up to parent chain
+// level scope. Matches are passed to found, which returns true to stop the
+// search; searchHierarchy reports whether it was stopped.
+func (cf *ComponentFinder) searchHierarchy(current interface{}, exclude interface{}, opt *SearchOption, depth int, scope int, found func(interface{}) bool) bool {
+	if current == nil {
+		return false
 	}
 
 	// Step 1: Search siblings at current level
-	if result := cf.searchSiblings(current, exclude, opt); result != nil {
-		return result
+	if cf.searchSiblings(current, exclude, opt, found) {
+		return true
 	}
 
 	// Step 2: Get parent chain from context to search higher levels
 	if chain := cf.getParentChain(); chain != nil {
+		maxDepth := cf.maxDepth(opt)
+		ancestors := chain.ancestors()
 		// Search each level up the hierarchy
-		for i := depth + 1; i < len(chain.chain); i++ {
-			ancestor := chain.chain[len(chain.chain)-1-i]
+		for i := depth + 1; i < len(ancestors) && i <= scope; i++ {
+			if i > maxDepth {
+				cf.logTruncated(opt, maxDepth, chain)
+				break
+			}
+
+			ancestor := ancestors[len(ancestors)-1-i]
 			if ancestor == nil {
 				continue
 			}
@@ -83,32 +290,37 @@ func (cf *ComponentFinder) searchHierarchy(current interface{}, exclude interfac
 			// Search siblings at this ancestor level
 			// Exclude the child that we came from
 			var excludeAtLevel interface{}
-			if i > 0 && i-1 < len(chain.chain) {
-				excludeAtLevel = chain.chain[len(chain.chain)-i]
+			if i > 0 && i-1 < len(ancestors) {
+				excludeAtLevel = ancestors[len(ancestors)-i]
 			}
 
-			if result := cf.searchSiblings(ancestor, excludeAtLevel, opt); result != nil {
-				return result
+			if cf.searchSiblings(ancestor, excludeAtLevel, opt, found) {
+				return true
 			}
 		}
 	}
 
-	return nil
+	return false
 }
 
 // searchSiblings searches among sibling components in the same parent
-func (cf *ComponentFinder) searchSiblings(parent interface{}, exclude interface{}, opt *SearchOption) interface{} {
+func (cf *ComponentFinder) searchSiblings(parent interface{}, exclude interface{}, opt *SearchOption, found func(interface{}) bool) bool {
 	if parent == nil {
-		return nil
+		return false
 	}
 
 	v := reflect.ValueOf(parent)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
+	return cf.searchSiblingsValue(v, exclude, opt, found)
+}
 
+// searchSiblingsValue searches the fields of struct value v, passing matches
+// to found until it returns true
+func (cf *ComponentFinder) searchSiblingsValue(v reflect.Value, exclude interface{}, opt *SearchOption, found func(interface{}) bool) bool {
 	if v.Kind() != reflect.Struct {
-		return nil
+		return false
 	}
 
 	t := v.Type()
@@ -117,36 +329,51 @@ func (cf *ComponentFinder) searchSiblings(parent interface{}, exclude interface{
 		field := v.Field(i)
 		fieldType := t.Field(i)
 
-		// Skip unexported fields
+		// Skip unexported fields, but still search unexported embedded structs:
+		// their exported fields are promoted and remain accessible
 		if !field.CanInterface() {
+			if fieldType.Anonymous {
+				if cf.searchSiblingsValue(embeddedStruct(field), exclude, opt, found) {
+					return true
+				}
+			}
+			cf.logRejected(t, fieldType.Name, opt, "unexported")
 			continue
 		}
 
 		// Skip self
 		fieldInterface := field.Interface()
-		if fieldInterface == exclude {
+		if sameComponent(fieldInterface, exclude) {
+			cf.logRejected(t, fieldType.Name, opt, "self")
 			continue
 		}
 
 		// Skip nil pointers
 		if field.Kind() == reflect.Ptr && field.IsNil() {
+			cf.logRejected(t, fieldType.Name, opt, "nil")
 			continue
 		}
 
 		// Check if this field matches our search criteria
 		if cf.matchesOption(field, &fieldType, opt) {
-			// For value types, return a pointer if the field is addressable
-			// This allows the found component to be modified
-			if field.Kind() != reflect.Ptr && field.CanAddr() {
-				return field.Addr().Interface()
+			cf.logger().Debug().
+				Str("in", t.String()).
+				Str("field", fieldType.Name).
+				Str("option", opt.String()).
+				Msg("Finder: found component")
+
+			if found(cf.resultOf(field, opt)) {
+				return true
 			}
-			return fieldInterface
+			continue
 		}
+		cf.logRejected(t, fieldType.Name, opt, "no criterion matches")
 
-		// For embedded structs, search their fields too
-		if fieldType.Anonymous && (field.Kind() == reflect.Struct || (field.Kind() == reflect.Ptr && field.Elem().Kind() == reflect.Struct)) {
-			if result := cf.searchSiblings(fieldInterface, exclude, opt); result != nil {
-				return result
+		// For embedded structs, search their fields too. Searching the value
+		// rather than a copy keeps value fields addressable.
+		if fieldType.Anonymous {
+			if cf.searchSiblingsValue(embeddedStruct(field), exclude, opt, found) {
+				return true
 			}
 		}
 
@@ -156,12 +383,10 @@ func (cf *ComponentFinder) searchSiblings(parent interface{}, exclude interface{
 				elem := field.Index(j)
 				if elem.CanInterface() {
 					elemInterface := elem.Interface()
-					if elemInterface != exclude && cf.matchesValue(elem, opt) {
-						// For value types in collections, return pointer if addressable
-						if elem.Kind() != reflect.Ptr && elem.CanAddr() {
-							return elem.Addr().Interface()
+					if !sameComponent(elemInterface, exclude) && cf.matchesValue(elem, opt) {
+						if found(cf.resultOf(elem, opt)) {
+							return true
 						}
-						return elemInterface
 					}
 				}
 			}
@@ -173,24 +398,53 @@ func (cf *ComponentFinder) searchSiblings(parent interface{}, exclude interface{
 				val := field.MapIndex(key)
 				if val.CanInterface() {
 					valInterface := val.Interface()
-					if valInterface != exclude && cf.matchesValue(val, opt) {
+					if !sameComponent(valInterface, exclude) && cf.matchesValue(val, opt) {
 						// Map values are not addressable, so we can't return pointers
 						// This is a Go limitation
-						return valInterface
+						if found(cf.resultOf(val, opt)) {
+							return true
+						}
 					}
 				}
 			}
 		}
 	}
 
-	return nil
+	return false
+}
+
+// logger returns the logger of the AutoInit run the finder was created in
+func (cf *ComponentFinder) logger() *zerolog.Logger {
+	return runLogger(cf.ctx)
+}
+
+// logRejected records at debug level why a field was not returned
+func (cf *ComponentFinder) logRejected(owner reflect.Type, field string, opt *SearchOption, reason string) {
+	cf.logger().Debug().
+		Str("in", owner.String()).
+		Str("field", field).
+		Str("option", opt.String()).
+		Str("reason", reason).
+		Msg("Finder: rejected candidate")
+}
+
+// logTruncated warns that a search stopped at maxDepth before reaching the
+// top of chain
+func (cf *ComponentFinder) logTruncated(opt *SearchOption, maxDepth int, chain *ParentChain) {
+	cf.logger().Warn().
+		Str("option", opt.String()).
+		Int("max_depth", maxDepth).
+		Int("levels", chain.Len()-1).
+		Msg("Finder: search truncated; raise SearchOption.MaxDepth or Options.MaxSearchDepth")
+	recordWarning(cf.ctx, WarningSearchTruncated, nil,
+		fmt.Sprintf("search for %s stopped at depth %d of %d", opt, maxDepth, chain.Len()-1))
 }
 
 // matchesOption checks if a field matches the search criteria
 func (cf *ComponentFinder) matchesOption(field reflect.Value, fieldType *reflect.StructField, opt *SearchOption) bool {
 	// Match by type
 	if opt.ByType != nil {
-		if cf.matchesType(field, opt.ByType) {
+		if _, ok := matchCandidate(field, opt.ByType, opt.MatchEmbedded); ok {
 			return true
 		}
 	}
@@ -204,9 +458,7 @@ func (cf *ComponentFinder) matchesOption(field reflect.Value, fieldType *reflect
 
 	// Match by JSON tag
 	if opt.ByJSONTag != "" {
-		jsonTag := fieldType.Tag.Get("json")
-		tagName := strings.Split(jsonTag, ",")[0]
-		if tagName == opt.ByJSONTag {
+		if name, ok := jsonTagName(fieldType); ok && name == opt.ByJSONTag {
 			return true
 		}
 	}
@@ -219,75 +471,67 @@ func (cf *ComponentFinder) matchesOption(field reflect.Value, fieldType *reflect
 		}
 	}
 
-	return false
-}
-
-// matchesValue checks if a value matches the search criteria (for elements in collections)
-func (cf *ComponentFinder) matchesValue(val reflect.Value, opt *SearchOption) bool {
-	if opt.ByType != nil {
-		return cf.matchesType(val, opt.ByType)
-	}
-	return false
-}
-
-// matchesType checks if a value matches the target type
-func (cf *ComponentFinder) matchesType(field reflect.Value, targetType reflect.Type) bool {
-	if !field.IsValid() || !field.CanInterface() {
-		return false
-	}
-
-	fieldInterface := field.Interface()
-	fieldType := reflect.TypeOf(fieldInterface)
-
-	// Direct type match
-	if fieldType == targetType {
-		return true
+	// Match by custom tag prefix/suffix
+	if (opt.ByTagPrefix != "" || opt.ByTagSuffix != "") && opt.TagKey != "" {
+		if tagValue, ok := fieldType.Tag.Lookup(opt.TagKey); ok &&
+			strings.HasPrefix(tagValue, opt.ByTagPrefix) && strings.HasSuffix(tagValue, opt.ByTagSuffix) {
+			return true
+		}
 	}
 
-	// If target is a pointer type, check if field type matches
-	if targetType.Kind() == reflect.Ptr && targetType.Elem() == fieldType {
+	// Match by capability
+	if opt.ByCapability != "" && implementsCapability(field, opt.ByCapability) {
 		return true
 	}
 
-	// If field is a pointer, check if its element type matches
-	if fieldType.Kind() == reflect.Ptr && fieldType.Elem() == targetType {
-		return true
-	}
+	return false
+}
 
-	// Check if both are pointers to the same type
-	if targetType.Kind() == reflect.Ptr && fieldType.Kind() == reflect.Ptr {
-		if targetType.Elem() == fieldType.Elem() {
+// matchesValue checks if a value matches the search criteria (for elements in collections)
+func (cf *ComponentFinder) matchesValue(val reflect.Value, opt *SearchOption) bool {
+	if opt.ByType != nil {
+		if _, ok := matchCandidate(val, opt.ByType, opt.MatchEmbedded); ok {
 			return true
 		}
 	}
+	return opt.ByCapability != "" && implementsCapability(val, opt.ByCapability)
+}
 
-	// Check interface implementation
-	if targetType.Kind() == reflect.Interface {
-		if fieldType.Implements(targetType) {
-			return true
-		}
-		// Check if pointer type implements the interface
-		if fieldType.Kind() != reflect.Ptr && reflect.PtrTo(fieldType).Implements(targetType) {
-			return true
+// resultOf returns what a search yields for the matching value v: v itself,
+// or with MatchEmbedded the component embedded in it that matched ByType. Value
+// types are returned as pointers if addressable, so the found component can be
+// modified.
+func (cf *ComponentFinder) resultOf(v reflect.Value, opt *SearchOption) interface{} {
+	if opt.MatchEmbedded && opt.ByType != nil && !TypeMatches(v, opt.ByType) {
+		if embedded, ok := embeddedMatch(v, opt.ByType); ok {
+			v = embedded
 		}
 	}
-
-	return false
+	if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface && v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	return v.Interface()
 }
 
 // searchAncestors searches up the parent chain
-func (cf *ComponentFinder) searchAncestors(parent interface{}, opt *SearchOption) interface{} {
+func (cf *ComponentFinder) searchAncestors(parent interface{}, opt *SearchOption, scope int) interface{} {
 	if chain := cf.getParentChain(); chain != nil {
+		maxDepth := cf.maxDepth(opt)
+		ancestors := chain.ancestors()
 		// Skip the first item (self) and search up
-		for i := 1; i < len(chain.chain); i++ {
-			ancestor := chain.chain[len(chain.chain)-1-i]
+		for i := 1; i < len(ancestors) && i <= scope; i++ {
+			if i > maxDepth {
+				cf.logTruncated(opt, maxDepth, chain)
+				break
+			}
+			ancestor := ancestors[len(ancestors)-1-i]
 			if ancestor == nil {
 				continue
 			}
 
 			// Check if the ancestor itself matches
 			v := reflect.ValueOf(ancestor)
-			if cf.matchesType(v, opt.ByType) {
+			if TypeMatches(v, opt.ByType) {
 				return ancestor
 			}
 		}
@@ -308,18 +552,26 @@ func (cf *ComponentFinder) getParentChain() *ParentChain {
 	return chain
 }
 
-// ParentChain maintains the hierarchy during initialization
+// ParentChain maintains the hierarchy during initialization. It is safe for
+// concurrent use; every AutoInit run pushes onto a chain of its own, seeded
+// with the ancestors of the enclosing run, so nested runs on other goroutines
+// do not see each other's components.
 type ParentChain struct {
+	mu    sync.RWMutex
 	chain []interface{}
 }
 
 // Push adds a parent to the chain
 func (pc *ParentChain) Push(parent interface{}) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
 	pc.chain = append(pc.chain, parent)
 }
 
 // Pop removes the last parent from the chain
 func (pc *ParentChain) Pop() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
 	if len(pc.chain) > 0 {
 		pc.chain = pc.chain[:len(pc.chain)-1]
 	}
@@ -327,6 +579,8 @@ func (pc *ParentChain) Pop() {
 
 // GetParent returns a parent at the specified level (0 = immediate parent)
 func (pc *ParentChain) GetParent(level int) interface{} {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
 	if level >= len(pc.chain) {
 		return nil
 	}
@@ -335,9 +589,92 @@ func (pc *ParentChain) GetParent(level int) interface{} {
 
 // Len returns the depth of the parent chain
 func (pc *ParentChain) Len() int {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
 	return len(pc.chain)
 }
 
+// ancestors returns a copy of the chain, outermost first; nil for a nil chain
+func (pc *ParentChain) ancestors() []interface{} {
+	if pc == nil {
+		return nil
+	}
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return append([]interface{}(nil), pc.chain...)
+}
+
+// AncestorOfType returns the nearest ancestor of the component currently being
+// initialized that is of type T (or implements T if T is an interface).
+// Call it from within Init or a lifecycle hook; the component itself is not
+// considered an ancestor.
+//
+//	if app, ok := autoinit.AncestorOfType[*App](ctx); ok {
+//	    s.config = app.Config
+//	}
+func AncestorOfType[T any](ctx context.Context) (T, bool) {
+	var zero T
+	chain := getParentChain(ctx)
+	if chain == nil {
+		return zero, false
+	}
+	// Level 0 of the chain is the component itself
+	for level := 1; level < chain.Len(); level++ {
+		if typed, ok := chain.GetParent(level).(T); ok {
+			return typed, true
+		}
+	}
+	return zero, false
+}
+
+// AncestorAtLevel returns the ancestor of the component currently being
+// initialized at the given level (0 = immediate parent, 1 = grandparent, ...)
+// if it is of type T. Call it from within Init or a lifecycle hook.
+func AncestorAtLevel[T any](ctx context.Context, level int) (T, bool) {
+	var zero T
+	chain := getParentChain(ctx)
+	if chain == nil || level < 0 {
+		return zero, false
+	}
+	typed, ok := chain.GetParent(level + 1).(T)
+	return typed, ok
+}
+
+// PreviousOfType returns the component of self's type that completed Init
+// just before self in the current AutoInit run, so components of one type can
+// link themselves into a chain as they initialize, e.g. a middleware stack:
+//
+//	func (m *Middleware) Init(ctx context.Context) error {
+//	    if prev, ok := autoinit.PreviousOfType(ctx, m); ok {
+//	        prev.Next = m
+//	    }
+//	    return nil
+//	}
+//
+// Called from Init, before self completed, it returns the last component of
+// the type initialized so far; later, e.g. from Link, the one initialized
+// right before self. self must be a pointer to a struct. The order is the
+// run's Init order, so it follows priority tags and Options.Phases. Fields
+// that point back along the chain should be tagged `autoinit:"weak"`. It
+// returns false for the first component and outside of an AutoInit run.
+func PreviousOfType[T any](ctx context.Context, self T) (T, bool) {
+	var zero T
+	state := getRunState(ctx)
+	if state == nil {
+		return zero, false
+	}
+	key, ok := keyOf(reflect.ValueOf(self))
+	if !ok {
+		return zero, false
+	}
+	prev, ok := state.previous(key)
+	if !ok {
+		return zero, false
+	}
+	typed, ok := prev.(T)
+	return typed, ok
+}
+
 // parentChainKey is the context key for the parent chain
 type contextKey string
 
@@ -351,6 +688,25 @@ func WithComponentSearch(ctx context.Context) context.Context {
 	return context.WithValue(ctx, parentChainKey, chain)
 }
 
+// WithParentChain enables component search with a parent chain pre-populated with
+// the given ancestors, ordered from the outermost (root) to the innermost.
+// Use it when initializing a subtree on its own so that ancestor-based discovery
+// still sees the real ancestors:
+//
+//	ctx := autoinit.WithParentChain(ctx, app, app.Module)
+//	err := autoinit.AutoInit(ctx, app.Module.Service)
+//
+// Like WithComponentSearch it installs a fresh chain, replacing any chain already
+// on ctx. AutoInit starts from a copy of a chain found on the context, so
+// components in the subtree are pushed on top of the seeded ancestors.
+func WithParentChain(ctx context.Context, ancestors ...interface{}) context.Context {
+	chain := &ParentChain{
+		chain: make([]interface{}, 0, len(ancestors)+10),
+	}
+	chain.chain = append(chain.chain, ancestors...)
+	return context.WithValue(ctx, parentChainKey, chain)
+}
+
 // Helper functions for common search patterns
 
 // FindByType searches for a component by its type
@@ -368,6 +724,140 @@ func FindByType[T any](ctx context.Context, self, parent interface{}) T {
 	return zero
 }
 
+// FindAllByType returns every component assignable to T, in the order
+// ComponentFinder.FindAll considers them, each once. T may be a concrete type
+// or an interface, e.g. to collect all implementations of a plugin interface:
+//
+//	plugins := autoinit.FindAllByType[Plugin](ctx, self, parent)
+func FindAllByType[T any](ctx context.Context, self, parent interface{}) []T {
+	finder := NewComponentFinder(ctx, self, parent)
+	var results []T
+	for _, result := range finder.FindAll(&SearchOption{
+		ByType: reflect.TypeOf((*T)(nil)).Elem(),
+	}) {
+		if typed, ok := result.(T); ok {
+			results = append(results, typed)
+		}
+	}
+	return results
+}
+
+// FindInMap returns the element stored under key in a map field holding
+// elements of type T, e.g. the service named "billing" in a Services map:
+//
+//	billing, ok := autoinit.FindInMap[*Service](ctx, self, parent, "billing")
+//
+// The maps searched are the fields of parent, then those of each ancestor in
+// the parent chain and of the additional roots; the first map with elements
+// assignable to T and an entry for key wins. key is converted to the map's key
+// type if it has the same kind, so an untyped "billing" finds the entry of a
+// map[ServiceName]*Service. Map elements are not addressable: for a
+// map of values, e.g. map[string]Service, the result is a copy, and changes to
+// it are not seen by the map; store pointers to share components.
+func FindInMap[T any](ctx context.Context, self, parent, key interface{}) (T, bool) {
+	var zero T
+	want := reflect.TypeOf((*T)(nil)).Elem()
+	finder := NewComponentFinder(ctx, self, parent)
+	for _, owner := range finder.mapOwners() {
+		if v, ok := lookupInMaps(reflect.ValueOf(owner), want, key); ok {
+			recordDependency(ctx, self, v, describeSearch("FindInMap", want, fmt.Sprintf("key=%v", key)))
+			return v.Interface().(T), true
+		}
+	}
+	return zero, false
+}
+
+// mapOwners returns the structs FindInMap searches, nearest first: the
+// parent, its ancestors up to the maximum search depth and the additional roots
+func (cf *ComponentFinder) mapOwners() []interface{} {
+	var owners []interface{}
+	add := func(owner interface{}) {
+		if owner == nil || sameComponent(owner, cf.self) {
+			return
+		}
+		for _, o := range owners {
+			if sameComponent(o, owner) {
+				return
+			}
+		}
+		owners = append(owners, owner)
+	}
+
+	add(cf.parent)
+	if chain := cf.getParentChain(); chain != nil {
+		maxDepth := cf.maxDepth(nil)
+		for level := 1; level < chain.Len() && level <= maxDepth; level++ {
+			add(chain.GetParent(level))
+		}
+	}
+	for _, root := range additionalRoots(cf.ctx) {
+		add(root)
+	}
+	return owners
+}
+
+// lookupInMaps looks key up in the exported map fields of struct v, and of
+// its embedded structs, whose elements are assignable to want
+func lookupInMaps(v reflect.Value, want reflect.Type, key interface{}) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanInterface() {
+			continue
+		}
+		if t.Field(i).Anonymous {
+			if found, ok := lookupInMaps(field, want, key); ok {
+				return found, true
+			}
+			continue
+		}
+		if field.Kind() != reflect.Map || field.IsNil() {
+			continue
+		}
+		elemType := field.Type().Elem()
+		if !elemType.AssignableTo(want) && elemType.Kind() != reflect.Interface {
+			continue
+		}
+		k, ok := mapKey(key, field.Type().Key())
+		if !ok {
+			continue
+		}
+		elem := field.MapIndex(k)
+		if elem.IsValid() && elem.Kind() == reflect.Interface {
+			elem = elem.Elem()
+		}
+		if elem.IsValid() && elem.Type().AssignableTo(want) {
+			return elem, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// mapKey converts key to keyType, if it is of that type or of the same kind
+func mapKey(key interface{}, keyType reflect.Type) (reflect.Value, bool) {
+	k := reflect.ValueOf(key)
+	if !k.IsValid() {
+		return reflect.Value{}, false
+	}
+	switch {
+	case k.Type().AssignableTo(keyType):
+		return k, true
+	case k.Kind() == keyType.Kind() && k.Type().ConvertibleTo(keyType):
+		return k.Convert(keyType), true
+	}
+	return reflect.Value{}, false
+}
+
 // FindByInterface searches for a component that implements an interface
 func FindByInterface[T any](ctx context.Context, self, parent interface{}) T {
 	var zero T
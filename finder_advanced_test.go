@@ -309,3 +309,64 @@ func TestFinderInterfaceWithMixedReceivers(t *testing.T) {
 		t.Error("Should have found at least one ConfigProvider")
 	}
 }
+
+// providerCollector gathers every DataProvider it can discover
+type providerCollector struct {
+	Name  string
+	found []DataProvider
+}
+
+func (c *providerCollector) Init(ctx context.Context, parent interface{}) error {
+	c.found = autoinit.FindAllByType[DataProvider](ctx, c, parent)
+	return nil
+}
+
+func TestFindAllByType(t *testing.T) {
+	type Module struct {
+		Local     *PointerComponent
+		Shared    *PointerComponent
+		Collector *providerCollector
+	}
+	type Root struct {
+		Value  ValueComponent
+		Module *Module
+		Extras []*PointerComponent
+	}
+
+	shared := &PointerComponent{Name: "shared"}
+	root := &Root{
+		Value: ValueComponent{Name: "value"},
+		Module: &Module{
+			Local:     &PointerComponent{Name: "local"},
+			Shared:    shared,
+			Collector: &providerCollector{},
+		},
+		Extras: []*PointerComponent{shared, {Name: "extra"}},
+	}
+	if err := autoinit.AutoInit(context.Background(), root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Siblings first, then the root level; the shared component is returned once
+	var names []string
+	for _, provider := range root.Module.Collector.found {
+		names = append(names, provider.GetData())
+	}
+	want := []string{"local", "shared", "value", "extra"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("FindAllByType found %v, want %v", names, want)
+	}
+
+	// Value fields are returned as pointers to the field
+	for _, provider := range root.Module.Collector.found {
+		if value, ok := provider.(*ValueComponent); ok && value != &root.Value {
+			t.Error("value field was not returned by pointer")
+		}
+	}
+
+	// Concrete types work too
+	concrete := autoinit.FindAllByType[*PointerComponent](context.Background(), root.Module.Collector, root.Module)
+	if len(concrete) != 2 || concrete[0] != root.Module.Local || concrete[1] != shared {
+		t.Errorf("FindAllByType[*PointerComponent] = %v", concrete)
+	}
+}
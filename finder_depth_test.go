@@ -0,0 +1,101 @@
+package autoinit
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type depthConfig struct {
+	Name string
+}
+
+// depthNode nests Next levels below it; the innermost node looks up the
+// depthConfig held by the root with the finder
+type depthNode struct {
+	Level  int
+	Next   *depthNode
+	Found  *depthConfig
+	Search SearchOption
+}
+
+func (n *depthNode) Init(ctx context.Context, parent interface{}) error {
+	if n.Next == nil {
+		n.Found, _ = NewComponentFinder(ctx, n, parent).Find(&n.Search).(*depthConfig)
+	}
+	return nil
+}
+
+type depthRoot struct {
+	Config depthConfig
+	Tree   *depthNode
+}
+
+// newDepthTree returns a tree whose innermost node is levels below the root
+func newDepthTree(levels int, search SearchOption) (*depthRoot, *depthNode) {
+	root := &depthRoot{Config: depthConfig{Name: "root"}}
+	leaf := &depthNode{Level: levels, Search: search}
+	node := leaf
+	for level := levels - 1; level >= 1; level-- {
+		node = &depthNode{Level: level, Next: node}
+	}
+	root.Tree = node
+	return root, leaf
+}
+
+func TestFinderSearchDepth(t *testing.T) {
+	byType := SearchOption{ByType: reflect.TypeOf(depthConfig{})}
+
+	t.Run("deep trees are searched to the root by default", func(t *testing.T) {
+		root, leaf := newDepthTree(12, byType)
+		if err := AutoInit(context.Background(), root); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if leaf.Found != &root.Config {
+			t.Errorf("expected the leaf 12 levels deep to find the root's Config, got %v", leaf.Found)
+		}
+	})
+
+	t.Run("MaxDepth truncates the search with a warning", func(t *testing.T) {
+		limited := byType
+		limited.MaxDepth = 5
+		root, leaf := newDepthTree(12, limited)
+
+		var buf bytes.Buffer
+		logger := zerolog.New(&buf).Level(zerolog.WarnLevel)
+		if err := WithOptions(context.Background(), root, &Options{Logger: &logger}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if leaf.Found != nil {
+			t.Errorf("expected the search to stop before the root, found %v", leaf.Found)
+		}
+		if !strings.Contains(buf.String(), "search truncated") || !strings.Contains(buf.String(), `"max_depth":5`) {
+			t.Errorf("expected a truncation warning, got %q", buf.String())
+		}
+	})
+
+	t.Run("Options.MaxSearchDepth sets the limit of the run", func(t *testing.T) {
+		root, leaf := newDepthTree(12, byType)
+		if err := WithOptions(context.Background(), root, &Options{MaxSearchDepth: 3}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if leaf.Found != nil {
+			t.Errorf("expected the search to stop at MaxSearchDepth, found %v", leaf.Found)
+		}
+
+		// SearchOption.MaxDepth overrides it
+		unlimited := byType
+		unlimited.MaxDepth = 20
+		root, leaf = newDepthTree(12, unlimited)
+		if err := WithOptions(context.Background(), root, &Options{MaxSearchDepth: 3}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if leaf.Found != &root.Config {
+			t.Errorf("expected SearchOption.MaxDepth to override the run's limit, got %v", leaf.Found)
+		}
+	})
+}
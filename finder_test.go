@@ -441,3 +441,120 @@ func TestFinderWithCollections(t *testing.T) {
 		t.Error("Service should have found a cache from the map")
 	}
 }
+
+type ScopedCache struct {
+	Name string
+}
+
+// ScopedService looks up a cache within its enclosing ScopedModule only
+type ScopedService struct {
+	Scoped   *ScopedCache
+	Anywhere *ScopedCache
+	NoScope  *ScopedCache
+}
+
+func (s *ScopedService) Init(ctx context.Context, parent interface{}) error {
+	finder := autoinit.NewComponentFinder(ctx, s, parent)
+	cacheType := reflect.TypeOf(&ScopedCache{})
+	if c := finder.Find(&autoinit.SearchOption{ByType: cacheType, WithinAncestorType: reflect.TypeOf(&ScopedModule{})}); c != nil {
+		s.Scoped = c.(*ScopedCache)
+	}
+	if c := finder.Find(&autoinit.SearchOption{ByType: cacheType}); c != nil {
+		s.Anywhere = c.(*ScopedCache)
+	}
+	if c := finder.Find(&autoinit.SearchOption{ByType: cacheType, WithinAncestorType: reflect.TypeOf(&FinderLogger{})}); c != nil {
+		s.NoScope = c.(*ScopedCache)
+	}
+	return nil
+}
+
+type ScopedGroup struct {
+	Service *ScopedService
+}
+
+type ScopedModule struct {
+	Cache *ScopedCache
+	Group *ScopedGroup
+}
+
+func TestFinderWithinAncestorType(t *testing.T) {
+	type App struct {
+		Cache *ScopedCache
+		ModA  *ScopedModule
+		ModB  *ScopedModule
+	}
+
+	app := &App{
+		Cache: &ScopedCache{Name: "global"},
+		ModA:  &ScopedModule{Cache: &ScopedCache{Name: "a"}, Group: &ScopedGroup{Service: &ScopedService{}}},
+		ModB:  &ScopedModule{Group: &ScopedGroup{Service: &ScopedService{}}},
+	}
+	if err := autoinit.AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Each module sees its own cache, found above the intermediate group
+	a := app.ModA.Group.Service
+	if a.Scoped == nil || a.Scoped.Name != "a" {
+		t.Errorf("ModA scoped cache = %v; want a", a.Scoped)
+	}
+
+	// A module without a cache does not fall back to the global one
+	b := app.ModB.Group.Service
+	if b.Scoped != nil {
+		t.Errorf("ModB scoped cache = %v; want none", b.Scoped.Name)
+	}
+	if b.Anywhere == nil || b.Anywhere.Name != "global" {
+		t.Errorf("ModB unscoped cache = %v; want global", b.Anywhere)
+	}
+
+	// No ancestor of the scope type: nothing is in scope
+	if a.NoScope != nil || b.NoScope != nil {
+		t.Error("expected no result without an ancestor of the scope type")
+	}
+}
+
+func TestFinderByTagPrefixAndSuffix(t *testing.T) {
+	type App struct {
+		Orders   *ScopedCache `component:"db-orders"`
+		Users    *ScopedCache `component:"db-users-ro"`
+		Main     *ScopedCache `component:"cache-main"`
+		Untagged *ScopedCache
+	}
+	app := &App{
+		Orders:   &ScopedCache{Name: "orders"},
+		Users:    &ScopedCache{Name: "users"},
+		Main:     &ScopedCache{Name: "main"},
+		Untagged: &ScopedCache{Name: "untagged"},
+	}
+	finder := autoinit.NewComponentFinder(context.Background(), nil, app)
+
+	names := func(found []interface{}) []string {
+		var result []string
+		for _, c := range found {
+			result = append(result, c.(*ScopedCache).Name)
+		}
+		return result
+	}
+
+	all := finder.FindAll(&autoinit.SearchOption{TagKey: "component", ByTagPrefix: "db-"})
+	if got := names(all); !reflect.DeepEqual(got, []string{"orders", "users"}) {
+		t.Errorf("prefix db- = %v; want [orders users]", got)
+	}
+	all = finder.FindAll(&autoinit.SearchOption{TagKey: "component", ByTagPrefix: "db-", ByTagSuffix: "-ro"})
+	if got := names(all); !reflect.DeepEqual(got, []string{"users"}) {
+		t.Errorf("prefix db- suffix -ro = %v; want [users]", got)
+	}
+	if c := finder.Find(&autoinit.SearchOption{TagKey: "component", ByTagSuffix: "-main"}); c == nil || c.(*ScopedCache).Name != "main" {
+		t.Errorf("suffix -main = %v; want main", c)
+	}
+
+	// Every tagged field has an empty prefix, untagged ones never match
+	all = finder.FindAll(&autoinit.SearchOption{TagKey: "component", ByTagSuffix: "s"})
+	if got := names(all); !reflect.DeepEqual(got, []string{"orders"}) {
+		t.Errorf("suffix s = %v; want [orders]", got)
+	}
+	if all := finder.FindAll(&autoinit.SearchOption{TagKey: "component", ByTagPrefix: "queue-"}); len(all) != 0 {
+		t.Errorf("expected no matches, got %v", names(all))
+	}
+}
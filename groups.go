@@ -0,0 +1,68 @@
+package autoinit
+
+import "context"
+
+const groupKey contextKey = "autoinit:group"
+
+// InitGroup initializes only the components of the named group, for staged
+// startup without restructuring the tree:
+//
+//	type App struct {
+//	    DB      *Database `autoinit:"group=core"`
+//	    Plugins []Plugin  `autoinit:"group=plugins"`
+//	}
+//
+//	autoinit.InitGroup(ctx, app, "core", nil)
+//	autoinit.InitGroup(ctx, app, "plugins", nil)
+//
+// A field tagged `autoinit:"group=name"` puts the component it holds, and all
+// components below it, into that group; a nested group tag starts a new group.
+// Components outside the group are traversed but their lifecycle methods and
+// field hooks are not called. The group "" selects components in no group.
+// AutoInit itself ignores groups and initializes everything.
+func InitGroup(ctx context.Context, target interface{}, group string, options *Options) error {
+	return WithOptions(ctx, target, groupOptions(options, group))
+}
+
+// ShutdownGroup shuts down only the components of the named group, in reverse
+// initialization order. See InitGroup for how group membership is determined.
+func ShutdownGroup(ctx context.Context, target interface{}, group string, options *Options) error {
+	return AutoShutdown(ctx, target, groupOptions(options, group))
+}
+
+// groupOptions returns a copy of options restricted to group
+func groupOptions(options *Options, group string) *Options {
+	var opts Options
+	if options != nil {
+		opts = *options
+	}
+	opts.groupFilter = true
+	opts.group = group
+	return &opts
+}
+
+// withFieldGroup returns ctx for the traversal below a field, entering the
+// group named by its tag if it has one
+func withFieldGroup(ctx context.Context, tag tagOptions) context.Context {
+	if group, ok := tag.value("group"); ok {
+		return context.WithValue(ctx, groupKey, group)
+	}
+	return ctx
+}
+
+// currentGroup returns the group of the component being traversed, "" if none
+func currentGroup(ctx context.Context) string {
+	group, _ := ctx.Value(groupKey).(string)
+	return group
+}
+
+// inSelectedGroup reports whether the component being traversed should run its
+// lifecycle methods under options
+func inSelectedGroup(ctx context.Context, options *Options) bool {
+	return selectsGroup(options, currentGroup(ctx))
+}
+
+// selectsGroup reports whether options select components of group
+func selectsGroup(options *Options, group string) bool {
+	return options == nil || !options.groupFilter || options.group == group
+}
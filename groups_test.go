@@ -0,0 +1,80 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// groupedComponent records its Init and Shutdown calls into a shared log
+type groupedComponent struct {
+	Name  string
+	Child *groupedComponent
+	log   *[]string
+}
+
+func (g *groupedComponent) Init() error {
+	*g.log = append(*g.log, "init "+g.Name)
+	return nil
+}
+
+func (g *groupedComponent) Shutdown(ctx context.Context) error {
+	*g.log = append(*g.log, "shutdown "+g.Name)
+	return nil
+}
+
+type groupedApp struct {
+	Core    *groupedComponent   `autoinit:"group=core"`
+	Plugins []*groupedComponent `autoinit:"group=plugins"`
+	Misc    *groupedComponent
+}
+
+func TestInitGroup(t *testing.T) {
+	var log []string
+	newComponent := func(name string) *groupedComponent {
+		return &groupedComponent{Name: name, log: &log}
+	}
+	app := &groupedApp{
+		Core:    newComponent("db"),
+		Plugins: []*groupedComponent{newComponent("p1"), newComponent("p2")},
+		Misc:    newComponent("misc"),
+	}
+	// Children inherit their ancestor's group
+	app.Core.Child = newComponent("pool")
+
+	logger := zerolog.Nop()
+	options := &Options{Logger: &logger}
+	ctx := context.Background()
+
+	steps := []struct {
+		run      func() error
+		expected []string
+	}{
+		{func() error { return InitGroup(ctx, app, "core", options) }, []string{"init pool", "init db"}},
+		{func() error { return InitGroup(ctx, app, "plugins", options) }, []string{"init p1", "init p2"}},
+		{func() error { return InitGroup(ctx, app, "", options) }, []string{"init misc"}},
+		{func() error { return ShutdownGroup(ctx, app, "plugins", options) }, []string{"shutdown p2", "shutdown p1"}},
+		{func() error { return ShutdownGroup(ctx, app, "core", options) }, []string{"shutdown db", "shutdown pool"}},
+	}
+
+	for i, step := range steps {
+		log = nil
+		if err := step.run(); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(log, step.expected) {
+			t.Errorf("step %d: calls = %v; want %v", i, log, step.expected)
+		}
+	}
+
+	// AutoInit ignores groups
+	log = nil
+	if err := WithOptions(ctx, app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(log) != 5 {
+		t.Errorf("AutoInit should initialize every group, got %v", log)
+	}
+}
@@ -203,3 +203,82 @@ func TestHooksMixedFields(t *testing.T) {
 		}
 	}
 }
+
+// Parent that populates nil collections from its PreFieldInit hook
+type PopulatingParent struct {
+	Workers  []*SimpleInit
+	Registry map[string]*SimpleInit
+	Names    []string
+
+	PreFieldCalls []string
+}
+
+func (p *PopulatingParent) PreFieldInit(ctx context.Context, fieldName string, fieldValue interface{}) error {
+	p.PreFieldCalls = append(p.PreFieldCalls, fieldName)
+	switch fieldName {
+	case "Workers":
+		workers := fieldValue.(*[]*SimpleInit)
+		*workers = append(*workers, &SimpleInit{Name: "w1"}, &SimpleInit{Name: "w2"})
+	case "Registry":
+		registry := fieldValue.(*map[string]*SimpleInit)
+		*registry = map[string]*SimpleInit{"svc": {Name: "svc"}}
+	}
+	return nil
+}
+
+func TestPreFieldHookPopulatesNilCollections(t *testing.T) {
+	parent := &PopulatingParent{}
+
+	if err := AutoInit(context.Background(), parent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Hooks fire for nil collections of initializable types only
+	expected := []string{"Workers", "Registry"}
+	if len(parent.PreFieldCalls) != len(expected) {
+		t.Fatalf("PreFieldInit calls = %v; want %v", parent.PreFieldCalls, expected)
+	}
+	for i, name := range expected {
+		if parent.PreFieldCalls[i] != name {
+			t.Errorf("PreFieldInit call %d = %s; want %s", i, parent.PreFieldCalls[i], name)
+		}
+	}
+
+	// Elements added by the hook are initialized
+	if len(parent.Workers) != 2 {
+		t.Fatalf("expected 2 workers, got %d", len(parent.Workers))
+	}
+	for i, w := range parent.Workers {
+		if !w.Initialized {
+			t.Errorf("Workers[%d] was not initialized", i)
+		}
+	}
+	if !parent.Registry["svc"].Initialized {
+		t.Error("Registry[svc] was not initialized")
+	}
+}
+
+func TestValueArrayElementsInitializedInPlace(t *testing.T) {
+	type Parent struct {
+		Array [2]SimpleInit
+		Slice []SimpleInit
+	}
+
+	parent := &Parent{Slice: make([]SimpleInit, 2)}
+	if err := AutoInit(context.Background(), parent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Elements of an addressable array are addressable, so pointer-receiver
+	// Init runs on the element itself and its changes persist
+	for i := range parent.Array {
+		if !parent.Array[i].Initialized {
+			t.Errorf("Array[%d] was not initialized in place", i)
+		}
+	}
+	for i := range parent.Slice {
+		if !parent.Slice[i].Initialized {
+			t.Errorf("Slice[%d] was not initialized in place", i)
+		}
+	}
+}
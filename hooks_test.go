@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"testing"
+
+	"github.com/rs/zerolog"
 )
 
 const (
@@ -392,3 +394,93 @@ func TestNestedHooks(t *testing.T) {
 		t.Error("Child's Init was not called")
 	}
 }
+
+type regionKey struct{}
+
+// regionReader records the region found on its Init context
+type regionReader struct {
+	Region string
+	Inner  *regionLeaf
+}
+
+func (r *regionReader) Init(ctx context.Context) error {
+	r.Region, _ = ctx.Value(regionKey{}).(string)
+	return nil
+}
+
+type regionLeaf struct {
+	Region string
+}
+
+func (l *regionLeaf) Init(ctx context.Context) error {
+	l.Region, _ = ctx.Value(regionKey{}).(string)
+	return nil
+}
+
+// regionParent gives its Replica child its own region
+type regionParent struct {
+	Primary   *regionReader
+	Replica   *regionReader
+	PostField string
+}
+
+func (p *regionParent) PreFieldInitContext(ctx context.Context, fieldName string, fieldValue interface{}) (context.Context, error) {
+	if fieldName == "Replica" {
+		return context.WithValue(ctx, regionKey{}, "eu-west"), nil
+	}
+	return ctx, nil
+}
+
+func (p *regionParent) PostFieldInit(ctx context.Context, fieldName string, fieldValue interface{}) error {
+	if fieldName == "Replica" {
+		p.PostField, _ = ctx.Value(regionKey{}).(string)
+	}
+	return nil
+}
+
+func TestContextMutatingPreFieldHook(t *testing.T) {
+	parent := &regionParent{
+		Primary: &regionReader{Inner: &regionLeaf{}},
+		Replica: &regionReader{Inner: &regionLeaf{}},
+	}
+	ctx := context.WithValue(context.Background(), regionKey{}, "us-east")
+	if err := AutoInit(ctx, parent); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parent.Primary.Region != "us-east" || parent.Primary.Inner.Region != "us-east" {
+		t.Errorf("Primary = %q/%q; want the run's region", parent.Primary.Region, parent.Primary.Inner.Region)
+	}
+	// The child context applies to the whole subtree and to PostFieldInit
+	if parent.Replica.Region != "eu-west" || parent.Replica.Inner.Region != "eu-west" {
+		t.Errorf("Replica = %q/%q; want the injected region", parent.Replica.Region, parent.Replica.Inner.Region)
+	}
+	if parent.PostField != "eu-west" {
+		t.Errorf("PostFieldInit saw %q; want eu-west", parent.PostField)
+	}
+}
+
+func TestDisableHooks(t *testing.T) {
+	logger := zerolog.Nop()
+	parent := &ParentWithFieldHooks{}
+	options := &Options{Logger: &logger, DisablePreInit: true, DisableFieldHooks: true}
+	if err := WithOptions(context.Background(), parent, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	child := parent.Child
+	if child.PreCalled || !child.InitCalled || !child.PostCalled {
+		t.Errorf("expected only Init and PostInit, got %+v", child)
+	}
+	if len(parent.PreFieldCalls) != 0 || len(parent.PostFieldCalls) != 0 {
+		t.Errorf("field hooks ran: pre=%v post=%v", parent.PreFieldCalls, parent.PostFieldCalls)
+	}
+
+	child = ChildWithHooks{}
+	if err := WithOptions(context.Background(), &child, &Options{Logger: &logger, DisablePostInit: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !child.PreCalled || !child.InitCalled || child.PostCalled {
+		t.Errorf("expected PreInit and Init only, got %+v", child)
+	}
+}
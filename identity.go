@@ -0,0 +1,41 @@
+package autoinit
+
+import "reflect"
+
+// Identifiable is implemented by components with a logical identity. The
+// finder and As treat two values with the same non-empty ComponentID as the
+// same component, both when excluding the searching component itself and when
+// FindAll removes duplicates. Use it when one logical component can be reached
+// as distinct values, e.g. wrapped or copied, and should still count as one.
+//
+// Values without an ID are compared by identity: pointers are the same
+// component only if they are equal.
+type Identifiable interface {
+	ComponentID() string
+}
+
+// sameComponent reports whether a and b are the same component, by
+// ComponentID if both have one and by identity otherwise. Unlike ==, it never
+// panics on values of uncomparable types.
+func sameComponent(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	if id, ok := componentID(a); ok {
+		if other, ok := componentID(b); ok {
+			return id == other
+		}
+	}
+	ta := reflect.TypeOf(a)
+	return ta == reflect.TypeOf(b) && ta.Comparable() && a == b
+}
+
+// componentID returns the non-empty ComponentID of v, if it has one
+func componentID(v interface{}) (string, bool) {
+	identifiable, ok := v.(Identifiable)
+	if !ok || isNilValue(reflect.ValueOf(v)) {
+		return "", false
+	}
+	id := identifiable.ComponentID()
+	return id, id != ""
+}
@@ -0,0 +1,72 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// identifiedService has a logical identity shared by its copies
+type identifiedService struct {
+	ID    string
+	Peer  *identifiedService
+	Peers []interface{}
+}
+
+func (s *identifiedService) ComponentID() string {
+	return s.ID
+}
+
+func (s *identifiedService) Init(ctx context.Context, parent interface{}) error {
+	As(ctx, s, parent, &s.Peer)
+	s.Peers = NewComponentFinder(ctx, s, parent).FindAll(&SearchOption{
+		ByType: reflect.TypeOf(&identifiedService{}),
+	})
+	return nil
+}
+
+func TestIdentifiableSelfExclusion(t *testing.T) {
+	type App struct {
+		Service *identifiedService
+		Copy    *identifiedService // the same logical component
+		Other   *identifiedService
+	}
+
+	service := &identifiedService{ID: "svc"}
+	copied := *service
+	other := &identifiedService{ID: "other"}
+	app := &App{Service: service, Copy: &copied, Other: other}
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Service does not discover its own copy
+	if service.Peer != other {
+		t.Errorf("Peer = %+v, want Other", service.Peer)
+	}
+	if len(service.Peers) != 1 || service.Peers[0] != other {
+		t.Errorf("Peers = %v, want only Other", service.Peers)
+	}
+
+	// Values sharing an ID are deduplicated
+	finder := NewComponentFinder(context.Background(), nil, app)
+	all := finder.FindAll(&SearchOption{ByType: reflect.TypeOf(&identifiedService{})})
+	if len(all) != 2 || all[0] != service || all[1] != other {
+		t.Errorf("FindAll = %v, want Service and Other", all)
+	}
+}
+
+func TestSameComponent(t *testing.T) {
+	a, b := &SimpleComponent{}, &SimpleComponent{}
+	if !sameComponent(a, a) || sameComponent(a, b) || sameComponent(a, nil) {
+		t.Error("pointers should be compared by identity")
+	}
+	// Uncomparable values never match and do not panic
+	if sameComponent([]int{1}, []int{1}) {
+		t.Error("uncomparable values should not match")
+	}
+	// An empty ID falls back to identity
+	if sameComponent(&identifiedService{}, &identifiedService{}) {
+		t.Error("values without an ID should be compared by identity")
+	}
+}
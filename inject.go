@@ -0,0 +1,128 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/rs/zerolog"
+)
+
+// isInjected reports whether a field's tag marks it for injection: `inject`,
+// or `inject=Name` to take the component held by the field named Name
+func isInjected(tag tagOptions) bool {
+	if tag.has("inject") {
+		return true
+	}
+	_, ok := tag.value("inject")
+	return ok
+}
+
+// injectFilters returns the filters an inject field is resolved with: the
+// field name of `inject=Name`, if given
+func injectFilters(tag tagOptions) []Filter {
+	if name, ok := tag.value("inject"); ok && name != "" {
+		return []Filter{WithFieldName(name)}
+	}
+	return nil
+}
+
+// injectTree assigns the nil fields tagged `autoinit:"inject"` in the tree
+// rooted at root, after every component completed its initializer and
+// PostInit and before Link. Each field is resolved with As from the position
+// of the component declaring it, so two components can refer to each other
+// although one of them always initializes first. With an error collector on
+// ctx failures are collected and nil is returned.
+func injectTree(ctx context.Context, root reflect.Value, state *runState, logger *zerolog.Logger, options *Options) error {
+	var nodes []componentNode
+	if err := walkTree(root, options, func(node componentNode) error {
+		nodes = append(nodes, node)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	byPath := indexByPath(nodes)
+	collector := getErrorCollector(ctx)
+	for i, node := range nodes {
+		if !selectsGroup(options, node.group) || failedWithin(nodes, i, byPath, state) {
+			continue
+		}
+		if err := injectFields(ctx, node, logger, options); err != nil {
+			if collector == nil {
+				return err
+			}
+			collector.add(err)
+		}
+	}
+	return nil
+}
+
+// injectFields resolves the nil inject fields of one component
+func injectFields(ctx context.Context, node componentNode, logger *zerolog.Logger, options *Options) error {
+	v := node.value
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	self := nodeInterface(node.value)
+	var parent interface{}
+	if node.parent.IsValid() {
+		parent = nodeInterface(node.parent)
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		tag := parseTag(fieldType)
+		field := v.Field(i)
+		if !isInjected(tag) || !field.CanSet() || !isNilValue(field) {
+			continue
+		}
+
+		fieldPath := appendPath(node.path, fieldType.Name)
+		result := asSearch(ctx, self, parent, field.Type(), injectFilters(tag)...)
+		var resultValue reflect.Value
+		cause := fmt.Errorf("%w of type %s", ErrUnresolvedInjection, field.Type())
+		if result != nil {
+			resultValue = reflect.ValueOf(result)
+			if !resultValue.Type().AssignableTo(field.Type()) {
+				// e.g. a struct held by value in a map whose methods have
+				// pointer receivers
+				cause = fmt.Errorf("%w of type %s: found %s, which is not assignable", ErrUnresolvedInjection, field.Type(), resultValue.Type())
+				resultValue = reflect.Value{}
+			}
+		}
+		if !resultValue.IsValid() {
+			if tag.has("optional") {
+				continue
+			}
+			logger.WithLevel(errorLogLevel(options)).
+				Str("path", pathToString(fieldPath)).
+				Str("type", field.Type().String()).
+				Err(cause).
+				Msg("Injection failed")
+			return &InitError{
+				Path:       fieldPath,
+				FieldIndex: i,
+				FieldType:  field.Type().String(),
+				Cause:      cause,
+			}
+		}
+
+		field.Set(resultValue)
+		via := "inject"
+		if name, ok := tag.value("inject"); ok {
+			via += "=" + name
+		}
+		recordDependency(ctx, self, resultValue, describeSearch(via, field.Type()))
+		logger.Trace().
+			Str("path", pathToString(fieldPath)).
+			Str("type", resultValue.Type().String()).
+			Msg("Injected dependency")
+	}
+	return nil
+}
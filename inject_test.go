@@ -0,0 +1,161 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// injectEarly and injectLate depend on each other; whichever initializes
+// first could not see the other initialized with As in its Init
+type injectEarly struct {
+	Initialized bool
+	Late        *injectLate `autoinit:"inject"`
+	linkedLate  bool
+}
+
+func (e *injectEarly) Init() error {
+	e.Initialized = true
+	return nil
+}
+
+func (e *injectEarly) Link(ctx context.Context, parent interface{}) error {
+	e.linkedLate = e.Late != nil && e.Late.Initialized
+	return nil
+}
+
+type injectLate struct {
+	Initialized bool
+	Early       *injectEarly `autoinit:"inject"`
+}
+
+func (l *injectLate) Init() error {
+	l.Initialized = true
+	return nil
+}
+
+func (e *injectEarly) Requires() []Requirement {
+	return []Requirement{Require[*injectLate]()}
+}
+
+func (l *injectLate) Requires() []Requirement {
+	return []Requirement{Require[*injectEarly]()}
+}
+
+type injectPair struct {
+	Early *injectEarly
+	Late  *injectLate
+}
+
+type injectStore struct {
+	Name string
+}
+
+type injectConsumer struct {
+	Store    *injectStore `autoinit:"inject=Replica"`
+	Fallback *injectStore `autoinit:"inject=Missing,optional"`
+}
+
+type injectStores struct {
+	Primary  *injectStore
+	Replica  *injectStore
+	Consumer *injectConsumer
+}
+
+type injectGetter interface {
+	Get() string
+}
+
+// Get has a pointer receiver, so a store held by value in a map, which As
+// can only return as a copy, does not implement injectGetter
+func (s *injectStore) Get() string { return s.Name }
+
+type injectGetterConsumer struct {
+	Getter injectGetter `autoinit:"inject"`
+}
+
+func TestInjectFields(t *testing.T) {
+	t.Run("mutual dependencies are wired after all Inits", func(t *testing.T) {
+		app := &injectPair{Early: &injectEarly{}, Late: &injectLate{}}
+		if err := AutoInit(context.Background(), app); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if app.Early.Late != app.Late || app.Late.Early != app.Early {
+			t.Errorf("expected both inject fields to be wired")
+		}
+		if !app.Early.linkedLate {
+			t.Errorf("expected Link to see the injected, initialized dependency")
+		}
+	})
+
+	t.Run("mutual requirements are reported", func(t *testing.T) {
+		report := &InitReport{}
+		app := &injectPair{Early: &injectEarly{}, Late: &injectLate{}}
+		if err := WithOptions(context.Background(), app, &Options{CheckRequirements: true, Report: report}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		warnings := report.WarningsWithCode(WarningMutualRequirement)
+		if len(warnings) != 1 || warnings[0].PathString() != "Early" {
+			t.Fatalf("expected one mutual requirement reported on Early, got %v", report.Warnings)
+		}
+		if !strings.Contains(warnings[0].Message, "Early and Late require each other") {
+			t.Errorf("unexpected message %q", warnings[0].Message)
+		}
+	})
+
+	t.Run("inject=Name selects the field", func(t *testing.T) {
+		app := &injectStores{
+			Primary:  &injectStore{Name: "primary"},
+			Replica:  &injectStore{Name: "replica"},
+			Consumer: &injectConsumer{},
+		}
+		if err := AutoInit(context.Background(), app); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if app.Consumer.Store != app.Replica {
+			t.Errorf("expected the Replica store, got %v", app.Consumer.Store)
+		}
+		if app.Consumer.Fallback != nil {
+			t.Errorf("expected the optional field to stay nil, got %v", app.Consumer.Fallback)
+		}
+	})
+
+	t.Run("assigned fields are kept", func(t *testing.T) {
+		own := &injectStore{Name: "own"}
+		app := &injectStores{Replica: &injectStore{Name: "replica"}, Consumer: &injectConsumer{Store: own}}
+		if err := AutoInit(context.Background(), app); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if app.Consumer.Store != own {
+			t.Errorf("expected the assigned store to be kept, got %v", app.Consumer.Store)
+		}
+	})
+
+	t.Run("unresolved injection fails", func(t *testing.T) {
+		app := &injectStores{Primary: &injectStore{Name: "primary"}, Consumer: &injectConsumer{}}
+		err := AutoInit(context.Background(), app)
+		if !errors.Is(err, ErrUnresolvedInjection) {
+			t.Fatalf("expected ErrUnresolvedInjection, got %v", err)
+		}
+		var initErr *InitError
+		if !errors.As(err, &initErr) || pathToString(initErr.GetPath()) != "Consumer.Store" {
+			t.Errorf("expected the error to name Consumer.Store, got %v", err)
+		}
+	})
+
+	t.Run("unassignable match fails", func(t *testing.T) {
+		type App struct {
+			Stores   map[string]injectStore
+			Consumer *injectGetterConsumer
+		}
+		app := &App{Stores: map[string]injectStore{"a": {Name: "a"}}, Consumer: &injectGetterConsumer{}}
+		err := AutoInit(context.Background(), app)
+		if !errors.Is(err, ErrUnresolvedInjection) || !strings.Contains(err.Error(), "not assignable") {
+			t.Fatalf("expected ErrUnresolvedInjection for the unassignable store, got %v", err)
+		}
+		if app.Consumer.Getter != nil {
+			t.Errorf("expected the field to stay nil, got %v", app.Consumer.Getter)
+		}
+	})
+}
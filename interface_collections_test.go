@@ -0,0 +1,99 @@
+package autoinit
+
+import (
+	"context"
+	"testing"
+)
+
+// Handler is an interface held in collections
+type Handler interface {
+	Handle() string
+}
+
+type ConcreteHandler struct {
+	Name        string
+	Dependency  *SimpleComponent
+	Initialized bool
+}
+
+func (h *ConcreteHandler) Init(ctx context.Context) error {
+	h.Initialized = true
+	return nil
+}
+
+func (h *ConcreteHandler) Handle() string {
+	return h.Name
+}
+
+func TestInterfaceSliceElementsInitialized(t *testing.T) {
+	type Router struct {
+		Handlers []Handler
+		ByName   map[string]Handler
+	}
+
+	router := &Router{
+		Handlers: []Handler{
+			&ConcreteHandler{Name: "users", Dependency: &SimpleComponent{}},
+			nil,
+			&ConcreteHandler{Name: "orders", Dependency: &SimpleComponent{}},
+		},
+		ByName: map[string]Handler{
+			"health": &ConcreteHandler{Name: "health", Dependency: &SimpleComponent{}},
+		},
+	}
+
+	if err := AutoInit(context.Background(), router); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, h := range router.Handlers {
+		if h == nil {
+			continue
+		}
+		concrete := h.(*ConcreteHandler)
+		if !concrete.Initialized {
+			t.Errorf("Handlers[%d] was not initialized", i)
+		}
+		if !concrete.Dependency.Initialized {
+			t.Errorf("Handlers[%d].Dependency was not descended into", i)
+		}
+	}
+
+	health := router.ByName["health"].(*ConcreteHandler)
+	if !health.Initialized || !health.Dependency.Initialized {
+		t.Error("interface map value was not initialized and descended into")
+	}
+}
+
+// otherComponent is a second, unrelated component type for heterogeneous collections
+type otherComponent struct {
+	Initialized bool
+}
+
+func (o *otherComponent) Init() error {
+	o.Initialized = true
+	return nil
+}
+
+func TestHeterogeneousInterfaceSlice(t *testing.T) {
+	type Registry struct {
+		Components []interface{}
+	}
+
+	handler := &ConcreteHandler{Name: "users"}
+	other := &otherComponent{}
+	registry := &Registry{
+		// Non-component values are ignored
+		Components: []interface{}{handler, "not a component", 42, nil, other},
+	}
+
+	if err := AutoInit(context.Background(), registry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !handler.Initialized {
+		t.Error("*ConcreteHandler element was not initialized")
+	}
+	if !other.Initialized {
+		t.Error("*otherComponent element was not initialized")
+	}
+}
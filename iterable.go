@@ -0,0 +1,65 @@
+package autoinit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Iterable is implemented by containers other than slices, arrays and maps that
+// hold components, such as sync.Map or a custom registry. When a field implements
+// it, AutoInit initializes every pointer-to-struct value the container yields,
+// in sorted key order, before the container itself. Struct values are skipped:
+// they are copies and changes made by Init could not be stored back.
+type Iterable interface {
+	Range(f func(key, value interface{}) bool)
+}
+
+// iterableEntry is one key/value pair yielded by an Iterable
+type iterableEntry struct {
+	key   interface{}
+	value reflect.Value
+}
+
+// iterableOf returns field as an Iterable, using its address if it is an
+// addressable struct so that pointer-receiver Range methods (sync.Map) are found
+func iterableOf(field reflect.Value) (Iterable, bool) {
+	if field.Kind() == reflect.Interface || field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, false
+		}
+	}
+	if field.Kind() == reflect.Struct && field.CanAddr() {
+		field = field.Addr()
+	}
+	if !field.CanInterface() {
+		return nil, false
+	}
+	iterable, ok := field.Interface().(Iterable)
+	return iterable, ok
+}
+
+// iterableEntries collects the entries of an Iterable in deterministic key order
+func iterableEntries(iterable Iterable) []iterableEntry {
+	var entries []iterableEntry
+	iterable.Range(func(key, value interface{}) bool {
+		entries = append(entries, iterableEntry{key: key, value: reflect.ValueOf(value)})
+		return true
+	})
+	sort.SliceStable(entries, func(i, j int) bool {
+		return lessAnyKey(entries[i].key, entries[j].key)
+	})
+	return entries
+}
+
+// lessAnyKey orders keys that may differ in type, by type name first
+func lessAnyKey(a, b interface{}) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return fmt.Sprint(ta) < fmt.Sprint(tb)
+	}
+	if ta == nil {
+		return false
+	}
+	return lessKey(reflect.ValueOf(a), reflect.ValueOf(b))
+}
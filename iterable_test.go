@@ -0,0 +1,81 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// pluginRegistry is a custom container exposing its plugins through Range
+type pluginRegistry struct {
+	plugins     map[string]*SimpleComponent
+	Initialized bool
+}
+
+func (r *pluginRegistry) Range(f func(key, value interface{}) bool) {
+	for name, plugin := range r.plugins {
+		if !f(name, plugin) {
+			return
+		}
+	}
+}
+
+func (r *pluginRegistry) Init() error {
+	// Contents are initialized before the container
+	for _, plugin := range r.plugins {
+		if !plugin.Initialized {
+			return nil
+		}
+	}
+	r.Initialized = true
+	return nil
+}
+
+func TestIterableContainers(t *testing.T) {
+	type App struct {
+		Registry *pluginRegistry
+		Services sync.Map
+	}
+
+	app := &App{Registry: &pluginRegistry{plugins: map[string]*SimpleComponent{
+		"b": {}, "a": {}, "c": {},
+	}}}
+	app.Services.Store("db", &Database{})
+	app.Services.Store(2, &Database{})
+	app.Services.Store("value", Database{}) // copies cannot be initialized
+
+	var paths []string
+	logger := zerolog.Nop()
+	err := WithOptions(context.Background(), app, &Options{
+		Logger: &logger,
+		OnEvent: func(e Event) {
+			if e.Phase == PhaseInit {
+				paths = append(paths, pathToString(e.Path))
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !app.Registry.Initialized {
+		t.Error("registry should be initialized after its plugins")
+	}
+	app.Services.Range(func(key, value interface{}) bool {
+		if db, ok := value.(*Database); ok && !db.Connected {
+			t.Errorf("service %v should be initialized", key)
+		}
+		return true
+	})
+
+	expected := []string{
+		"Registry.[a]", "Registry.[b]", "Registry.[c]", "Registry",
+		"Services.[2]", "Services.[db]",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("init order = %v; want %v", paths, expected)
+	}
+}
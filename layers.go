@@ -0,0 +1,458 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	layeredRunKey contextKey = "autoinit:layeredRun"
+	layerFieldKey contextKey = "autoinit:layerField"
+	serialKey     contextKey = "autoinit:serial"
+)
+
+// AutoInitLayers initializes target like WithOptions, but in dependency
+// layers rather than depth-first. A component depends on the components it
+// holds, which AutoInit initializes before it, and on those its declared
+// requirements (see DependencyDeclarer) resolve to. Layer 0 holds the
+// components that depend on nothing, and each further layer those whose
+// dependencies are all in earlier layers. A layer completes before the next
+// one starts, and with Options.MaxConcurrency > 1 the components of a layer
+// are initialized concurrently, so independent subtrees no longer wait for
+// each other.
+//
+// Each component runs its PreInit, Init and PostInit, with the type hooks
+// around them, together when its layer is initialized; its parent's
+// PostFieldInit for it runs once the whole layer completed. Components that
+// only exist once the run started, e.g. allocated nil pointers or factory
+// products, are not layered and are initialized during the pass of the
+// nearest component enclosing them, before it.
+//
+// Components that depend on each other, e.g. a component requiring one of its
+// ancestors, cannot be ordered: AutoInitLayers then returns a
+// *LayerCycleError listing them and initializes no component. It cannot be
+// combined with Options.Phases or Options.InitPlan, which order the tree
+// themselves.
+func AutoInitLayers(ctx context.Context, target interface{}, options *Options) error {
+	return WithOptions(context.WithValue(ctx, layeredRunKey, true), target, options)
+}
+
+// LayerCycleError is returned by AutoInitLayers when components depend on each
+// other, so that no layer can hold one of them before the others
+type LayerCycleError struct {
+	Cycles [][]string // Paths of the components depending on each other, one list per cycle
+}
+
+// Error lists the components of each cycle
+func (e *LayerCycleError) Error() string {
+	cycles := make([]string, len(e.Cycles))
+	for i, cycle := range e.Cycles {
+		cycles[i] = strings.Join(cycle, ", ")
+	}
+	return "components cannot be layered, they depend on each other: " + strings.Join(cycles, "; ")
+}
+
+// takeLayeredRun reports whether ctx starts a run of AutoInitLayers, and
+// returns ctx for the run, in which runs nested in it are not layered
+func takeLayeredRun(ctx context.Context) (context.Context, bool) {
+	layered, _ := ctx.Value(layeredRunKey).(bool)
+	if !layered {
+		return ctx, false
+	}
+	return context.WithValue(ctx, layeredRunKey, false), true
+}
+
+// initLayered initializes the tree rooted at v one layer at a time: each pass
+// traverses the tree, collecting the lifecycles of the components of its
+// layer, then runs them
+func initLayered(ctx context.Context, v reflect.Value, logger *zerolog.Logger, options *Options) error {
+	// A nested run starts outside the phases of the run that started it
+	ctx = context.WithValue(ctx, phaseKey, "")
+	ctx = context.WithValue(ctx, phasePassKey, (*phasePass)(nil))
+
+	if options != nil && (len(options.Phases) > 0 || options.InitPlan != nil) {
+		return fmt.Errorf("autoinit: AutoInitLayers cannot be combined with Options.Phases or Options.InitPlan")
+	}
+	layers, count, err := layerTree(ctx, v, options)
+	if err != nil {
+		return err
+	}
+
+	for layer := 0; layer < count; layer++ {
+		logger.WithLevel(stepLogLevel(options)).
+			Int("layer", layer).
+			Msg("Starting layer")
+		jobs := &layerJobs{}
+		passCtx := context.WithValue(ctx, phasePassKey, &phasePass{first: layer == 0, layers: layers, layer: layer, jobs: jobs})
+		if err := initStructWithVisited(passCtx, v, reflect.Value{}, []string{}, -1, logger, newVisited(options), options); err != nil {
+			return err
+		}
+		if err := jobs.run(options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// layerTree assigns every component of the tree rooted at v, by path, to the
+// layer after the last of its dependencies, and returns the number of layers
+func layerTree(ctx context.Context, v reflect.Value, options *Options) (map[string]int, int, error) {
+	var paths [][]string
+	index := make(map[string]int)
+	keys := make(map[componentKey]int)
+	if err := walkTree(v, options, func(node componentNode) error {
+		index[pathToString(node.path)] = len(paths)
+		if key, ok := keyOf(node.value); ok {
+			keys[key] = len(paths)
+		}
+		paths = append(paths, node.path)
+		return nil
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	// A component depends on the nearest components below it, and on those
+	// its requirements resolve to
+	dependencies := make([][]int, len(paths))
+	for i, path := range paths {
+		for n := len(path) - 1; n >= 0; n-- {
+			if parent, ok := index[pathToString(path[:n])]; ok {
+				dependencies[parent] = append(dependencies[parent], i)
+				break
+			}
+		}
+	}
+	edges, _, err := resolveRequirements(ctx, v, options)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, edge := range edges {
+		if dependency, ok := keys[edge.dependency]; ok {
+			component := index[pathToString(edge.path)]
+			dependencies[component] = append(dependencies[component], dependency)
+		}
+	}
+
+	if cycles := dependencyCycles(dependencies); len(cycles) > 0 {
+		cycleErr := &LayerCycleError{}
+		for _, cycle := range cycles {
+			names := make([]string, len(cycle))
+			for i, node := range cycle {
+				names[i] = pathToString(paths[node])
+			}
+			cycleErr.Cycles = append(cycleErr.Cycles, names)
+		}
+		return nil, 0, cycleErr
+	}
+
+	// Components are walked children-first, but requirements may point
+	// anywhere, so layers are computed depth-first over the dependencies
+	layerOf := make([]int, len(paths))
+	for i := range layerOf {
+		layerOf[i] = -1
+	}
+	var assign func(node int) int
+	assign = func(node int) int {
+		if layerOf[node] < 0 {
+			layer := 0
+			for _, dependency := range dependencies[node] {
+				if l := assign(dependency) + 1; l > layer {
+					layer = l
+				}
+			}
+			layerOf[node] = layer
+		}
+		return layerOf[node]
+	}
+	layers := make(map[string]int, len(paths))
+	count := 0
+	for i, path := range paths {
+		layers[pathToString(path)] = assign(i)
+		if layerOf[i]+1 > count {
+			count = layerOf[i] + 1
+		}
+	}
+	return layers, count, nil
+}
+
+// dependencyCycles returns the strongly connected components of the
+// dependency graph that contain a cycle, each sorted and in order of their
+// first node (Tarjan's algorithm)
+func dependencyCycles(dependencies [][]int) [][]int {
+	order := make([]int, len(dependencies))
+	low := make([]int, len(dependencies))
+	onStack := make([]bool, len(dependencies))
+	var stack []int
+	var cycles [][]int
+	next := 1
+
+	var connect func(node int)
+	connect = func(node int) {
+		order[node] = next
+		low[node] = next
+		next++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		selfLoop := false
+		for _, dependency := range dependencies[node] {
+			if dependency == node {
+				selfLoop = true
+			}
+			if order[dependency] == 0 {
+				connect(dependency)
+				if low[dependency] < low[node] {
+					low[node] = low[dependency]
+				}
+			} else if onStack[dependency] && order[dependency] < low[node] {
+				low[node] = order[dependency]
+			}
+		}
+		if low[node] != order[node] {
+			return
+		}
+
+		var component []int
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == node {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			sort.Ints(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for node := range dependencies {
+		if order[node] == 0 {
+			connect(node)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// layerJobs collects, during the pass of a layer, the lifecycles of the
+// layer's components and the steps that must follow them
+type layerJobs struct {
+	jobs  []layerJob
+	after []layerStep
+}
+
+// layerJob is the lifecycle of one component of a layer
+type layerJob struct {
+	ctx    context.Context
+	path   []string
+	serial bool // Whether the component must be initialized on the calling goroutine
+	run    func(ctx context.Context) error
+}
+
+// layerStep is a step of the traversal postponed until its layer completed,
+// such as a parent's PostFieldInit for a component of the layer
+type layerStep struct {
+	ctx  context.Context
+	step func() error
+}
+
+// defers reports whether the component at path, being traversed on ctx, has
+// its lifecycle run with the rest of the pass's layer: it must belong to the
+// layer itself rather than be initialized with the component enclosing it
+func (p *phasePass) defers(ctx context.Context, path string, options *Options) bool {
+	node, _ := ctx.Value(planNodeKey).(string)
+	return node == path && lifecycleActive(ctx, options)
+}
+
+// deferComponent initializes the fields of the component v now and collects
+// its lifecycle, which the layer runs once the pass completed
+func (j *layerJobs) deferComponent(ctx context.Context, node *nodeReportCursor, v, parent reflect.Value, path []string, index int, logger *zerolog.Logger, visited map[uintptr]bool, options *Options) error {
+	err := checkSingleton(ctx, v, path, index)
+	if err == nil {
+		err = initDescendants(ctx, v, path, logger, visited, options)
+	}
+	if err != nil {
+		return finishComponent(ctx, node, v, err, options)
+	}
+	markLayerField(ctx)
+
+	// The job runs after the traversal left v, so it gets its own parent
+	// chain, holding v and its ancestors
+	jobCtx := WithParentChain(ctx, getParentChain(ctx).ancestors()...)
+	j.jobs = append(j.jobs, layerJob{ctx: jobCtx, path: path, serial: isSerial(ctx, v), run: func(ctx context.Context) error {
+		err := interruption(ctx, path)
+		if err == nil {
+			var config *configSnapshot
+			config, err = beginLifecycle(ctx, v, path, index, logger, options)
+			if err == nil {
+				err = endLifecycle(ctx, v, parent, path, index, logger, options, config)
+			}
+		}
+		return finishComponent(ctx, node, v, err, options)
+	}})
+	return nil
+}
+
+// run runs the collected lifecycles, up to Options.MaxConcurrency at a time,
+// then the postponed steps in traversal order. Serial components run alone
+// on the calling goroutine once the others completed. It returns the error
+// of the first failed component; after a failure no further lifecycle
+// starts.
+func (j *layerJobs) run(options *Options) error {
+	limit := 1
+	if options != nil && options.MaxConcurrency > 1 {
+		limit = options.MaxConcurrency
+	}
+
+	var concurrent, serial []layerJob
+	for _, job := range j.jobs {
+		if job.serial || limit == 1 {
+			serial = append(serial, job)
+		} else {
+			concurrent = append(concurrent, job)
+		}
+	}
+	if err := runConcurrently(concurrent, limit); err != nil {
+		return err
+	}
+	for _, job := range serial {
+		if err := job.run(job.ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, after := range j.after {
+		if err := after.step(); err != nil {
+			// With ContinueOnError the failure is recorded like that of the
+			// parent the step belongs to
+			if collector := getErrorCollector(after.ctx); collector != nil && !isInterrupted(err) {
+				collector.add(err)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// runConcurrently runs jobs on their own goroutines, limit at a time, and
+// returns the error of the first failed one in traversal order
+func runConcurrently(jobs []layerJob, limit int) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failed   bool
+		panicked interface{}
+	)
+	errs := make([]error, len(jobs))
+	slots := make(chan struct{}, limit)
+	for i, job := range jobs {
+		slots <- struct{}{}
+		mu.Lock()
+		stop := failed || panicked != nil
+		mu.Unlock()
+		if stop {
+			<-slots
+			break
+		}
+		wg.Add(1)
+		go func(i int, job layerJob) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			// A panicking component panics on the goroutine that called
+			// AutoInitLayers, as it would in a sequential run
+			defer func() {
+				if r := recover(); r != nil {
+					mu.Lock()
+					panicked = r
+					mu.Unlock()
+				}
+			}()
+			err := job.run(job.ctx)
+			mu.Lock()
+			errs[i] = err
+			failed = failed || err != nil
+			mu.Unlock()
+		}(i, job)
+	}
+	wg.Wait()
+	if panicked != nil {
+		panic(panicked)
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// layerField notes whether a component below a field, or a collection
+// entry, traversed in the pass of a layer had its lifecycle deferred
+type layerField struct {
+	deferred bool
+	parent   *layerField // The field enclosing this one
+}
+
+// withLayerField returns ctx for the traversal below a field or collection
+// entry, in a pass of a layered run
+func withLayerField(ctx context.Context) context.Context {
+	if pass := currentPhasePass(ctx); pass == nil || pass.jobs == nil {
+		return ctx
+	}
+	parent, _ := ctx.Value(layerFieldKey).(*layerField)
+	return context.WithValue(ctx, layerFieldKey, &layerField{parent: parent})
+}
+
+// markLayerField notes that the lifecycle of a component below the fields of
+// ctx was deferred
+func markLayerField(ctx context.Context) {
+	field, _ := ctx.Value(layerFieldKey).(*layerField)
+	for ; field != nil; field = field.parent {
+		field.deferred = true
+	}
+}
+
+// afterLayer runs step, which follows the initialization of the field or
+// entry of ctx, now or, if a component below it had its lifecycle deferred,
+// once the layer completed
+func afterLayer(ctx context.Context, step func() error) error {
+	pass := currentPhasePass(ctx)
+	field, _ := ctx.Value(layerFieldKey).(*layerField)
+	if pass == nil || pass.jobs == nil || field == nil || !field.deferred {
+		return step()
+	}
+	pass.jobs.after = append(pass.jobs.after, layerStep{ctx: ctx, step: step})
+	return nil
+}
+
+// withFieldSerial returns ctx for the component held by a field, which must
+// be initialized on the calling goroutine if the field is tagged serial. Like
+// a resource class, the constraint is not inherited by the components below
+// it.
+func withFieldSerial(ctx context.Context, tag tagOptions) context.Context {
+	serial := tag.has("serial")
+	if current, _ := ctx.Value(serialKey).(bool); current == serial {
+		return ctx
+	}
+	return context.WithValue(ctx, serialKey, serial)
+}
+
+// isSerial reports whether the component v, traversed on ctx, must be
+// initialized on the calling goroutine: it is held by a field tagged serial
+// or implements SerialInitializer
+func isSerial(ctx context.Context, v reflect.Value) bool {
+	if serial, _ := ctx.Value(serialKey).(bool); serial {
+		return true
+	}
+	_, ok := nodeInterface(v).(SerialInitializer)
+	return ok
+}
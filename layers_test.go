@@ -0,0 +1,382 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// layerLog records the order components are initialized in
+type layerLog struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (l *layerLog) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.names = append(l.names, name)
+}
+
+func (l *layerLog) index(name string) int {
+	for i, n := range l.names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+type layerLogKeyType struct{}
+
+var layerLogKey = layerLogKeyType{}
+
+func layerLogOf(ctx context.Context) *layerLog {
+	log, _ := ctx.Value(layerLogKey).(*layerLog)
+	return log
+}
+
+type layerStore struct {
+	Name  string
+	Ready bool
+}
+
+func (s *layerStore) Init(ctx context.Context) error {
+	s.Ready = true
+	layerLogOf(ctx).add(s.Name)
+	return nil
+}
+
+// layerConsumer requires the store named Store, declared after it
+type layerConsumer struct {
+	store *layerStore
+}
+
+func (c *layerConsumer) Requires() []Requirement {
+	return []Requirement{Require[*layerStore](WithFieldName("Store"))}
+}
+
+func (c *layerConsumer) Init(ctx context.Context, parent interface{}) error {
+	MustAs(ctx, c, parent, &c.store, WithFieldName("Store"))
+	if !c.store.Ready {
+		return errors.New("store not ready")
+	}
+	layerLogOf(ctx).add("Consumer")
+	return nil
+}
+
+type layerGroup struct {
+	Inner *layerStore
+}
+
+func (g *layerGroup) Init(ctx context.Context) error {
+	layerLogOf(ctx).add("Group")
+	return nil
+}
+
+// layerPostHooks records the PostFieldInit calls of its fields
+type layerPostHooks struct {
+	Store  *layerStore
+	Stores map[string]layerStore
+	ready  []bool
+}
+
+func (h *layerPostHooks) PostFieldInit(ctx context.Context, fieldName string, fieldValue interface{}) error {
+	if store, ok := fieldValue.(*layerStore); ok {
+		h.ready = append(h.ready, store.Ready)
+	}
+	return nil
+}
+
+func TestAutoInitLayers(t *testing.T) {
+	t.Run("dependencies initialize first", func(t *testing.T) {
+		type App struct {
+			Consumer *layerConsumer
+			Group    *layerGroup
+			Store    *layerStore
+		}
+		app := &App{Consumer: &layerConsumer{}, Group: &layerGroup{Inner: &layerStore{Name: "Inner"}}, Store: &layerStore{Name: "Store"}}
+
+		// Depth-first, the consumer initializes before the store it requires
+		ctx := context.WithValue(context.Background(), layerLogKey, &layerLog{})
+		if err := AutoInit(ctx, &App{Consumer: &layerConsumer{}, Store: &layerStore{Name: "Store"}}); err == nil {
+			t.Fatal("expected the consumer to fail depth-first")
+		}
+
+		log := &layerLog{}
+		ctx = context.WithValue(context.Background(), layerLogKey, log)
+		if err := AutoInitLayers(ctx, app, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"Inner", "Store", "Consumer", "Group"}
+		if !reflect.DeepEqual(log.names, want) {
+			t.Errorf("initialization order = %v; want %v", log.names, want)
+		}
+	})
+
+	t.Run("layer initializes concurrently", func(t *testing.T) {
+		const width = 3
+		type App struct {
+			Stores []*layerBarrier
+		}
+		barrier := &sync.WaitGroup{}
+		barrier.Add(width)
+		app := &App{}
+		for i := 0; i < width; i++ {
+			app.Stores = append(app.Stores, &layerBarrier{wg: barrier})
+		}
+		options := &Options{MaxConcurrency: width, ContinueOnError: true, Report: &InitReport{}}
+		if err := AutoInitLayers(context.Background(), app, options); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, store := range app.Stores {
+			if !store.met {
+				t.Errorf("Stores[%d] did not run alongside the rest of its layer", i)
+			}
+		}
+	})
+
+	t.Run("serial components run alone", func(t *testing.T) {
+		type App struct {
+			First  *layerBarrier
+			Tagged *layerSerialProbe `autoinit:"serial"`
+			Marked *layerSerialMarked
+			Second *layerBarrier
+		}
+		barrier := &sync.WaitGroup{}
+		barrier.Add(2)
+		running := &layerRunning{}
+		app := &App{
+			First:  &layerBarrier{wg: barrier, running: running},
+			Tagged: &layerSerialProbe{running: running},
+			Marked: &layerSerialMarked{layerSerialProbe{running: running}},
+			Second: &layerBarrier{wg: barrier, running: running},
+		}
+		if err := AutoInitLayers(context.Background(), app, &Options{MaxConcurrency: 4}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !app.First.met || !app.Second.met {
+			t.Error("expected the other components of the layer to run concurrently")
+		}
+		if !app.Tagged.alone || !app.Marked.alone {
+			t.Error("expected the serial components to run alone")
+		}
+	})
+
+	t.Run("callbacks do not overlap", func(t *testing.T) {
+		const width = 4
+		type App struct {
+			Stores []*layerBarrier
+		}
+		barrier := &sync.WaitGroup{}
+		barrier.Add(width)
+		app := &App{}
+		for i := 0; i < width; i++ {
+			app.Stores = append(app.Stores, &layerBarrier{wg: barrier})
+		}
+
+		// The callbacks record without locking; -race flags overlapping calls
+		var events []EventPhase
+		var progress []int
+		var hooked, timed int
+		hook := TypeHook{
+			Before: func(ctx context.Context, component interface{}) error {
+				hooked++
+				return nil
+			},
+			After: func(ctx context.Context, component interface{}) error {
+				hooked++
+				return nil
+			},
+		}
+		options := &Options{
+			MaxConcurrency: width,
+			OnEvent:        func(e Event) { events = append(events, e.Phase) },
+			OnProgress:     func(done, total int) { progress = append(progress, done) },
+			TypeHooks:      map[reflect.Type]TypeHook{reflect.TypeOf(&layerBarrier{}): hook},
+			Middleware: []Middleware{TimingMiddleware(func(path []string, component interface{}, elapsed time.Duration) {
+				timed++
+			})},
+		}
+		if err := AutoInitLayers(context.Background(), app, options); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, store := range app.Stores {
+			if !store.met {
+				t.Errorf("Stores[%d] did not run alongside the rest of its layer", i)
+			}
+		}
+		if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(progress, want) {
+			t.Errorf("progress = %v; want %v", progress, want)
+		}
+		if len(events) != 3*width || hooked != 2*width || timed != width {
+			t.Errorf("got %d events, %d hook calls and %d timings", len(events), hooked, timed)
+		}
+	})
+
+	t.Run("post field hooks and map values follow the layer", func(t *testing.T) {
+		app := &layerPostHooks{
+			Store:  &layerStore{Name: "Store"},
+			Stores: map[string]layerStore{"a": {Name: "a"}, "b": {Name: "b"}},
+		}
+		ctx := context.WithValue(context.Background(), layerLogKey, &layerLog{})
+		if err := AutoInitLayers(ctx, app, &Options{MaxConcurrency: 2}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(app.ready, []bool{true}) {
+			t.Errorf("PostFieldInit saw Ready = %v; want [true]", app.ready)
+		}
+		for key, store := range app.Stores {
+			if !store.Ready {
+				t.Errorf("Stores[%s] was not written back initialized", key)
+			}
+		}
+	})
+
+	t.Run("failure stops later layers", func(t *testing.T) {
+		type App struct {
+			Group *layerGroup
+			Bad   *layerFailing
+		}
+		log := &layerLog{}
+		ctx := context.WithValue(context.Background(), layerLogKey, log)
+		app := &App{Group: &layerGroup{Inner: &layerStore{Name: "Inner"}}, Bad: &layerFailing{}}
+		err := AutoInitLayers(ctx, app, &Options{MaxConcurrency: 2})
+		var initErr *InitError
+		if !errors.As(err, &initErr) || pathToString(initErr.Path) != "Bad" {
+			t.Fatalf("expected an InitError for Bad, got %v", err)
+		}
+		if log.index("Group") >= 0 {
+			t.Error("expected the layer after the failure not to run")
+		}
+	})
+
+	t.Run("mutual requirements are a cycle", func(t *testing.T) {
+		type App struct {
+			Ping *layerPing
+			Pong *layerPong
+		}
+		app := &App{Ping: &layerPing{}, Pong: &layerPong{}}
+		err := AutoInitLayers(context.Background(), app, nil)
+		var cycleErr *LayerCycleError
+		if !errors.As(err, &cycleErr) {
+			t.Fatalf("expected a LayerCycleError, got %v", err)
+		}
+		if want := [][]string{{"Ping", "Pong"}}; !reflect.DeepEqual(cycleErr.Cycles, want) {
+			t.Errorf("Cycles = %v; want %v", cycleErr.Cycles, want)
+		}
+		if app.Ping.initialized || app.Pong.initialized {
+			t.Error("expected no component to be initialized")
+		}
+	})
+
+	t.Run("cannot be combined with phases", func(t *testing.T) {
+		type App struct {
+			Store *layerStore
+		}
+		err := AutoInitLayers(context.Background(), &App{Store: &layerStore{}}, &Options{Phases: []string{"config"}})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+// layerRunning counts the Init calls in progress
+type layerRunning struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (r *layerRunning) enter() int {
+	if r == nil {
+		return 1
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.n++
+	return r.n
+}
+
+func (r *layerRunning) leave() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.n--
+}
+
+// layerBarrier only completes its Init once every other barrier of its
+// layer started theirs
+type layerBarrier struct {
+	wg      *sync.WaitGroup
+	running *layerRunning
+	met     bool
+}
+
+func (b *layerBarrier) Init() error {
+	b.running.enter()
+	defer b.running.leave()
+	b.wg.Done()
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		b.met = true
+	case <-time.After(time.Second):
+	}
+	return nil
+}
+
+type layerFailing struct{}
+
+func (f *layerFailing) Init() error {
+	return errors.New("boom")
+}
+
+type layerPing struct {
+	initialized bool
+}
+
+func (p *layerPing) Requires() []Requirement { return []Requirement{Require[*layerPong]()} }
+
+func (p *layerPing) Init() error {
+	p.initialized = true
+	return nil
+}
+
+type layerPong struct {
+	initialized bool
+}
+
+func (p *layerPong) Requires() []Requirement { return []Requirement{Require[*layerPing]()} }
+
+func (p *layerPong) Init() error {
+	p.initialized = true
+	return nil
+}
+
+// layerSerialProbe notes whether its Init ran while no other Init did
+type layerSerialProbe struct {
+	running *layerRunning
+	alone   bool
+}
+
+func (p *layerSerialProbe) Init() error {
+	p.alone = p.running.enter() == 1
+	p.running.leave()
+	return nil
+}
+
+// layerSerialMarked declares itself serial rather than through a tag
+type layerSerialMarked struct {
+	layerSerialProbe
+}
+
+func (m *layerSerialMarked) SerialInit() {}
@@ -0,0 +1,118 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Linker is implemented by components that wire themselves to other components
+// using state that only exists once those are fully initialized, e.g. the
+// address of a server that binds an ephemeral port in PostInit.
+//
+// Link is the last lifecycle phase of a run. It runs after the whole tree has
+// completed PreInit, Init and PostInit, so every component - not just the ones
+// initialized earlier - can be looked up in its final state with As or a
+// ComponentFinder. Components are linked in initialization order, with the
+// parent chain set to their real ancestors. There is no separate validation
+// phase: checks that depend on linked state belong in Link itself.
+//
+// If initialization fails, Link is not called. With ContinueOnError, Link is
+// skipped for components whose lifecycle failed and everything below them.
+// Each component is linked once per run, even if nested AutoInit calls reach it
+// again. Struct values held in maps are linked on a copy, so changes made by
+// Link are not stored back.
+type Linker interface {
+	Link(ctx context.Context, parent interface{}) error
+}
+
+// linkTree runs the Link phase over the tree rooted at root. With an error
+// collector on ctx failures are collected and nil is returned.
+func linkTree(ctx context.Context, root reflect.Value, state *runState, logger *zerolog.Logger, options *Options) error {
+	var nodes []componentNode
+	if err := walkTree(root, options, func(node componentNode) error {
+		nodes = append(nodes, node)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	byPath := indexByPath(nodes)
+	collector := getErrorCollector(ctx)
+	for i, node := range nodes {
+		if !selectsGroup(options, node.group) || failedWithin(nodes, i, byPath, state) {
+			continue
+		}
+		linker, ok := nodeInterface(node.value).(Linker)
+		if !ok || !state.markLinked(node.value) {
+			continue
+		}
+
+		nodeCtx := WithParentChain(ctx, ancestorChain(nodes, i, byPath)...)
+		if err := callLink(nodeCtx, node, linker, logger, options); err != nil {
+			if collector == nil {
+				return err
+			}
+			collector.add(err)
+		}
+	}
+	return nil
+}
+
+// failedWithin reports whether node i or one of its ancestors failed
+func failedWithin(nodes []componentNode, i int, byPath map[string]int, state *runState) bool {
+	for {
+		if state.isFailed(nodes[i].value) {
+			return true
+		}
+		parent, ok := parentNode(nodes[i].path, byPath)
+		if !ok {
+			return false
+		}
+		i = parent
+	}
+}
+
+// callLink calls a component's Link method
+func callLink(ctx context.Context, node componentNode, linker Linker, logger *zerolog.Logger, options *Options) error {
+	pathStr := pathToString(node.path)
+	typeName := reflect.TypeOf(linker).String()
+
+	logger.WithLevel(stepLogLevel(options)).
+		Str("path", pathStr).
+		Str("type", typeName).
+		Msg("Calling Link")
+
+	start := time.Now()
+	err := protect(options, func() error {
+		return linker.Link(ctx, parentInterfaceOf(node.parent))
+	})
+	emitEvent(ctx, options, Event{
+		Phase:      PhaseLink,
+		Path:       node.path,
+		FieldIndex: node.index,
+		Type:       typeName,
+		Err:        err,
+		Duration:   time.Since(start),
+	})
+
+	if err != nil {
+		logger.WithLevel(errorLogLevel(options)).
+			Str("path", pathStr).
+			Err(err).
+			Msg("Link failed")
+		return &InitError{
+			Path:       node.path,
+			FieldIndex: node.index,
+			FieldType:  typeName,
+			Cause:      err,
+		}
+	}
+
+	logger.WithLevel(stepLogLevel(options)).
+		Str("path", pathStr).
+		Msg("Link completed successfully")
+	return nil
+}
@@ -0,0 +1,95 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// linkServer only knows its address after PostInit
+type linkServer struct {
+	Addr     string
+	FailInit bool
+}
+
+func (s *linkServer) Init() error {
+	if s.FailInit {
+		return errors.New("bind failed")
+	}
+	return nil
+}
+
+func (s *linkServer) PostInit(ctx context.Context) error {
+	s.Addr = "127.0.0.1:49152"
+	return nil
+}
+
+// linkClient is declared before the server but needs its final address
+type linkClient struct {
+	ServerAddr string
+	Linked     int
+}
+
+func (c *linkClient) Link(ctx context.Context, parent interface{}) error {
+	c.Linked++
+	var server *linkServer
+	if !As(ctx, c, parent, &server) {
+		return errors.New("no server")
+	}
+	if server.Addr == "" {
+		return errors.New("server has no address yet")
+	}
+	c.ServerAddr = server.Addr
+	return nil
+}
+
+func TestLinkPhase(t *testing.T) {
+	type App struct {
+		Client *linkClient
+		Server *linkServer
+	}
+
+	var phases []string
+	logger := zerolog.Nop()
+	options := &Options{
+		Logger: &logger,
+		OnEvent: func(e Event) {
+			phases = append(phases, string(e.Phase)+" "+e.PathString())
+		},
+	}
+
+	app := &App{Client: &linkClient{}, Server: &linkServer{}}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Client.ServerAddr != "127.0.0.1:49152" || app.Client.Linked != 1 {
+		t.Errorf("client = %+v; want it linked once to the bound server", app.Client)
+	}
+	expected := []string{"Init Server", "PostInit Server", "Link Client"}
+	if !reflect.DeepEqual(phases, expected) {
+		t.Errorf("phases = %v; want %v", phases, expected)
+	}
+
+	// Link does not run after a failure
+	failing := &App{Client: &linkClient{}, Server: &linkServer{FailInit: true}}
+	if err := WithOptions(context.Background(), failing, &Options{Logger: &logger}); err == nil {
+		t.Fatal("expected the server to fail")
+	}
+	if failing.Client.Linked != 0 {
+		t.Error("Link should not run when initialization fails")
+	}
+
+	// With ContinueOnError healthy components are linked; their Link errors are collected
+	err := WithOptions(context.Background(), failing, &Options{Logger: &logger, ContinueOnError: true})
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != 2 || failing.Client.Linked != 1 {
+		t.Fatalf("expected init and link failures, got %v (linked %d)", err, failing.Client.Linked)
+	}
+	var initErr *InitError
+	if !errors.As(errs[1], &initErr) || pathToString(initErr.GetPath()) != "Client" {
+		t.Errorf("expected a Link error for Client, got %v", errs[1])
+	}
+}
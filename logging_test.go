@@ -3,6 +3,7 @@ package autoinit
 import (
 	"bytes"
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
@@ -235,3 +236,252 @@ func TestPathFormatting(t *testing.T) {
 		}
 	}
 }
+
+// valueReceiverInit has a value-receiver Init whose changes only persist when addressable
+type valueReceiverInit struct {
+	Count *int
+}
+
+func (v valueReceiverInit) Init() error {
+	*v.Count++
+	return nil
+}
+
+type valueReceiverRoot struct {
+	Component valueReceiverInit
+}
+
+// Test that calling Init on a non-addressable value logs a warning
+func TestNonAddressableInitWarning(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.WarnLevel)
+	count := 0
+
+	// Passing the root by value makes every field non-addressable
+	root := valueReceiverRoot{Component: valueReceiverInit{Count: &count}}
+	if err := WithOptions(context.Background(), root, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("Init should still be called once, got %d", count)
+	}
+
+	logOutput := buf.String()
+	if !strings.Contains(logOutput, `"level":"warn"`) {
+		t.Errorf("expected a warning, got: %s", logOutput)
+	}
+	if !strings.Contains(logOutput, "non-addressable") || !strings.Contains(logOutput, `"path":"Component"`) {
+		t.Errorf("warning should name the path and problem, got: %s", logOutput)
+	}
+
+	// Passing a pointer keeps everything addressable: no warning
+	buf.Reset()
+	if err := WithOptions(context.Background(), &root, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no warnings for an addressable tree, got: %s", buf.String())
+	}
+}
+
+// Test that a pointer-receiver Init on a non-addressable value fails instead of being skipped
+func TestNonAddressablePointerReceiver(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.WarnLevel)
+
+	type ValueRoot struct {
+		Component SimpleComponent
+	}
+	err := WithOptions(context.Background(), ValueRoot{}, &Options{Logger: &logger})
+	var initErr *InitError
+	if !errors.As(err, &initErr) || !errors.Is(err, ErrNotAddressable) || pathToString(initErr.Path) != "Component" {
+		t.Errorf("expected ErrNotAddressable for Component, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "Target passed by value") {
+		t.Errorf("expected a warning about the by-value target, got: %s", buf.String())
+	}
+
+	// Struct values held in interfaces are not addressable either
+	type InterfaceRoot struct {
+		Items []interface{}
+	}
+	err = WithOptions(context.Background(), &InterfaceRoot{Items: []interface{}{SimpleComponent{}}}, &Options{Logger: &logger})
+	if !errors.As(err, &initErr) || !errors.Is(err, ErrNotAddressable) || pathToString(initErr.Path) != "Items.[0]" {
+		t.Errorf("expected ErrNotAddressable for Items.[0], got %v", err)
+	}
+
+	// Pointers are fine
+	root := &ValueRoot{}
+	if err := WithOptions(context.Background(), root, &Options{Logger: &logger}); err != nil || !root.Component.Initialized {
+		t.Errorf("expected the component to be initialized, got %v", err)
+	}
+}
+
+// Test that a trace ID is attached to every log line and event
+func TestTraceIDInLogsAndEvents(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.TraceLevel)
+
+	var events []Event
+	options := &Options{
+		Logger:  &logger,
+		TraceID: "boot-42",
+		OnEvent: func(e Event) {
+			events = append(events, e)
+		},
+	}
+
+	component := &LoggingComponent{Name: "test"}
+	if err := WithOptions(context.Background(), component, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for _, line := range lines {
+		if !strings.Contains(line, `"trace_id":"boot-42"`) {
+			t.Errorf("log line missing trace ID: %s", line)
+		}
+	}
+	if len(events) == 0 {
+		t.Fatal("expected events")
+	}
+	for _, e := range events {
+		if e.TraceID != "boot-42" {
+			t.Errorf("event %s %s has trace ID %q", e.Phase, e.PathString(), e.TraceID)
+		}
+	}
+
+	// The trace ID can also come from the context
+	buf.Reset()
+	ctx := WithTraceID(context.Background(), "from-ctx")
+	if got := TraceIDFromContext(ctx); got != "from-ctx" {
+		t.Errorf("TraceIDFromContext = %q", got)
+	}
+	if err := WithOptions(ctx, &LoggingComponent{}, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"trace_id":"from-ctx"`) {
+		t.Error("expected trace ID from context in logs")
+	}
+}
+
+// resolvingComponent looks up a dependency that does not exist
+type resolvingComponent struct {
+	Found bool
+}
+
+func (r *resolvingComponent) Init(ctx context.Context, parent interface{}) error {
+	var db *Database
+	r.Found = As(ctx, r, parent, &db)
+	return nil
+}
+
+func TestAsResolutionLogging(t *testing.T) {
+	type App struct {
+		Resolver *resolvingComponent
+		Cache    *SimpleComponent
+		Missing  *Database
+	}
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.DebugLevel)
+	app := &App{Resolver: &resolvingComponent{}, Cache: &SimpleComponent{}}
+	if err := WithOptions(context.Background(), app, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Resolver.Found {
+		t.Fatal("no database should be found")
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		`"field":"Resolver","target":"*autoinit.Database","reason":"self"`,
+		`"field":"Cache","target":"*autoinit.Database","reason":"type mismatch"`,
+		`"field":"Missing","target":"*autoinit.Database","reason":"nil"`,
+		`"message":"As: no matching dependency found"`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected log to contain %s\n%s", want, output)
+		}
+	}
+
+	// Nothing is logged above debug level
+	buf.Reset()
+	logger = zerolog.New(&buf).Level(zerolog.InfoLevel)
+	if err := WithOptions(context.Background(), &App{Resolver: &resolvingComponent{}}, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output at info level, got %s", buf.String())
+	}
+}
+
+type failingLoggingComponent struct {
+	Name string
+}
+
+func (f *failingLoggingComponent) Init() error {
+	return errors.New("init failed")
+}
+
+func TestConfigurableLogLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Level(zerolog.InfoLevel)
+	info, warn, debug := zerolog.InfoLevel, zerolog.WarnLevel, zerolog.DebugLevel
+	options := &Options{Logger: &logger, LogLevel: &info}
+	if err := WithOptions(context.Background(), &LoggingComponent{Name: "test"}, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		`"level":"info","target_type":"*autoinit.LoggingComponent","message":"Starting AutoInit"`,
+		`"level":"info","path":"Nested","message":"Init() completed successfully"`,
+		`"level":"info","message":"AutoInit completed successfully"`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected log to contain %s\n%s", want, output)
+		}
+	}
+	// Traversal details and hooks that are not implemented stay at trace level
+	for _, unwanted := range []string{"Traversing field", "PreInit completed"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("unexpected %q at info level\n%s", unwanted, output)
+		}
+	}
+
+	// Failures are logged at ErrorLogLevel
+	buf.Reset()
+	options = &Options{Logger: &logger, LogLevel: &info, ErrorLogLevel: &warn}
+	type App struct {
+		Broken *failingLoggingComponent
+	}
+	if err := WithOptions(context.Background(), &App{Broken: &failingLoggingComponent{}}, options); err == nil {
+		t.Fatal("expected an error")
+	}
+	output = buf.String()
+	if !strings.Contains(output, `"level":"warn","path":"Broken","error":"init failed","message":"Init() failed"`) {
+		t.Errorf("expected failure at warn level\n%s", output)
+	}
+	if strings.Contains(output, `"level":"error"`) {
+		t.Errorf("unexpected error-level log\n%s", output)
+	}
+
+	// Debug is a level like any other, not the unset default
+	buf.Reset()
+	debugLogger := zerolog.New(&buf).Level(zerolog.DebugLevel)
+	options = &Options{Logger: &debugLogger, ErrorLogLevel: &debug}
+	if err := WithOptions(context.Background(), &App{Broken: &failingLoggingComponent{}}, options); err == nil {
+		t.Fatal("expected an error")
+	}
+	output = buf.String()
+	if !strings.Contains(output, `"level":"debug","path":"Broken","error":"init failed","message":"Init() failed"`) {
+		t.Errorf("expected failure at debug level\n%s", output)
+	}
+
+	// Without LogLevel, lifecycle steps stay at trace level
+	if strings.Contains(output, "Starting AutoInit") {
+		t.Errorf("unexpected lifecycle step above trace level by default\n%s", output)
+	}
+}
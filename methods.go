@@ -0,0 +1,113 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// componentMethodRegistry holds the methods registered with
+// RegisterComponentMethod, keyed by owning struct type
+var componentMethodRegistry = struct {
+	sync.RWMutex
+	methods map[reflect.Type][]string
+}{methods: make(map[reflect.Type][]string)}
+
+// RegisterComponentMethod registers a zero-argument method of struct type T
+// that returns a subcomponent, for designs that construct subcomponents lazily
+// in factory methods instead of storing them in fields:
+//
+//	func (a *App) Cache() *Cache { ... }
+//
+//	autoinit.RegisterComponentMethod[App]("Cache")
+//
+// With Options.CallComponentMethods set, AutoInit calls the registered methods
+// of every T it initializes after T's fields, in registration order, and
+// initializes the returned components as children of T, under paths such as
+// "App.Cache()". Nil results are skipped, and a method returning a component
+// already initialized in the run, e.g. one cached in a field, does not
+// initialize it again. PreFieldInit and PostFieldInit are not called for them.
+//
+// Without the option no method is ever called, so registering a method cannot
+// run arbitrary code by accident. Tree walks such as AutoShutdown never call
+// these methods; shut such components down from their owner if needed.
+//
+// The registry is process-wide; register methods during program setup.
+// Registering the same method twice has no effect. RegisterComponentMethod
+// panics if T is not a struct type or if *T has no method named method that
+// takes no arguments and returns a single pointer to a struct.
+func RegisterComponentMethod[T any](method string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("autoinit: RegisterComponentMethod requires a struct type, got %s", t))
+	}
+	m, ok := reflect.PointerTo(t).MethodByName(method)
+	if !ok {
+		panic(fmt.Sprintf("autoinit: %s has no method %s", t, method))
+	}
+	// m.Type includes the receiver
+	if m.Type.NumIn() != 1 || m.Type.NumOut() != 1 ||
+		m.Type.Out(0).Kind() != reflect.Ptr || m.Type.Out(0).Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("autoinit: %s.%s must take no arguments and return a pointer to a struct", t, method))
+	}
+
+	componentMethodRegistry.Lock()
+	defer componentMethodRegistry.Unlock()
+	for _, registered := range componentMethodRegistry.methods[t] {
+		if registered == method {
+			return
+		}
+	}
+	componentMethodRegistry.methods[t] = append(componentMethodRegistry.methods[t], method)
+}
+
+// componentMethodsOf returns the methods registered for struct type t
+func componentMethodsOf(t reflect.Type) []string {
+	componentMethodRegistry.RLock()
+	defer componentMethodRegistry.RUnlock()
+	return componentMethodRegistry.methods[t]
+}
+
+// initMethodComponents initializes the components returned by the registered
+// methods of struct v under Options.CallComponentMethods
+func initMethodComponents(ctx context.Context, v reflect.Value, path []string, logger *zerolog.Logger, visited map[uintptr]bool, options *Options) error {
+	if options == nil || !options.CallComponentMethods || !v.CanAddr() {
+		return nil
+	}
+	methods := componentMethodsOf(v.Type())
+	if len(methods) == 0 {
+		return nil
+	}
+
+	// Like fields, method components do not inherit their owner's timeout tag
+	// or Recoverable policy
+	ctx = withFieldTimeout(ctx, 0)
+	ctx = withRecoveryPolicy(ctx, recoveryPolicy{})
+	for _, name := range methods {
+		methodPath := appendPath(path, name+"()")
+		logger.WithLevel(stepLogLevel(options)).
+			Str("path", pathToString(methodPath)).
+			Msg("Calling component method")
+
+		var component reflect.Value
+		if err := protect(options, func() error {
+			component = v.Addr().MethodByName(name).Call(nil)[0]
+			return nil
+		}); err != nil {
+			return &InitError{
+				Path:       methodPath,
+				FieldIndex: -1,
+				FieldType:  v.Addr().Type().String(),
+				Cause:      err,
+			}
+		}
+
+		if err := initStructWithVisited(ctx, component, v, methodPath, -1, logger, visited, options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package autoinit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// lazyApp constructs its cache on first use
+type lazyApp struct {
+	Name  string
+	cache *SimpleComponent
+	Calls int
+}
+
+func (a *lazyApp) Cache() *SimpleComponent {
+	a.Calls++
+	if a.cache == nil {
+		a.cache = &SimpleComponent{}
+	}
+	return a.cache
+}
+
+func (a *lazyApp) Missing() *SimpleComponent {
+	return nil
+}
+
+func (a *lazyApp) Name2() string {
+	return a.Name
+}
+
+func TestComponentMethods(t *testing.T) {
+	RegisterComponentMethod[lazyApp]("Cache")
+	RegisterComponentMethod[lazyApp]("Cache") // duplicates are ignored
+	RegisterComponentMethod[lazyApp]("Missing")
+
+	logger := zerolog.Nop()
+
+	// Without the option no method is called
+	app := &lazyApp{}
+	if err := WithOptions(context.Background(), app, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Calls != 0 {
+		t.Errorf("Cache called %d times without CallComponentMethods", app.Calls)
+	}
+
+	var paths []string
+	options := &Options{
+		Logger:               &logger,
+		CallComponentMethods: true,
+		OnEvent: func(e Event) {
+			if e.Phase == PhaseInit {
+				paths = append(paths, e.PathString())
+			}
+		},
+	}
+	type Root struct {
+		App *lazyApp
+	}
+	root := &Root{App: app}
+	if err := WithOptions(context.Background(), root, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Calls != 1 || !app.cache.Initialized {
+		t.Errorf("Cache calls = %d, initialized = %v; want one call and an initialized cache", app.Calls, app.cache != nil && app.cache.Initialized)
+	}
+	if len(paths) != 1 || paths[0] != "App.Cache()" {
+		t.Errorf("init paths = %v; want [App.Cache()]", paths)
+	}
+
+	assertPanics(t, func() { RegisterComponentMethod[lazyApp]("Nope") })
+	assertPanics(t, func() { RegisterComponentMethod[lazyApp]("Name2") })
+	assertPanics(t, func() { RegisterComponentMethod[int]("String") })
+}
+
+func assertPanics(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	fn()
+}
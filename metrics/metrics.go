@@ -0,0 +1,149 @@
+// Package metrics turns AutoInit lifecycle events into metrics for production
+// dashboards: per-component call durations and success/failure counts,
+// published through expvar or served in the Prometheus text exposition format.
+//
+// The package is a thin adapter over autoinit.Event and has no dependencies
+// beyond the standard library; Prometheus scrapes the handler directly, without
+// the Prometheus client library.
+//
+//	collector := metrics.NewCollector()
+//	options := &autoinit.Options{OnEvent: collector.OnEvent}
+//	err := autoinit.WithOptions(ctx, app, options)
+//
+//	collector.PublishExpvar("autoinit")        // under /debug/vars
+//	http.Handle("/metrics/autoinit", collector) // for Prometheus
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/telnet2/autoinit"
+)
+
+// Collector aggregates events per component path and lifecycle phase. It is
+// safe for concurrent use, so one collector can record several runs (e.g.
+// AutoInit and AutoShutdown) while being scraped.
+type Collector struct {
+	mu    sync.Mutex
+	stats map[statKey]*Stat
+}
+
+type statKey struct {
+	path  string
+	phase autoinit.EventPhase
+}
+
+// Stat holds the metrics recorded for one lifecycle phase of one component
+type Stat struct {
+	Path          string              // Dot-separated component path
+	Phase         autoinit.EventPhase // Lifecycle phase, e.g. Init
+	Type          string              // Component type the method was called on
+	Successes     int                 // Calls that returned nil
+	Failures      int                 // Calls that returned an error
+	LastDuration  time.Duration       // Duration of the most recent call
+	TotalDuration time.Duration       // Sum of all call durations
+}
+
+// NewCollector creates an empty collector
+func NewCollector() *Collector {
+	return &Collector{stats: make(map[statKey]*Stat)}
+}
+
+// OnEvent records an event. Use it as, or call it from, Options.OnEvent.
+func (c *Collector) OnEvent(e autoinit.Event) {
+	key := statKey{path: e.PathString(), phase: e.Phase}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stat, ok := c.stats[key]
+	if !ok {
+		stat = &Stat{Path: key.path, Phase: e.Phase}
+		c.stats[key] = stat
+	}
+	stat.Type = e.Type
+	if e.Err != nil {
+		stat.Failures++
+	} else {
+		stat.Successes++
+	}
+	stat.LastDuration = e.Duration
+	stat.TotalDuration += e.Duration
+}
+
+// Stats returns a copy of the recorded metrics, sorted by path and phase
+func (c *Collector) Stats() []Stat {
+	c.mu.Lock()
+	stats := make([]Stat, 0, len(c.stats))
+	for _, stat := range c.stats {
+		stats = append(stats, *stat)
+	}
+	c.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Path != stats[j].Path {
+			return stats[i].Path < stats[j].Path
+		}
+		return stats[i].Phase < stats[j].Phase
+	})
+	return stats
+}
+
+// PublishExpvar publishes the metrics as the expvar variable name, a JSON
+// list of Stat values with durations in nanoseconds. Like expvar.Publish it
+// panics if name is already in use, so call it once per process.
+func (c *Collector) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return c.Stats()
+	}))
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format:
+//
+//	autoinit_component_duration_seconds{path,phase,type}  gauge, last call
+//	autoinit_component_calls_total{path,phase,type,result} counter, result is success or failure
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	stats := c.Stats()
+	var b strings.Builder
+
+	b.WriteString("# HELP autoinit_component_duration_seconds Duration of the most recent lifecycle call of a component.\n")
+	b.WriteString("# TYPE autoinit_component_duration_seconds gauge\n")
+	for _, stat := range stats {
+		fmt.Fprintf(&b, "autoinit_component_duration_seconds{%s} %g\n", labels(stat), stat.LastDuration.Seconds())
+	}
+
+	b.WriteString("# HELP autoinit_component_calls_total Lifecycle calls of a component by result.\n")
+	b.WriteString("# TYPE autoinit_component_calls_total counter\n")
+	for _, stat := range stats {
+		fmt.Fprintf(&b, "autoinit_component_calls_total{%s,result=\"success\"} %d\n", labels(stat), stat.Successes)
+		fmt.Fprintf(&b, "autoinit_component_calls_total{%s,result=\"failure\"} %d\n", labels(stat), stat.Failures)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = c.WritePrometheus(w)
+}
+
+// labels formats the identifying labels of a stat
+func labels(stat Stat) string {
+	return fmt.Sprintf(`path="%s",phase="%s",type="%s"`,
+		escapeLabel(stat.Path), escapeLabel(string(stat.Phase)), escapeLabel(stat.Type))
+}
+
+// labelEscaper escapes label values as the exposition format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,85 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/telnet2/autoinit"
+	"github.com/telnet2/autoinit/metrics"
+)
+
+// Components have fields: pointers to zero-size values may share an address
+type slowComponent struct{ Name string }
+
+func (s *slowComponent) Init() error {
+	time.Sleep(2 * time.Millisecond)
+	return nil
+}
+
+type failingComponent struct{ Name string }
+
+func (f *failingComponent) Init() error {
+	return errors.New("boom")
+}
+
+func TestCollector(t *testing.T) {
+	type App struct {
+		Slow   *slowComponent
+		Broken *failingComponent
+	}
+
+	collector := metrics.NewCollector()
+	logger := zerolog.Nop()
+	options := &autoinit.Options{Logger: &logger, OnEvent: collector.OnEvent, ContinueOnError: true}
+	app := &App{Slow: &slowComponent{}, Broken: &failingComponent{}}
+	if err := autoinit.WithOptions(context.Background(), app, options); err == nil {
+		t.Fatal("expected Broken to fail")
+	}
+
+	stats := collector.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 stats, got %+v", stats)
+	}
+	broken, slow := stats[0], stats[1]
+	if broken.Path != "Broken" || broken.Failures != 1 || broken.Successes != 0 {
+		t.Errorf("Broken stat = %+v", broken)
+	}
+	if slow.Path != "Slow" || slow.Phase != autoinit.PhaseInit || slow.Successes != 1 || slow.LastDuration < 2*time.Millisecond {
+		t.Errorf("Slow stat = %+v", slow)
+	}
+
+	// Prometheus text format
+	recorder := httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+	for _, want := range []string{
+		"# TYPE autoinit_component_duration_seconds gauge",
+		`autoinit_component_duration_seconds{path="Slow",phase="Init",type="*metrics_test.slowComponent"} 0.00`,
+		`autoinit_component_calls_total{path="Broken",phase="Init",type="*metrics_test.failingComponent",result="failure"} 1`,
+		`autoinit_component_calls_total{path="Slow",phase="Init",type="*metrics_test.slowComponent",result="success"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("output missing %q:\n%s", want, body)
+		}
+	}
+	if ct := recorder.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	// expvar
+	collector.PublishExpvar("autoinit_test")
+	var published []metrics.Stat
+	if err := json.Unmarshal([]byte(expvar.Get("autoinit_test").String()), &published); err != nil {
+		t.Fatalf("invalid expvar JSON: %v", err)
+	}
+	if len(published) != 2 || published[1].Successes != 1 {
+		t.Errorf("published = %+v", published)
+	}
+}
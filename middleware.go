@@ -0,0 +1,85 @@
+package autoinit
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+)
+
+// InitFunc initializes a single component. component is the value the Init
+// method is called on (usually a pointer to the struct) and path is its
+// location in the tree, empty for the root.
+type InitFunc func(ctx context.Context, component interface{}, path []string) error
+
+// Middleware wraps an InitFunc to add cross-cutting behavior such as logging,
+// metrics, panic recovery or timeouts. A middleware may run code before and
+// after calling next, replace the context passed on, or not call next at all.
+//
+//	func Logging(next autoinit.InitFunc) autoinit.InitFunc {
+//	    return func(ctx context.Context, component interface{}, path []string) error {
+//	        log.Printf("init %T", component)
+//	        return next(ctx, component, path)
+//	    }
+//	}
+type Middleware func(next InitFunc) InitFunc
+
+// wrapMiddleware returns the InitFunc invoking call, wrapped by the configured middleware
+func wrapMiddleware(options *Options, call *lifecycleCall) InitFunc {
+	fn := InitFunc(func(ctx context.Context, _ interface{}, _ []string) error {
+		return call.invoke(ctx)
+	})
+	if options == nil {
+		return fn
+	}
+	for i := len(options.Middleware) - 1; i >= 0; i-- {
+		fn = options.Middleware[i](fn)
+	}
+	return fn
+}
+
+// RecoverMiddleware returns middleware that converts a panic in a component's
+// Init into a *PanicError, so it is reported like any other Init failure.
+func RecoverMiddleware() Middleware {
+	return func(next InitFunc) InitFunc {
+		return func(ctx context.Context, component interface{}, path []string) (err error) {
+			defer recoverInto(&err)
+			return next(ctx, component, path)
+		}
+	}
+}
+
+// protect calls fn, recovering a panic into a *PanicError if options.RecoverPanics is set
+func protect(options *Options, fn func() error) (err error) {
+	if options == nil || !options.RecoverPanics {
+		return fn()
+	}
+	defer recoverInto(&err)
+	return fn()
+}
+
+// recoverInto must be deferred; it turns a recovered panic into a *PanicError stored in *err
+func recoverInto(err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Value: r, Stack: debug.Stack()}
+	}
+}
+
+// TimingMiddleware returns middleware that reports how long each component's
+// Init took, whether or not it succeeded.
+//
+//	autoinit.TimingMiddleware(func(path []string, component interface{}, elapsed time.Duration) {
+//	    log.Printf("%T initialized in %s", component, elapsed)
+//	})
+func TimingMiddleware(record func(path []string, component interface{}, elapsed time.Duration)) Middleware {
+	return func(next InitFunc) InitFunc {
+		return func(ctx context.Context, component interface{}, path []string) error {
+			start := time.Now()
+			err := next(ctx, component, path)
+			elapsed := time.Since(start)
+			_, unlock := lockCallbacks(ctx)
+			defer unlock()
+			record(path, component, elapsed)
+			return err
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type panickingComponent struct{}
+
+func (p *panickingComponent) Init() error {
+	panic("boom")
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	tracing := func(name string) Middleware {
+		return func(next InitFunc) InitFunc {
+			return func(ctx context.Context, component interface{}, path []string) error {
+				calls = append(calls, name+" before "+pathToString(path))
+				err := next(ctx, component, path)
+				calls = append(calls, name+" after "+pathToString(path))
+				return err
+			}
+		}
+	}
+
+	type App struct {
+		Component *SimpleComponent
+	}
+	app := &App{Component: &SimpleComponent{}}
+
+	logger := zerolog.Nop()
+	options := &Options{
+		Logger:     &logger,
+		Middleware: []Middleware{tracing("outer"), tracing("inner")},
+	}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"outer before Component",
+		"inner before Component",
+		"inner after Component",
+		"outer after Component",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v; want %v", calls, expected)
+	}
+	if !app.Component.Initialized {
+		t.Error("component should be initialized through the middleware chain")
+	}
+}
+
+func TestBuiltinMiddleware(t *testing.T) {
+	type App struct {
+		Fine  *SimpleComponent
+		Panic *panickingComponent
+	}
+	app := &App{Fine: &SimpleComponent{}, Panic: &panickingComponent{}}
+
+	timings := map[string]time.Duration{}
+	logger := zerolog.Nop()
+	options := &Options{
+		Logger: &logger,
+		Middleware: []Middleware{
+			TimingMiddleware(func(path []string, component interface{}, elapsed time.Duration) {
+				timings[pathToString(path)] = elapsed
+			}),
+			RecoverMiddleware(),
+		},
+	}
+
+	err := WithOptions(context.Background(), app, options)
+	var initErr *InitError
+	if !errors.As(err, &initErr) || pathToString(initErr.Path) != "Panic" {
+		t.Fatalf("expected InitError for Panic, got %v", err)
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a PanicError cause, got %v", err)
+	}
+	if panicErr.Value != "boom" || !strings.Contains(string(panicErr.Stack), "panickingComponent") {
+		t.Errorf("unexpected panic error: %v", panicErr)
+	}
+
+	if _, ok := timings["Fine"]; !ok {
+		t.Error("expected a timing for Fine")
+	}
+	if _, ok := timings["Panic"]; !ok {
+		t.Error("expected a timing for the panicking component")
+	}
+}
+
+type panickingPreInit struct {
+	Child *SimpleComponent
+}
+
+func (p *panickingPreInit) PreInit(ctx context.Context) error {
+	panic(errors.New("pre-init exploded"))
+}
+
+func TestRecoverPanics(t *testing.T) {
+	type App struct {
+		Fine  *SimpleComponent
+		Boom  *panickingComponent
+		Early *panickingPreInit
+	}
+
+	logger := zerolog.Nop()
+	app := &App{Fine: &SimpleComponent{}, Boom: &panickingComponent{}, Early: &panickingPreInit{Child: &SimpleComponent{}}}
+	err := WithOptions(context.Background(), app, &Options{Logger: &logger, RecoverPanics: true, ContinueOnError: true})
+	if err == nil {
+		t.Fatal("expected errors from the panicking components")
+	}
+
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errs), err)
+	}
+	for i, want := range []string{"Boom", "Early"} {
+		var initErr *InitError
+		var panicErr *PanicError
+		if !errors.As(errs[i], &initErr) || pathToString(initErr.Path) != want {
+			t.Errorf("error %d: expected InitError for %s, got %v", i, want, errs[i])
+		}
+		if !errors.As(errs[i], &panicErr) || len(panicErr.Stack) == 0 {
+			t.Errorf("error %d: expected PanicError with stack, got %v", i, errs[i])
+		}
+	}
+	// The panic value is part of the error message
+	if !strings.Contains(errs[1].Error(), "pre-init exploded") {
+		t.Errorf("unexpected message: %v", errs[1])
+	}
+	if !app.Fine.Initialized || app.Early.Child.Initialized {
+		t.Error("healthy components should initialize; children of a failed PreInit should not")
+	}
+
+	// Without the option the panic propagates
+	defer func() {
+		if recover() == nil {
+			t.Error("expected the panic to propagate without RecoverPanics")
+		}
+	}()
+	_ = WithOptions(context.Background(), &App{Boom: &panickingComponent{}}, &Options{Logger: &logger})
+}
@@ -3,6 +3,7 @@ package autoinit
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -371,3 +372,63 @@ func TestNilParentForRoot(t *testing.T) {
 		t.Error("root struct should receive nil parent")
 	}
 }
+
+// ReflectiveValidator inspects its siblings through the parent's reflect.Value
+type ReflectiveValidator struct {
+	Siblings    []string
+	RootParent  bool
+	Initialized bool
+}
+
+func (r *ReflectiveValidator) Init() error {
+	return fmt.Errorf("Init should not be called when InitReflect is implemented")
+}
+
+func (r *ReflectiveValidator) InitReflect(ctx context.Context, parent reflect.Value) error {
+	r.Initialized = true
+	if !parent.IsValid() {
+		r.RootParent = true
+		return nil
+	}
+	for i := 0; i < parent.NumField(); i++ {
+		field := parent.Field(i)
+		r.Siblings = append(r.Siblings, fmt.Sprintf("%s=%v", parent.Type().Field(i).Name, field))
+	}
+	// The parent is live: writes through it persist
+	if parent.CanAddr() {
+		parent.FieldByName("Port").SetInt(8080)
+	}
+	return nil
+}
+
+func TestReflectiveInitInterface(t *testing.T) {
+	type Server struct {
+		Validator *ReflectiveValidator
+		Port      int
+		secret    string
+	}
+
+	server := &Server{Validator: &ReflectiveValidator{}, secret: "hidden"}
+	if err := AutoInit(context.Background(), server); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !server.Validator.Initialized {
+		t.Fatal("InitReflect should be called")
+	}
+	if server.Port != 8080 {
+		t.Errorf("Port = %d; changes through parent should persist", server.Port)
+	}
+	// Unexported siblings are readable
+	got := strings.Join(server.Validator.Siblings[1:], ",")
+	if got != "Port=0,secret=hidden" {
+		t.Errorf("siblings = %q", got)
+	}
+
+	root := &ReflectiveValidator{}
+	if err := AutoInit(context.Background(), root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !root.RootParent {
+		t.Error("root should receive the zero Value")
+	}
+}
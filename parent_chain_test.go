@@ -0,0 +1,153 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type chainConfig struct {
+	Env string
+}
+
+type chainWorker struct {
+	config *chainConfig
+	root   *chainApp
+}
+
+func (w *chainWorker) Init(ctx context.Context, parent interface{}) error {
+	finder := NewComponentFinder(ctx, w, parent)
+	if cfg := finder.Find(&SearchOption{
+		ByType: reflect.TypeOf((*chainConfig)(nil)),
+	}); cfg != nil {
+		w.config = cfg.(*chainConfig)
+	}
+	if root := finder.FindAncestor(&SearchOption{
+		ByType: reflect.TypeOf((*chainApp)(nil)),
+	}); root != nil {
+		w.root = root.(*chainApp)
+	}
+	return nil
+}
+
+type chainModule struct {
+	Worker *chainWorker
+}
+
+type chainApp struct {
+	Config *chainConfig
+	Module *chainModule
+}
+
+func TestWithParentChainSubtreeInit(t *testing.T) {
+	app := &chainApp{
+		Config: &chainConfig{Env: "prod"},
+		Module: &chainModule{Worker: &chainWorker{}},
+	}
+
+	// Initialize only the module, without seeding ancestors
+	if err := AutoInit(context.Background(), app.Module); err != nil {
+		t.Fatalf("AutoInit failed: %v", err)
+	}
+	if app.Module.Worker.config != nil || app.Module.Worker.root != nil {
+		t.Fatal("worker should not see ancestors outside the initialized subtree")
+	}
+
+	// Seed the chain with the real ancestors
+	ctx := WithParentChain(context.Background(), app)
+	if err := AutoInit(ctx, app.Module); err != nil {
+		t.Fatalf("AutoInit failed: %v", err)
+	}
+	if app.Module.Worker.config != app.Config {
+		t.Error("worker should find Config on a seeded ancestor")
+	}
+	if app.Module.Worker.root != app {
+		t.Error("worker should find the seeded root as an ancestor")
+	}
+}
+
+func TestWithParentChainSeedsChain(t *testing.T) {
+	root := &chainApp{}
+	module := &chainModule{}
+
+	ctx := WithParentChain(context.Background(), root, module)
+	chain := getParentChain(ctx)
+	if chain == nil {
+		t.Fatal("expected a parent chain on the context")
+	}
+	if chain.Len() != 2 {
+		t.Fatalf("chain length = %d; want 2", chain.Len())
+	}
+	if chain.GetParent(0) != module || chain.GetParent(1) != root {
+		t.Error("ancestors should be ordered from root to innermost")
+	}
+}
+
+type ancestryLeaf struct {
+	app       *ancestryApp
+	module    *ancestryModule
+	parent    *ancestryModule
+	grand     *ancestryApp
+	wrongType bool
+	tooFar    bool
+}
+
+func (l *ancestryLeaf) Init(ctx context.Context) error {
+	l.app, _ = AncestorOfType[*ancestryApp](ctx)
+	l.module, _ = AncestorOfType[*ancestryModule](ctx)
+	l.parent, _ = AncestorAtLevel[*ancestryModule](ctx, 0)
+	l.grand, _ = AncestorAtLevel[*ancestryApp](ctx, 2)
+	_, l.wrongType = AncestorAtLevel[*ancestryApp](ctx, 0)
+	_, l.tooFar = AncestorAtLevel[*ancestryApp](ctx, 10)
+	return nil
+}
+
+type ancestrySection struct {
+	Leaf ancestryLeaf
+}
+
+type ancestryModule struct {
+	Section *ancestrySection
+}
+
+type ancestryApp struct {
+	Module *ancestryModule
+}
+
+func TestTypedAncestorLookups(t *testing.T) {
+	app := &ancestryApp{Module: &ancestryModule{Section: &ancestrySection{}}}
+
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("AutoInit failed: %v", err)
+	}
+
+	leaf := &app.Module.Section.Leaf
+	if leaf.app != app {
+		t.Error("AncestorOfType should find the root app")
+	}
+	if leaf.module != app.Module {
+		t.Error("AncestorOfType should find the enclosing module")
+	}
+	// The immediate parent is the section, not a module
+	if leaf.parent != nil {
+		t.Error("AncestorAtLevel(0) should not match a non-module parent")
+	}
+	if leaf.grand != app {
+		t.Error("AncestorAtLevel(2) should be the root app")
+	}
+	if leaf.wrongType {
+		t.Error("AncestorAtLevel should report false for a type mismatch")
+	}
+	if leaf.tooFar {
+		t.Error("AncestorAtLevel should report false beyond the root")
+	}
+}
+
+func TestTypedAncestorLookupsWithoutChain(t *testing.T) {
+	if _, ok := AncestorOfType[*ancestryApp](context.Background()); ok {
+		t.Error("expected no ancestor without a parent chain")
+	}
+	if _, ok := AncestorAtLevel[*ancestryApp](context.Background(), 0); ok {
+		t.Error("expected no ancestor without a parent chain")
+	}
+}
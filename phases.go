@@ -0,0 +1,164 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	phaseKey     contextKey = "autoinit:phase"
+	phasePassKey contextKey = "autoinit:phasePass"
+	planNodeKey  contextKey = "autoinit:planNode"
+)
+
+// phasePass is the pass of a phased run being executed (see Options.Phases),
+// or of a run following Options.InitPlan
+type phasePass struct {
+	name  string // Phase whose components run their lifecycle, "" for untagged ones
+	first bool   // Whether this is the first pass, which records skipped fields
+	// planned holds the paths of Options.InitPlan in a planned run, whose
+	// passes each select the component at path name, and finally ("") every
+	// component not in the plan
+	planned map[string]bool
+	// layers holds the layer of every component of the tree in a layered
+	// run (see AutoInitLayers), whose passes each select the components of
+	// layer and collect their lifecycles in jobs
+	layers map[string]int
+	layer  int
+	jobs   *layerJobs
+}
+
+// initPhases initializes the tree rooted at v: once, or with Options.Phases
+// once per phase followed by a pass for components in no phase. Each pass
+// completes across the whole tree before the next one starts.
+func initPhases(ctx context.Context, v reflect.Value, logger *zerolog.Logger, options *Options) error {
+	// A nested run starts outside the phases of the run that started it
+	ctx = context.WithValue(ctx, phaseKey, "")
+	ctx = context.WithValue(ctx, phasePassKey, (*phasePass)(nil))
+
+	if options != nil && options.InitPlan != nil {
+		return initPlanned(ctx, v, logger, options)
+	}
+	if options == nil || len(options.Phases) == 0 {
+		return initStructWithVisited(ctx, v, reflect.Value{}, []string{}, -1, logger, newVisited(options), options)
+	}
+	if err := validatePhases(v, options); err != nil {
+		return err
+	}
+
+	passes := append(append([]string(nil), options.Phases...), "")
+	for i, phase := range passes {
+		if phase != "" {
+			logger.WithLevel(stepLogLevel(options)).
+				Str("phase", phase).
+				Msg("Starting phase")
+		}
+		passCtx := context.WithValue(ctx, phasePassKey, &phasePass{name: phase, first: i == 0})
+		if err := initStructWithVisited(passCtx, v, reflect.Value{}, []string{}, -1, logger, newVisited(options), options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newVisited returns the map used for cycle detection in one traversal, nil
+// if cycle detection is disabled
+func newVisited(options *Options) map[uintptr]bool {
+	if options != nil && options.DisableCycleDetection {
+		return nil
+	}
+	return make(map[uintptr]bool)
+}
+
+// validatePhases checks that options.Phases are distinct and non-empty, and
+// that every phase=name tag in the tree names one of them
+func validatePhases(root reflect.Value, options *Options) error {
+	known := make(map[string]bool, len(options.Phases))
+	for _, phase := range options.Phases {
+		if phase == "" {
+			return fmt.Errorf("autoinit: empty phase name in Options.Phases")
+		}
+		if known[phase] {
+			return fmt.Errorf("autoinit: duplicate phase %q in Options.Phases", phase)
+		}
+		known[phase] = true
+	}
+
+	return walkTree(root, options, func(node componentNode) error {
+		if phase, ok := node.tag.value("phase"); ok && !known[phase] {
+			return fmt.Errorf("field %s: unknown autoinit phase %q (Options.Phases: %v)", pathToString(node.path), phase, options.Phases)
+		}
+		return nil
+	})
+}
+
+// withFieldPhase returns ctx for the traversal below a field, entering the
+// phase named by its tag if it has one
+func withFieldPhase(ctx context.Context, tag tagOptions) context.Context {
+	if phase, ok := tag.value("phase"); ok {
+		return context.WithValue(ctx, phaseKey, phase)
+	}
+	return ctx
+}
+
+// currentPhasePass returns the pass being executed, nil outside a phased run
+func currentPhasePass(ctx context.Context) *phasePass {
+	pass, _ := ctx.Value(phasePassKey).(*phasePass)
+	return pass
+}
+
+// inSelectedPhase reports whether the component being traversed belongs to
+// the phase of the current pass, or in a planned run is the component it
+// selects. Outside a phased or planned run every component does.
+func inSelectedPhase(ctx context.Context) bool {
+	pass := currentPhasePass(ctx)
+	if pass == nil {
+		return true
+	}
+	if pass.layers != nil {
+		node, _ := ctx.Value(planNodeKey).(string)
+		return pass.layers[node] == pass.layer
+	}
+	if pass.planned != nil {
+		node, _ := ctx.Value(planNodeKey).(string)
+		if pass.name == "" {
+			return !pass.planned[node]
+		}
+		return node == pass.name
+	}
+	phase, _ := ctx.Value(phaseKey).(string)
+	return phase == pass.name
+}
+
+// withPlanNode returns ctx for the traversal of the node at path in a planned
+// or layered run, which select components by path. A layered run only knows
+// the components in the tree before it started; the others belong to the
+// nearest known component enclosing them.
+func withPlanNode(ctx context.Context, path string) context.Context {
+	pass := currentPhasePass(ctx)
+	if pass == nil || (pass.planned == nil && pass.layers == nil) {
+		return ctx
+	}
+	if _, known := pass.layers[path]; pass.layers != nil && !known {
+		return ctx
+	}
+	return context.WithValue(ctx, planNodeKey, path)
+}
+
+// recordsSkips reports whether skipped fields are recorded in the current
+// traversal. A phased run traverses the tree once per pass but records them
+// only in the first one.
+func recordsSkips(ctx context.Context) bool {
+	pass := currentPhasePass(ctx)
+	return pass == nil || pass.first
+}
+
+// lifecycleActive reports whether the component being traversed runs its
+// lifecycle methods and field hooks: it must be in the group selected by
+// InitGroup and in the phase of the current pass
+func lifecycleActive(ctx context.Context, options *Options) bool {
+	return inSelectedGroup(ctx, options) && inSelectedPhase(ctx)
+}
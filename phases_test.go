@@ -0,0 +1,115 @@
+package autoinit
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// phaseComponent records its Init in the shared order slice
+type phaseComponent struct {
+	Name  string
+	order *[]string
+}
+
+func (c *phaseComponent) Init() error {
+	*c.order = append(*c.order, c.Name)
+	return nil
+}
+
+type phaseService struct {
+	Config *phaseComponent `autoinit:"phase=config"`
+	Conn   *phaseComponent `autoinit:"phase=connect"`
+	Helper *phaseComponent
+	order  *[]string
+}
+
+func (s *phaseService) Init() error {
+	*s.order = append(*s.order, "service")
+	return nil
+}
+
+type phaseApp struct {
+	API    *phaseService
+	Worker *phaseService
+	Server *phaseComponent `autoinit:"phase=serve"`
+}
+
+func newPhaseApp(order *[]string) *phaseApp {
+	component := func(name string) *phaseComponent {
+		return &phaseComponent{Name: name, order: order}
+	}
+	service := func(prefix string) *phaseService {
+		return &phaseService{
+			Config: component(prefix + ".config"),
+			Conn:   component(prefix + ".conn"),
+			Helper: component(prefix + ".helper"),
+			order:  order,
+		}
+	}
+	return &phaseApp{API: service("api"), Worker: service("worker"), Server: component("server")}
+}
+
+func TestPhases(t *testing.T) {
+	var order []string
+	app := newPhaseApp(&order)
+	options := &Options{Phases: []string{"config", "connect", "serve"}}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// All configs across both subtrees, then all connections, then the
+	// server, then the untagged components in tree order
+	want := []string{
+		"api.config", "worker.config",
+		"api.conn", "worker.conn",
+		"server",
+		"api.helper", "service", "worker.helper", "service",
+	}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("init order = %v, want %v", order, want)
+	}
+
+	// Without Phases, tags are ignored and the tree order applies
+	order = nil
+	if err := AutoInit(context.Background(), newPhaseApp(&order)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order[0] != "api.config" || order[1] != "api.conn" || order[2] != "api.helper" {
+		t.Errorf("unphased init order = %v", order)
+	}
+
+	// A phase missing from Options.Phases fails before anything initializes
+	order = nil
+	err := WithOptions(context.Background(), newPhaseApp(&order), &Options{Phases: []string{"config", "connect"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown autoinit phase "serve"`) {
+		t.Errorf("expected unknown phase error, got %v", err)
+	}
+	if len(order) != 0 {
+		t.Errorf("components initialized despite invalid phases: %v", order)
+	}
+}
+
+func TestPhasesReport(t *testing.T) {
+	var order []string
+	app := newPhaseApp(&order)
+	app.Worker.Helper = nil
+
+	report := &InitReport{}
+	options := &Options{Phases: []string{"config", "connect", "serve"}, Report: report}
+	tree, err := AutoInitTreeReport(context.Background(), app, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Every pass traverses the tree, but skips and nodes are recorded once
+	if len(report.Skipped) != 1 {
+		t.Errorf("skipped = %v, want the nil helper once", report.Skipped)
+	}
+	if len(tree.Children) != 3 {
+		t.Errorf("root has %d children, want 3", len(tree.Children))
+	}
+	if worker := tree.Children[1]; len(worker.Children) != 3 {
+		t.Errorf("worker has %d children, want 3", len(worker.Children))
+	}
+}
@@ -0,0 +1,96 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// PlanError is returned when Options.InitPlan does not fit the tree. No
+// component is initialized in that case.
+type PlanError struct {
+	Unknown   []string // Planned paths that do not name a component in the tree
+	Duplicate []string // Paths listed more than once
+	Missing   []string // Paths of required components the plan omits
+}
+
+// Error lists the problems with the plan
+func (e *PlanError) Error() string {
+	var problems []string
+	if len(e.Unknown) > 0 {
+		problems = append(problems, "unknown paths "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Duplicate) > 0 {
+		problems = append(problems, "duplicate paths "+strings.Join(e.Duplicate, ", "))
+	}
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing required components "+strings.Join(e.Missing, ", "))
+	}
+	return "invalid init plan: " + strings.Join(problems, "; ")
+}
+
+// initPlanned initializes the tree rooted at v following Options.InitPlan:
+// one pass per planned path, then a pass for everything not in the plan
+func initPlanned(ctx context.Context, v reflect.Value, logger *zerolog.Logger, options *Options) error {
+	if len(options.Phases) > 0 {
+		return fmt.Errorf("autoinit: Options.InitPlan cannot be combined with Options.Phases")
+	}
+	planned, err := validatePlan(v, options)
+	if err != nil {
+		return err
+	}
+
+	passes := append(append([]string(nil), options.InitPlan...), "")
+	for i, path := range passes {
+		if path != "" {
+			logger.Trace().
+				Str("path", path).
+				Int("step", i+1).
+				Msg("Following init plan")
+		}
+		passCtx := context.WithValue(ctx, phasePassKey, &phasePass{name: path, first: i == 0, planned: planned})
+		if err := initStructWithVisited(passCtx, v, reflect.Value{}, []string{}, -1, logger, newVisited(options), options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePlan checks options.InitPlan against the tree rooted at root and
+// returns the set of planned paths
+func validatePlan(root reflect.Value, options *Options) (map[string]bool, error) {
+	planned := make(map[string]bool, len(options.InitPlan))
+	planErr := &PlanError{}
+	for _, path := range options.InitPlan {
+		if planned[path] {
+			planErr.Duplicate = append(planErr.Duplicate, path)
+		}
+		planned[path] = true
+	}
+
+	conventions := options.RecognizeConventions
+	exists := make(map[string]bool)
+	if err := walkTree(root, options, func(node componentNode) error {
+		path := pathToString(node.path)
+		exists[path] = true
+		if !planned[path] && !node.tag.has("optional") && resolveInitializer(node.value, node.parent, conventions) != nil {
+			planErr.Missing = append(planErr.Missing, path)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for _, path := range options.InitPlan {
+		if !exists[path] {
+			planErr.Unknown = append(planErr.Unknown, path)
+		}
+	}
+
+	if len(planErr.Unknown) > 0 || len(planErr.Duplicate) > 0 || len(planErr.Missing) > 0 {
+		return nil, planErr
+	}
+	return planned, nil
+}
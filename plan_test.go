@@ -0,0 +1,80 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type planComponent struct {
+	Name  string
+	order *[]string
+}
+
+func (c *planComponent) Init() error {
+	*c.order = append(*c.order, c.Name)
+	return nil
+}
+
+// planModule has no initializer, so it need not be planned
+type planModule struct {
+	Cache *planComponent
+	Queue *planComponent
+}
+
+type planApp struct {
+	Config  *planComponent
+	Module  *planModule
+	Metrics *planComponent `autoinit:"optional"`
+}
+
+func newPlanApp(order *[]string) *planApp {
+	component := func(name string) *planComponent {
+		return &planComponent{Name: name, order: order}
+	}
+	return &planApp{
+		Config:  component("config"),
+		Module:  &planModule{Cache: component("cache"), Queue: component("queue")},
+		Metrics: component("metrics"),
+	}
+}
+
+func TestInitPlan(t *testing.T) {
+	// A dry run records the natural order as a plan
+	var order []string
+	report := &InitReport{}
+	if err := WithOptions(context.Background(), newPlanApp(&order), &Options{Report: report}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(report.InitPlan(), ","); got != "Config,Module.Cache,Module.Queue,Metrics" {
+		t.Errorf("recorded plan = %s", got)
+	}
+
+	// The plan overrides the tree order; unplanned optional components run last
+	order = nil
+	options := &Options{InitPlan: []string{"Module.Queue", "Config", "Module.Cache"}}
+	if err := WithOptions(context.Background(), newPlanApp(&order), options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(order, ","); got != "queue,config,cache,metrics" {
+		t.Errorf("init order = %s, want queue,config,cache,metrics", got)
+	}
+
+	// Unknown paths and omitted required components fail before anything runs
+	order = nil
+	options = &Options{InitPlan: []string{"Config", "Module.Missing", "Config"}}
+	err := WithOptions(context.Background(), newPlanApp(&order), options)
+	var planErr *PlanError
+	if !errors.As(err, &planErr) {
+		t.Fatalf("expected PlanError, got %v", err)
+	}
+	if strings.Join(planErr.Unknown, ",") != "Module.Missing" ||
+		strings.Join(planErr.Duplicate, ",") != "Config" ||
+		strings.Join(planErr.Missing, ",") != "Module.Cache,Module.Queue" {
+		t.Errorf("unexpected plan error: %v", planErr)
+	}
+	if len(order) != 0 {
+		t.Errorf("components initialized despite an invalid plan: %v", order)
+	}
+}
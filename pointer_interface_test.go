@@ -0,0 +1,64 @@
+package autoinit
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+// generatedConn is a component reached through a pointer to an interface
+type generatedConn struct {
+	initialized bool
+	closed      bool
+}
+
+func (c *generatedConn) Init() error {
+	c.initialized = true
+	return nil
+}
+
+func (c *generatedConn) Shutdown(ctx context.Context) error {
+	c.closed = true
+	return nil
+}
+
+func (c *generatedConn) Close() error { return nil }
+
+type generatedClient struct {
+	Conn    *io.Closer
+	Nil     *io.Closer
+	NilConn *io.Closer
+
+	hooked interface{}
+}
+
+func (g *generatedClient) PostFieldInit(ctx context.Context, fieldName string, fieldValue interface{}) error {
+	if fieldName == "Conn" {
+		g.hooked = fieldValue
+	}
+	return nil
+}
+
+func TestPointerToInterfaceField(t *testing.T) {
+	conn := &generatedConn{}
+	var closer io.Closer = conn
+	var nilCloser io.Closer
+	client := &generatedClient{Conn: &closer, NilConn: &nilCloser}
+
+	if err := AutoInit(context.Background(), client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !conn.initialized {
+		t.Error("component behind *io.Closer was not initialized")
+	}
+	if client.hooked != client.Conn {
+		t.Errorf("PostFieldInit got %v, want the *io.Closer field", client.hooked)
+	}
+
+	if err := AutoShutdown(context.Background(), client, nil); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if !conn.closed {
+		t.Error("component behind *io.Closer was not shut down")
+	}
+}
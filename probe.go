@@ -0,0 +1,192 @@
+package autoinit
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ProbedComponent is what Probe found out about one component
+type ProbedComponent struct {
+	Path []string // Path of the component, empty for the root
+	Type string   // Type of the component
+	// Initializer is the initializer AutoInit resolves for the component,
+	// e.g. "Init(ctx, parent)", or "" if it has none
+	Initializer string
+	// Arity is the number of arguments Initializer takes, -1 if there is none
+	Arity int
+	// Calls lists the component's own lifecycle methods AutoInit would call,
+	// in order: PreInit, the initializer, PostInit, then Link after the
+	// whole tree. Field hooks are called by the parent and not listed.
+	Calls []string
+	// Shadowed lists initializers the component implements that lose to
+	// Initializer under the priority rules, or that the options do not
+	// recognize, and are therefore never called
+	Shadowed []string
+	// Issues lists lifecycle methods whose signature AutoInit does not
+	// recognize, as CheckStruct reports them, and other reasons the
+	// component would not initialize as it may be expected to
+	Issues []string
+}
+
+// PathString returns the component's path in dotted form, "<root>" for the root
+func (c ProbedComponent) PathString() string {
+	return pathToString(c.Path)
+}
+
+// String describes the component on one line, e.g.
+// "Service (*app.Service): PreInit(ctx) -> Init(ctx, parent)"
+func (c ProbedComponent) String() string {
+	calls := "no lifecycle methods"
+	if len(c.Calls) > 0 {
+		calls = strings.Join(c.Calls, " -> ")
+	}
+	s := fmt.Sprintf("%s (%s): %s", c.PathString(), c.Type, calls)
+	if len(c.Shadowed) > 0 {
+		s += " [not called: " + strings.Join(c.Shadowed, ", ") + "]"
+	}
+	for _, issue := range c.Issues {
+		s += " [" + issue + "]"
+	}
+	return s
+}
+
+// ProbeResult lists the components of a tree as Probe found them
+type ProbeResult struct {
+	// Components in the order AutoInit would call their initializers
+	Components []ProbedComponent
+}
+
+// String lists the components one per line
+func (r ProbeResult) String() string {
+	lines := make([]string, len(r.Components))
+	for i, component := range r.Components {
+		lines[i] = component.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Component returns the probed component at path ("<root>" for the target)
+func (r ProbeResult) Component(path string) (ProbedComponent, bool) {
+	for _, component := range r.Components {
+		if component.PathString() == path {
+			return component, true
+		}
+	}
+	return ProbedComponent{}, false
+}
+
+// Probe traverses target like AutoInit would and reports, per component,
+// which lifecycle methods it would call and in what order, without calling
+// any of them or modifying the tree. It shows how each initializer is
+// resolved under the priority rules (InitReflect(ctx, parent) >
+// Init(ctx, parent) > Init(ctx) > Init()), e.g. to confirm a component is
+// detected as a ParentInitializer:
+//
+//	result, _ := autoinit.Probe(app, nil)
+//	fmt.Println(result)
+//
+// Options are honored where they change what is called, e.g.
+// RecognizeConventions and DisablePreInit. Components are listed in tree
+// order; Phases and InitPlan, which reorder initialization, are not applied.
+func Probe(target interface{}, options *Options) (ProbeResult, error) {
+	var result ProbeResult
+	v, err := resolveTarget(target, "probe")
+	if err != nil {
+		return result, err
+	}
+
+	conventions := options != nil && options.RecognizeConventions
+	err = walkTree(v, options, func(node componentNode) error {
+		result.Components = append(result.Components, probeComponent(node, conventions, options))
+		return nil
+	})
+	return result, err
+}
+
+// probeComponent resolves the lifecycle methods of one component
+func probeComponent(node componentNode, conventions bool, options *Options) ProbedComponent {
+	v := node.value
+	ptr := v
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		ptr = v.Addr()
+	}
+	probed := ProbedComponent{
+		Path:   node.path,
+		Type:   ptr.Type().String(),
+		Arity:  -1,
+		Issues: lifecycleIssues(v.Type()),
+	}
+	if !ptr.CanInterface() {
+		return probed
+	}
+	component := ptr.Interface()
+
+	if _, ok := component.(PreInitializer); ok && (options == nil || !options.DisablePreInit) {
+		probed.Calls = append(probed.Calls, "PreInit(ctx)")
+	}
+
+	if call := resolveInitializer(v, node.parent, conventions); call != nil {
+		probed.Initializer = call.method
+		probed.Arity = initializerArity(call.method)
+		probed.Calls = append(probed.Calls, call.method)
+		if call.copied {
+			probed.Issues = append(probed.Issues, "initializer called on a copy; changes will not persist")
+		}
+	} else if v.Kind() == reflect.Struct && !v.CanAddr() && resolveInitializer(reflect.New(v.Type()), node.parent, conventions) != nil {
+		probed.Issues = append(probed.Issues, "pointer-receiver initializer on a non-addressable value; Init would fail")
+	}
+	probed.Shadowed = shadowedInitializers(component, probed.Initializer, conventions)
+
+	if _, ok := component.(PostInitializer); ok && (options == nil || !options.DisablePostInit) {
+		probed.Calls = append(probed.Calls, "PostInit(ctx)")
+	}
+	if _, ok := component.(Linker); ok {
+		probed.Calls = append(probed.Calls, "Link(ctx, parent)")
+	}
+	return probed
+}
+
+// shadowedInitializers returns the initializers component implements other
+// than resolved, the one AutoInit calls
+func shadowedInitializers(component interface{}, resolved string, conventions bool) []string {
+	var shadowed []string
+	if _, ok := component.(ReflectiveInitializer); ok && resolved != "InitReflect(ctx, parent)" {
+		shadowed = append(shadowed, "InitReflect(ctx, parent)")
+	}
+	for _, init := range []struct {
+		implemented bool
+		method      string
+	}{
+		{implementsInit[ParentInitializer](component), "Init(ctx, parent)"},
+		{implementsInit[ContextInitializer](component), "Init(ctx)"},
+		{implementsInit[SimpleInitializer](component), "Init()"},
+	} {
+		if init.implemented && resolved != init.method {
+			shadowed = append(shadowed, init.method)
+		}
+	}
+	if _, ok := component.(Starter); ok && resolved != "Start(ctx)" {
+		if conventions || resolved != "" {
+			shadowed = append(shadowed, "Start(ctx)")
+		} else {
+			shadowed = append(shadowed, "Start(ctx) (requires Options.RecognizeConventions)")
+		}
+	}
+	return shadowed
+}
+
+// implementsInit reports whether component implements the initializer I
+func implementsInit[I any](component interface{}) bool {
+	_, ok := component.(I)
+	return ok
+}
+
+// initializerArity returns the number of arguments of a resolved initializer
+func initializerArity(method string) int {
+	args := method[strings.Index(method, "(")+1 : len(method)-1]
+	if args == "" {
+		return 0
+	}
+	return strings.Count(args, ",") + 1
+}
@@ -0,0 +1,122 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The probed components panic if any lifecycle method is actually called.
+// They are not empty, so that their pointers are distinct.
+
+type probeParentAware struct{ Name string }
+
+func (p *probeParentAware) Init(ctx context.Context, parent interface{}) error { panic("called") }
+
+type probeReflective struct{ Name string }
+
+func (p *probeReflective) InitReflect(ctx context.Context, parent reflect.Value) error {
+	panic("called")
+}
+func (p *probeReflective) Init(ctx context.Context) error { panic("called") }
+
+type probeStarter struct{ Name string }
+
+func (p *probeStarter) Start(ctx context.Context) error { panic("called") }
+
+// probeMistyped meant to be a ParentInitializer but took the wrong arguments
+type probeMistyped struct{ Name string }
+
+func (p *probeMistyped) Init(parent interface{}) error { panic("called") }
+
+type probeFull struct {
+	Name string
+}
+
+func (p *probeFull) PreInit(ctx context.Context) error                  { panic("called") }
+func (p *probeFull) Init() error                                        { panic("called") }
+func (p *probeFull) PostInit(ctx context.Context) error                 { panic("called") }
+func (p *probeFull) Link(ctx context.Context, parent interface{}) error { panic("called") }
+
+type probeApp struct {
+	Parent    *probeParentAware
+	Reflect   *probeReflective
+	Starter   *probeStarter
+	Mistyped  *probeMistyped
+	Full      probeFull
+	Container struct{ Inner *probeFull }
+}
+
+func TestProbe(t *testing.T) {
+	app := &probeApp{
+		Parent:   &probeParentAware{},
+		Reflect:  &probeReflective{},
+		Starter:  &probeStarter{},
+		Mistyped: &probeMistyped{},
+	}
+	app.Container.Inner = &probeFull{Name: "inner"}
+
+	result, err := Probe(app, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var paths []string
+	for _, component := range result.Components {
+		paths = append(paths, component.PathString())
+	}
+	if want := "Parent,Reflect,Starter,Mistyped,Full,Container.Inner,Container,<root>"; strings.Join(paths, ",") != want {
+		t.Errorf("components = %v, want %s", paths, want)
+	}
+
+	tests := []struct {
+		path     string
+		init     string
+		arity    int
+		calls    string
+		shadowed string
+		issue    string
+	}{
+		{"Parent", "Init(ctx, parent)", 2, "Init(ctx, parent)", "", ""},
+		{"Reflect", "InitReflect(ctx, parent)", 2, "InitReflect(ctx, parent)", "Init(ctx)", ""},
+		{"Starter", "", -1, "", "Start(ctx) (requires Options.RecognizeConventions)", ""},
+		{"Mistyped", "", -1, "", "", "method Init has signature func(interface {}) error"},
+		{"Full", "Init()", 0, "PreInit(ctx),Init(),PostInit(ctx),Link(ctx, parent)", "", ""},
+		{"<root>", "", -1, "", "", ""},
+	}
+	for _, tt := range tests {
+		component, ok := result.Component(tt.path)
+		if !ok {
+			t.Errorf("%s not probed", tt.path)
+			continue
+		}
+		if component.Initializer != tt.init || component.Arity != tt.arity {
+			t.Errorf("%s: initializer %q arity %d, want %q arity %d", tt.path, component.Initializer, component.Arity, tt.init, tt.arity)
+		}
+		if got := strings.Join(component.Calls, ","); got != tt.calls {
+			t.Errorf("%s: calls %s, want %s", tt.path, got, tt.calls)
+		}
+		if got := strings.Join(component.Shadowed, ","); got != tt.shadowed {
+			t.Errorf("%s: shadowed %s, want %s", tt.path, got, tt.shadowed)
+		}
+		if got := strings.Join(component.Issues, ";"); !strings.Contains(got, tt.issue) || (tt.issue == "" && got != "") {
+			t.Errorf("%s: issues %q, want %q", tt.path, got, tt.issue)
+		}
+	}
+
+	// Options change what would be called
+	result, err = Probe(app, &Options{RecognizeConventions: true, DisablePreInit: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if starter, _ := result.Component("Starter"); starter.Initializer != "Start(ctx)" || starter.Arity != 1 {
+		t.Errorf("Starter with conventions: %v", starter)
+	}
+	if full, _ := result.Component("Full"); full.Calls[0] != "Init()" {
+		t.Errorf("Full with DisablePreInit: %v", full)
+	}
+	if !strings.Contains(result.String(), "Parent (*autoinit.probeParentAware): Init(ctx, parent)") {
+		t.Errorf("unexpected String():\n%s", result)
+	}
+}
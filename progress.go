@@ -0,0 +1,70 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// progressTracker counts Init calls for Options.OnProgress
+type progressTracker struct {
+	mu       sync.Mutex
+	done     int
+	total    int
+	callback func(done, total int)
+}
+
+type progressKeyType struct{}
+
+var progressKey progressKeyType
+
+// withProgress installs a tracker on ctx if options.OnProgress is set. The total
+// is counted up front by walking the tree with the traversal rules of AutoInit.
+func withProgress(ctx context.Context, root reflect.Value, options *Options) context.Context {
+	if options == nil || options.OnProgress == nil {
+		if ctx.Value(progressKey) != nil {
+			// A nested run must not report into an enclosing run's progress
+			return context.WithValue(ctx, progressKey, (*progressTracker)(nil))
+		}
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey, &progressTracker{
+		total:    countInitializers(root, options),
+		callback: options.OnProgress,
+	})
+}
+
+// countInitializers returns the number of Init calls AutoInit makes for the tree
+// rooted at root, counting a component once per reinit reference. A tree that
+// fails to walk (e.g. an invalid tag) is counted up to the failure; AutoInit
+// reports the error itself.
+func countInitializers(root reflect.Value, options *Options) int {
+	conventions := options != nil && options.RecognizeConventions
+	total := 0
+	_ = walkInitCalls(root, options, func(node componentNode) error {
+		if selectsGroup(options, node.group) && resolveInitializer(node.value, node.parent, conventions) != nil {
+			total++
+		}
+		return nil
+	})
+	return total
+}
+
+// advanceProgress records a completed Init call and reports it
+func advanceProgress(ctx context.Context) {
+	tracker, _ := ctx.Value(progressKey).(*progressTracker)
+	if tracker == nil {
+		return
+	}
+	// Holding the locks while reporting keeps the counts in order
+	_, unlock := lockCallbacks(ctx)
+	defer unlock()
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.done++
+	if tracker.done > tracker.total {
+		// Components added during the run, e.g. by a PreFieldInit hook
+		tracker.total = tracker.done
+	}
+	tracker.callback(tracker.done, tracker.total)
+}
@@ -0,0 +1,72 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestOnProgress(t *testing.T) {
+	type App struct {
+		Config   *SimpleComponent
+		Services []Service
+		Plugins  map[string]SimpleComponent
+		Missing  *SimpleComponent
+		Excluded *SimpleComponent       `autoinit:"-"`
+		Plain    *struct{ Name string } // no Init, not counted
+	}
+	app := &App{
+		Config: &SimpleComponent{},
+		Services: []Service{
+			{Name: "a", Database: &Database{}},
+			{Name: "b", Database: &Database{}},
+		},
+		Plugins:  map[string]SimpleComponent{"x": {}},
+		Excluded: &SimpleComponent{},
+		Plain:    &struct{ Name string }{},
+	}
+
+	var progress []string
+	logger := zerolog.Nop()
+	err := WithOptions(context.Background(), app, &Options{
+		Logger: &logger,
+		OnProgress: func(done, total int) {
+			progress = append(progress, fmt.Sprintf("%d/%d", done, total))
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Config, 2 databases, 2 services and the plugin; App has no Init
+	expected := []string{"1/6", "2/6", "3/6", "4/6", "5/6", "6/6"}
+	if !reflect.DeepEqual(progress, expected) {
+		t.Errorf("progress = %v; want %v", progress, expected)
+	}
+}
+
+func TestOnProgressReinit(t *testing.T) {
+	shared := &reinitShared{Name: "shared"}
+	app := &reinitApp{First: shared, Second: shared, Module: reinitModule{Shared: shared}, Plain: shared}
+
+	var progress []string
+	logger := zerolog.Nop()
+	err := WithOptions(context.Background(), app, &Options{
+		Logger: &logger,
+		OnProgress: func(done, total int) {
+			progress = append(progress, fmt.Sprintf("%d/%d", done, total))
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// One Init per reinit reference; the untagged Plain reference is deduplicated
+	expected := []string{"1/3", "2/3", "3/3"}
+	if !reflect.DeepEqual(progress, expected) || shared.InitCount != 3 {
+		t.Errorf("progress = %v (InitCount %d); want %v", progress, shared.InitCount, expected)
+	}
+}
@@ -0,0 +1,71 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+)
+
+const providedKey contextKey = "autoinit:provided"
+
+// WithProvided returns a context carrying externally supplied values, such as
+// singletons created outside the component tree (a metrics registry, a shared
+// HTTP client). Components retrieve them with Provided, or with AsOrProvided to
+// look in the tree first:
+//
+//	ctx = autoinit.WithProvided(ctx, metricsRegistry, httpClient)
+//
+// Values added by a later call take precedence over earlier ones of the same type.
+func WithProvided(ctx context.Context, values ...interface{}) context.Context {
+	existing, _ := ctx.Value(providedKey).([]interface{})
+
+	// Copy so contexts derived earlier are not affected
+	provided := make([]interface{}, len(existing), len(existing)+len(values))
+	copy(provided, existing)
+	provided = append(provided, values...)
+
+	return context.WithValue(ctx, providedKey, provided)
+}
+
+// Provided returns the most recently provided value assignable to T, e.g. a
+// concrete pointer type or an interface it implements. Nil values never match.
+func Provided[T any](ctx context.Context) (T, bool) {
+	var zero T
+	if ctx == nil {
+		return zero, false
+	}
+
+	provided, _ := ctx.Value(providedKey).([]interface{})
+	for i := len(provided) - 1; i >= 0; i-- {
+		v := reflect.ValueOf(provided[i])
+		if !v.IsValid() || isNilValue(v) {
+			continue
+		}
+		if value, ok := provided[i].(T); ok {
+			return value, true
+		}
+	}
+	return zero, false
+}
+
+// AsOrProvided is like As but falls back to values supplied with WithProvided
+// when no component in the tree matches, so a component can use one call for
+// both tree components and external singletons. The tree always takes
+// precedence. Provided values are not fields and have no name or tags, so
+// they cannot satisfy filters: a lookup with filters other than MatchEmbedded
+// searches the tree only.
+func AsOrProvided[T any](ctx context.Context, self, parent interface{}, target *T, filters ...Filter) bool {
+	if target == nil {
+		return false
+	}
+	if As(ctx, self, parent, target, filters...) {
+		return true
+	}
+	if rest, _ := splitMarkers(filters); len(rest) > 0 {
+		return false
+	}
+	if value, ok := Provided[T](ctx); ok {
+		*target = value
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// providedConsumer discovers its database and stringer in the tree or among provided values
+type providedConsumer struct {
+	DB       *Database
+	Stringer fmt.Stringer
+}
+
+func (c *providedConsumer) Init(ctx context.Context, parent interface{}) error {
+	AsOrProvided(ctx, c, parent, &c.DB)
+	AsOrProvided(ctx, c, parent, &c.Stringer)
+	return nil
+}
+
+// providedNamed looks for a database by field name
+type providedNamed struct {
+	DB *Database
+}
+
+func (n *providedNamed) Init(ctx context.Context, parent interface{}) error {
+	AsOrProvided(ctx, n, parent, &n.DB, WithFieldName("Primary"))
+	return nil
+}
+
+type providedName string
+
+func (n providedName) String() string { return string(n) }
+
+func TestAsOrProvided(t *testing.T) {
+	type App struct {
+		Database *Database
+		Consumer *providedConsumer
+	}
+
+	external := &Database{}
+	ctx := WithProvided(context.Background(), external, providedName("first"))
+	ctx = WithProvided(ctx, providedName("second"), (*Database)(nil))
+
+	// The tree takes precedence over provided values
+	app := &App{Database: &Database{}, Consumer: &providedConsumer{}}
+	if err := AutoInit(ctx, app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Consumer.DB != app.Database {
+		t.Error("expected the tree database")
+	}
+	// Later values win; only the provided stringer exists
+	if app.Consumer.Stringer == nil || app.Consumer.Stringer.String() != "second" {
+		t.Errorf("stringer = %v; want the later provided value", app.Consumer.Stringer)
+	}
+
+	// Without a tree match the provided value is used; the nil one is ignored
+	lone := &App{Consumer: &providedConsumer{}}
+	if err := AutoInit(ctx, lone); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lone.Consumer.DB != external {
+		t.Error("expected the provided database")
+	}
+
+	// Provided values have no field name, so a filtered lookup does not fall back
+	type Named struct {
+		Database *Database
+		Named    *providedNamed
+	}
+	named := &Named{Database: &Database{}, Named: &providedNamed{}}
+	if err := AutoInit(ctx, named); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if named.Named.DB != nil {
+		t.Errorf("DB = %p; want no match for a filter on Primary", named.Named.DB)
+	}
+
+	if _, ok := Provided[*Database](context.Background()); ok {
+		t.Error("expected nothing provided on a plain context")
+	}
+}
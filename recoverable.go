@@ -0,0 +1,126 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const recoveryPolicyKey contextKey = "autoinit:recoveryPolicy"
+
+// Recoverable wraps a component to make its Init panic-safe and retryable,
+// without global options. Use it as a field type:
+//
+//	type App struct {
+//	    Cache autoinit.Recoverable[*Cache]
+//	}
+//
+//	app := &App{Cache: autoinit.NewRecoverable(&Cache{}, 3, time.Second)}
+//	err := autoinit.AutoInit(ctx, app)
+//	cache := app.Cache.Get()
+//
+// AutoInit treats the wrapper as transparent: the inner component is
+// initialized in its place, at the field's path and with the field's parent,
+// and tree walks such as AutoShutdown reach it too. Only the inner component's
+// own initializer is guarded, not its children or hooks: a panic becomes a
+// *PanicError, and a failure is retried up to the configured number of
+// attempts, waiting backoff between them, before the last error is returned.
+// Each attempt gets its own timeout if one applies. ReInit does not retry.
+//
+// As and the finder see the wrapper, not the inner component; use Get.
+type Recoverable[T any] struct {
+	inner    T
+	attempts int
+	backoff  time.Duration
+}
+
+// NewRecoverable wraps inner, allowing up to attempts calls of its initializer
+// (at least one) with backoff between them
+func NewRecoverable[T any](inner T, attempts int, backoff time.Duration) Recoverable[T] {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return Recoverable[T]{inner: inner, attempts: attempts, backoff: backoff}
+}
+
+// Get returns the wrapped component
+func (r Recoverable[T]) Get() T {
+	return r.inner
+}
+
+// recoverableField is implemented by *Recoverable[T] for any T
+type recoverableField interface {
+	recoverable() (inner reflect.Value, policy recoveryPolicy)
+}
+
+// recoveryPolicy configures the retries of a Recoverable component
+type recoveryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+func (r *Recoverable[T]) recoverable() (reflect.Value, recoveryPolicy) {
+	// Reached through a pointer, so the inner value is addressable and settable
+	return reflect.ValueOf(&r.inner).Elem(), recoveryPolicy{attempts: r.attempts, backoff: r.backoff}
+}
+
+// unwrapRecoverable returns the component wrapped by a Recoverable field, or
+// the field itself with ok false
+func unwrapRecoverable(field reflect.Value) (inner reflect.Value, policy recoveryPolicy, ok bool) {
+	if field.Kind() != reflect.Struct || !field.CanAddr() || !field.Addr().CanInterface() {
+		return field, recoveryPolicy{}, false
+	}
+	wrapper, ok := field.Addr().Interface().(recoverableField)
+	if !ok {
+		return field, recoveryPolicy{}, false
+	}
+	inner, policy = wrapper.recoverable()
+	return inner, policy, true
+}
+
+// withRecoveryPolicy records the recovery policy of the field being
+// initialized. Like timeouts it is not inherited: every field sets its own.
+func withRecoveryPolicy(ctx context.Context, policy recoveryPolicy) context.Context {
+	if current, _ := ctx.Value(recoveryPolicyKey).(recoveryPolicy); current == policy {
+		return ctx
+	}
+	return context.WithValue(ctx, recoveryPolicyKey, policy)
+}
+
+// invokeRecoverable calls attempt once, or, for a Recoverable component,
+// with panic recovery and retries
+func invokeRecoverable(ctx context.Context, path []string, logger *zerolog.Logger, attempt func() error) error {
+	policy, _ := ctx.Value(recoveryPolicyKey).(recoveryPolicy)
+	if policy.attempts == 0 {
+		return attempt()
+	}
+
+	attempts := policy.attempts
+	var err error
+	for i := 1; ; i++ {
+		err = func() (err error) {
+			defer recoverInto(&err)
+			return attempt()
+		}()
+		if err == nil || i == attempts {
+			break
+		}
+		logger.Warn().
+			Str("path", pathToString(path)).
+			Int("attempt", i).
+			Err(err).
+			Msg("Recoverable initializer failed, retrying")
+		select {
+		case <-time.After(policy.backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("after %d attempts: %w", i, ctx.Err())
+		}
+	}
+	if err != nil && attempts > 1 {
+		return fmt.Errorf("after %d attempts: %w", attempts, err)
+	}
+	return err
+}
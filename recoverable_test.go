@@ -0,0 +1,83 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// flakyComponent panics until its Init has been called failUntil times
+type flakyComponent struct {
+	FailUntil int
+	Calls     int
+	Parent    interface{}
+	Child     *SimpleComponent
+	ShutDown  bool
+}
+
+func (f *flakyComponent) Init(ctx context.Context, parent interface{}) error {
+	f.Calls++
+	if f.Calls < f.FailUntil {
+		panic("not ready yet")
+	}
+	f.Parent = parent
+	return nil
+}
+
+func (f *flakyComponent) Shutdown(ctx context.Context) error {
+	f.ShutDown = true
+	return nil
+}
+
+func TestRecoverable(t *testing.T) {
+	type App struct {
+		Flaky Recoverable[*flakyComponent]
+		Plain *SimpleComponent
+	}
+
+	logger := zerolog.Nop()
+	options := &Options{Logger: &logger}
+	app := &App{
+		Flaky: NewRecoverable(&flakyComponent{FailUntil: 3, Child: &SimpleComponent{}}, 3, time.Millisecond),
+		Plain: &SimpleComponent{},
+	}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flaky := app.Flaky.Get()
+	if flaky.Calls != 3 {
+		t.Errorf("Init called %d times; want 3", flaky.Calls)
+	}
+	// The wrapper is transparent: the inner component's parent is App, and
+	// its children are initialized once
+	if flaky.Parent != app {
+		t.Errorf("parent = %v; want the App", flaky.Parent)
+	}
+	if !flaky.Child.Initialized || !app.Plain.Initialized {
+		t.Error("children and siblings should be initialized")
+	}
+
+	// Tree walks reach the inner component
+	if err := AutoShutdown(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if !flaky.ShutDown {
+		t.Error("Shutdown should reach the wrapped component")
+	}
+
+	// Once attempts run out the last error is returned
+	app = &App{Flaky: NewRecoverable(&flakyComponent{FailUntil: 10}, 2, 0)}
+	err := WithOptions(context.Background(), app, options)
+	var initErr *InitError
+	var panicErr *PanicError
+	if !errors.As(err, &initErr) || pathToString(initErr.Path) != "Flaky" || !errors.As(err, &panicErr) {
+		t.Errorf("expected a PanicError for Flaky, got %v", err)
+	}
+	if app.Flaky.Get().Calls != 2 {
+		t.Errorf("Init called %d times; want 2", app.Flaky.Get().Calls)
+	}
+}
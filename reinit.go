@@ -0,0 +1,219 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/rs/zerolog"
+)
+
+// ReInit re-runs the lifecycle of the changed components and of every component
+// that depends on them, e.g. after a configuration reload.
+// Components are identified by pointer: each entry of changed must point to a
+// struct reachable from target under the traversal rules of AutoInit.
+//
+// A component is re-initialized if it is in changed or if it depends on a
+// component that is re-initialized. Dependents are determined as follows:
+//   - Ancestors: a struct initializes after its fields and may have consumed
+//     them, so every enclosing component of a re-initialized one is included.
+//   - Discovered dependencies: a component that found another one with As (or
+//     AsInterface, AsType, AsTypeFiltered) during the initial run depends on it.
+//     These are only known if the initial run was given an Options.Report; pass
+//     the same options (and report) to ReInit to use them.
+//
+// The rules are applied transitively. Selected components are re-initialized in
+// their original initialization order, with the parent chain set to their real
+// ancestors, by the same steps as in AutoInit: type hooks, PreInit, Init,
+// PostInit and the ConfigValidator check. Once all of them are done, those that
+// implement Linker are linked again. Fields are not traversed again, so
+// components outside the selection keep their state, and inject fields keep the
+// components assigned in the initial run. Errors stop at the first failure
+// unless ContinueOnError is set, in which case Link is skipped for failed
+// components and everything below them.
+func ReInit(ctx context.Context, target interface{}, changed []interface{}, options *Options) error {
+	ctx = withContextValues(ctx, options)
+	logger := optionsLogger(options)
+	ctx, logger = withRunTraceID(ctx, options, logger)
+	ctx = withRunLogger(ctx, logger)
+
+	logger.WithLevel(stepLogLevel(options)).
+		Str("target_type", fmt.Sprintf("%T", target)).
+		Int("changed", len(changed)).
+		Msg("Starting ReInit")
+
+	v, err := resolveTarget(target, "re-initialize")
+	if err != nil {
+		return err
+	}
+
+	var nodes []componentNode
+	if err := walkTree(v, options, func(node componentNode) error {
+		nodes = append(nodes, node)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Index the tree by identity and by path
+	keys := make([]componentKey, len(nodes))
+	byKey := make(map[componentKey]int, len(nodes))
+	for i, node := range nodes {
+		if key, ok := keyOf(node.value); ok {
+			keys[i] = key
+			byKey[key] = i
+		}
+	}
+	byPath := indexByPath(nodes)
+
+	selected := make([]bool, len(nodes))
+	var queue []int
+	selectNode := func(i int) {
+		if !selected[i] {
+			selected[i] = true
+			queue = append(queue, i)
+		}
+	}
+
+	for _, component := range changed {
+		key, ok := keyOf(reflect.ValueOf(component))
+		if !ok {
+			return fmt.Errorf("cannot re-initialize %T: changed components must be non-nil pointers", component)
+		}
+		i, ok := byKey[key]
+		if !ok {
+			return fmt.Errorf("cannot re-initialize %T: component is not part of the tree", component)
+		}
+		selectNode(i)
+	}
+
+	var dependents map[componentKey][]componentKey
+	if options != nil && options.Report != nil {
+		dependents = options.Report.dependents()
+	}
+
+	// Expand the selection to ancestors and discovered dependents
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+
+		if parent, ok := parentNode(nodes[i].path, byPath); ok {
+			selectNode(parent)
+		}
+		for _, dependent := range dependents[keys[i]] {
+			if j, ok := byKey[dependent]; ok {
+				selectNode(j)
+			}
+		}
+	}
+
+	// Re-run the lifecycle in a fresh run
+	ctx, state := withRunState(context.WithValue(ctx, runStateKey, (*runState)(nil)))
+
+	var errs []error
+	for i, node := range nodes {
+		if !selected[i] || !selectsGroup(options, node.group) {
+			continue
+		}
+
+		timeout, err := node.tag.timeout()
+		if err != nil {
+			return err
+		}
+		nodeCtx := withFieldTimeout(WithParentChain(ctx, ancestorChain(nodes, i, byPath)...), timeout)
+		if err := reinitNode(nodeCtx, node, &logger, options); err != nil {
+			if options == nil || !options.ContinueOnError {
+				return err
+			}
+			state.markFailed(node.value)
+			errs = append(errs, err)
+		}
+	}
+
+	// Link the re-initialized components once all of them are done
+	for i, node := range nodes {
+		if !selected[i] || !selectsGroup(options, node.group) || failedWithin(nodes, i, byPath, state) {
+			continue
+		}
+		linker, ok := nodeInterface(node.value).(Linker)
+		if !ok || !state.markLinked(node.value) {
+			continue
+		}
+		nodeCtx := WithParentChain(ctx, ancestorChain(nodes, i, byPath)...)
+		if err := callLink(nodeCtx, node, linker, &logger, options); err != nil {
+			if options == nil || !options.ContinueOnError {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	if options != nil && options.Report != nil {
+		options.Report.addDependencies(state.dependencySnapshot())
+	}
+
+	err = errors.Join(errs...)
+	if err != nil {
+		logger.WithLevel(errorLogLevel(options)).
+			Err(err).
+			Msg("ReInit failed")
+	} else {
+		logger.WithLevel(stepLogLevel(options)).
+			Msg("ReInit completed successfully")
+	}
+	return err
+}
+
+// reinitNode runs the lifecycle of one component without visiting its fields
+func reinitNode(ctx context.Context, node componentNode, logger *zerolog.Logger, options *Options) error {
+	config, err := beginLifecycle(ctx, node.value, node.path, node.index, logger, options)
+	if err != nil {
+		return err
+	}
+	return endLifecycle(ctx, node.value, node.parent, node.path, node.index, logger, options, config)
+}
+
+// indexByPath maps the dot-separated path of every node to its index
+func indexByPath(nodes []componentNode) map[string]int {
+	byPath := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		byPath[pathToString(node.path)] = i
+	}
+	return byPath
+}
+
+// parentNode returns the index of the closest enclosing node of path
+func parentNode(path []string, byPath map[string]int) (int, bool) {
+	if len(path) == 0 {
+		return 0, false
+	}
+	for n := len(path) - 1; n >= 0; n-- {
+		if i, ok := byPath[pathToString(path[:n])]; ok {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// ancestorChain returns the enclosing components of node i followed by the node
+// itself, from the root to the innermost, as AutoInit would have pushed them
+func ancestorChain(nodes []componentNode, i int, byPath map[string]int) []interface{} {
+	var chain []interface{}
+	for {
+		chain = append([]interface{}{nodeInterface(nodes[i].value)}, chain...)
+		parent, ok := parentNode(nodes[i].path, byPath)
+		if !ok {
+			return chain
+		}
+		i = parent
+	}
+}
+
+// nodeInterface returns a pointer to v if it is addressable, v itself otherwise
+func nodeInterface(v reflect.Value) interface{} {
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	return v.Interface()
+}
@@ -0,0 +1,85 @@
+package autoinit
+
+import (
+	"context"
+	"testing"
+)
+
+type reinitShared struct {
+	Name      string
+	InitCount int
+}
+
+func (s *reinitShared) Init() error {
+	s.InitCount++
+	return nil
+}
+
+type reinitModule struct {
+	Shared *reinitShared `autoinit:"reinit"`
+}
+
+type reinitApp struct {
+	First  *reinitShared `autoinit:"reinit"`
+	Second *reinitShared `autoinit:"reinit"`
+	Module reinitModule
+	Plain  *reinitShared
+}
+
+// reinitLoop refers to itself through a reinit field
+type reinitLoop struct {
+	Next      *reinitLoop `autoinit:"reinit"`
+	InitCount int
+}
+
+func (l *reinitLoop) Init() error {
+	l.InitCount++
+	return nil
+}
+
+func TestReinitTag(t *testing.T) {
+	t.Run("shared pointer is initialized per reference", func(t *testing.T) {
+		shared := &reinitShared{Name: "shared"}
+		app := &reinitApp{First: shared, Second: shared, Module: reinitModule{Shared: shared}}
+		if err := AutoInit(context.Background(), app); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shared.InitCount != 3 {
+			t.Errorf("InitCount = %d, want 3 (one per reinit reference)", shared.InitCount)
+		}
+	})
+
+	t.Run("untagged references are still deduplicated", func(t *testing.T) {
+		shared := &reinitShared{Name: "shared"}
+		app := &reinitApp{First: shared, Plain: shared}
+		if err := AutoInit(context.Background(), app); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shared.InitCount != 1 {
+			t.Errorf("InitCount = %d, want 1", shared.InitCount)
+		}
+	})
+
+	t.Run("a reference to an enclosing component is a cycle", func(t *testing.T) {
+		type holder struct {
+			Loop *reinitLoop
+		}
+		loop := &reinitLoop{}
+		loop.Next = loop
+		if err := AutoInit(context.Background(), &holder{Loop: loop}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if loop.InitCount != 1 {
+			t.Errorf("InitCount = %d, want 1", loop.InitCount)
+		}
+
+		first, second := &reinitLoop{}, &reinitLoop{}
+		first.Next, second.Next = second, first
+		if err := AutoInit(context.Background(), &holder{Loop: first}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if first.InitCount != 1 || second.InitCount != 1 {
+			t.Errorf("InitCounts = %d, %d, want 1, 1", first.InitCount, second.InitCount)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package autoinit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type reloadConfig struct {
+	URL   string
+	calls *[]string
+}
+
+func (c *reloadConfig) Init() error {
+	*c.calls = append(*c.calls, "config")
+	return nil
+}
+
+// reloadClient discovers the config through As
+type reloadClient struct {
+	URL   string
+	calls *[]string
+}
+
+func (c *reloadClient) Init(ctx context.Context, parent interface{}) error {
+	*c.calls = append(*c.calls, "client")
+	var cfg *reloadConfig
+	if As(ctx, c, parent, &cfg) {
+		c.URL = cfg.URL
+	}
+	return nil
+}
+
+type reloadCache struct {
+	calls *[]string
+}
+
+func (c *reloadCache) Init() error {
+	*c.calls = append(*c.calls, "cache")
+	return nil
+}
+
+type reloadModule struct {
+	Cache *reloadCache
+	calls *[]string
+}
+
+func (m *reloadModule) Init() error {
+	*m.calls = append(*m.calls, "module")
+	return nil
+}
+
+type reloadApp struct {
+	Config *reloadConfig
+	Module *reloadModule
+	Client *reloadClient
+	calls  *[]string
+}
+
+func (a *reloadApp) Init() error {
+	*a.calls = append(*a.calls, "app")
+	return nil
+}
+
+func TestReInit(t *testing.T) {
+	var calls []string
+	app := &reloadApp{
+		Config: &reloadConfig{URL: "db://old", calls: &calls},
+		Module: &reloadModule{Cache: &reloadCache{calls: &calls}, calls: &calls},
+		Client: &reloadClient{calls: &calls},
+		calls:  &calls,
+	}
+
+	logger := zerolog.Nop()
+	options := &Options{Logger: &logger, Report: &InitReport{}}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Reloading the config re-initializes the client that found it and the
+	// enclosing app, but not the unrelated module
+	calls = nil
+	app.Config.URL = "db://new"
+	if err := ReInit(context.Background(), app, []interface{}{app.Config}, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"config", "client", "app"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v; want %v", calls, expected)
+	}
+	if app.Client.URL != "db://new" {
+		t.Errorf("client URL = %q; want the reloaded value", app.Client.URL)
+	}
+
+	// Without the report only ancestors are known
+	calls = nil
+	if err := ReInit(context.Background(), app, []interface{}{app.Config}, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"config", "app"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v; want %v", calls, expected)
+	}
+
+	// Nested components take their ancestors along
+	calls = nil
+	if err := ReInit(context.Background(), app, []interface{}{app.Module.Cache}, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"cache", "module", "app"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v; want %v", calls, expected)
+	}
+
+	if err := ReInit(context.Background(), app, []interface{}{&reloadCache{calls: &calls}}, options); err == nil {
+		t.Error("expected an error for a component outside the tree")
+	}
+}
+
+// reloadLinked records its lifecycle calls and validates its configuration
+type reloadLinked struct {
+	Config struct{ Port int }
+	drift  bool
+	calls  *[]string
+}
+
+func (l *reloadLinked) ValidatedConfig() (interface{}, func(interface{}) ([]byte, error)) {
+	return &l.Config, json.Marshal
+}
+
+func (l *reloadLinked) Init() error {
+	*l.calls = append(*l.calls, "init")
+	if l.drift {
+		l.Config.Port++
+	}
+	return nil
+}
+
+func (l *reloadLinked) Link(ctx context.Context, parent interface{}) error {
+	*l.calls = append(*l.calls, "link")
+	return nil
+}
+
+func TestReInitLifecycle(t *testing.T) {
+	type App struct {
+		Linked *reloadLinked
+	}
+	var calls []string
+	app := &App{Linked: &reloadLinked{calls: &calls}}
+	hook := TypeHook{
+		Before: func(ctx context.Context, component interface{}) error {
+			calls = append(calls, "before")
+			return nil
+		},
+		After: func(ctx context.Context, component interface{}) error {
+			calls = append(calls, "after")
+			return nil
+		},
+	}
+	logger := zerolog.Nop()
+	options := &Options{Logger: &logger, TypeHooks: map[reflect.Type]TypeHook{reflect.TypeOf(&reloadLinked{}): hook}}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// ReInit runs the same steps as the initial run
+	want := append([]string(nil), calls...)
+	calls = nil
+	if err := ReInit(context.Background(), app, []interface{}{app.Linked}, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("ReInit calls = %v; want %v as in AutoInit", calls, want)
+	}
+	if expected := []string{"before", "init", "after", "link"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v; want %v", calls, expected)
+	}
+
+	// A configuration changed during re-initialization fails the component,
+	// which is then not linked
+	calls = nil
+	app.Linked.drift = true
+	err := ReInit(context.Background(), app, []interface{}{app.Linked}, options)
+	var drift *ConfigDriftError
+	if !errors.As(err, &drift) {
+		t.Fatalf("expected a ConfigDriftError, got %v", err)
+	}
+	if expected := []string{"before", "init"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("calls = %v; want %v", calls, expected)
+	}
+}
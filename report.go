@@ -0,0 +1,526 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SkipReason explains why AutoInit did not initialize a field
+type SkipReason int
+
+const (
+	// SkipNilPointer means the field (or collection element) was a nil pointer or nil interface
+	SkipNilPointer SkipReason = iota + 1
+	// SkipUnexported means the field is unexported and cannot be accessed via reflection
+	SkipUnexported
+	// SkipTag means the field is tagged `autoinit:"-"`
+	SkipTag
+	// SkipMissingTag means RequireTags is enabled and the field has no autoinit tag
+	SkipMissingTag
+	// SkipLeafType means the field holds a plain value type such as time.Time (see Options.LeafTypes)
+	SkipLeafType
+	// SkipWeak means the field is tagged `autoinit:"weak"`, a navigation-only back-reference
+	SkipWeak
+	// SkipFeatureDisabled means the field is tagged `autoinit:"feature=name"` and
+	// Options.FeatureEnabled does not enable the feature
+	SkipFeatureDisabled
+	// SkipInjected means the field is tagged `autoinit:"inject"`: it refers to
+	// a component initialized elsewhere and is assigned after all Inits
+	SkipInjected
+)
+
+// String returns a short name for the reason
+func (r SkipReason) String() string {
+	switch r {
+	case SkipNilPointer:
+		return "nil pointer"
+	case SkipUnexported:
+		return "unexported"
+	case SkipTag:
+		return "autoinit:\"-\" tag"
+	case SkipMissingTag:
+		return "missing autoinit tag (RequireTags)"
+	case SkipLeafType:
+		return "leaf type"
+	case SkipWeak:
+		return "autoinit:\"weak\" tag"
+	case SkipFeatureDisabled:
+		return "feature disabled"
+	case SkipInjected:
+		return "autoinit:\"inject\" tag"
+	default:
+		return "unknown"
+	}
+}
+
+// logMessage returns the trace log message for a skip with this reason
+func (r SkipReason) logMessage() string {
+	switch r {
+	case SkipNilPointer:
+		return "Skipping nil pointer"
+	case SkipUnexported:
+		return "Skipping unexported field"
+	case SkipTag:
+		return "Skipping field with autoinit:\"-\" tag"
+	case SkipMissingTag:
+		return "Skipping field without autoinit tag (RequireTags enabled)"
+	case SkipLeafType:
+		return "Skipping field of leaf type"
+	case SkipWeak:
+		return "Skipping weak reference field"
+	case SkipFeatureDisabled:
+		return "Skipping field of disabled feature"
+	case SkipInjected:
+		return "Skipping injected field"
+	default:
+		return "Skipping field"
+	}
+}
+
+// SkippedField records a field that AutoInit did not initialize
+type SkippedField struct {
+	Path   []string
+	Type   string // Declared type of the skipped field or element
+	Reason SkipReason
+}
+
+// PathString returns the skipped field's path in dot-separated form
+func (s SkippedField) PathString() string {
+	return pathToString(s.Path)
+}
+
+// InitReport collects diagnostics about an AutoInit run. Pass a pointer via
+// Options.Report and inspect it after the run; results of repeated runs accumulate.
+//
+//	report := &autoinit.InitReport{}
+//	err := autoinit.WithOptions(ctx, app, &autoinit.Options{Report: report})
+//	for _, s := range report.Skipped {
+//	    fmt.Printf("%s skipped: %s\n", s.PathString(), s.Reason)
+//	}
+type InitReport struct {
+	mu sync.Mutex
+	// Skipped lists fields that could hold components but were not initialized,
+	// in traversal order
+	Skipped []SkippedField
+	// Initialized lists the paths of components whose initializer (Init or,
+	// with RecognizeConventions, Start) completed successfully, in init order
+	Initialized [][]string
+	// Warnings lists the non-fatal issues found during the run, in the order
+	// they were raised, including those raised by As and the finder during
+	// Init calls
+	Warnings []Warning
+	// Dependencies lists the components each component found with As, a
+	// ComponentFinder, FindInMap or an inject field: the wiring of the tree as
+	// it was actually resolved. It is filled in when the run ends.
+	Dependencies []Dependency
+
+	// dependencies maps each component to those it discovered with As; used by ReInit
+	dependencies map[componentKey]map[componentKey]bool
+	// succeeded holds components whose lifecycle completed; used by AutoShutdown
+	succeeded map[componentKey]bool
+	// pending holds dependencies found during a run until their paths are resolved
+	pending []pendingDependency
+}
+
+// SkipReasonFor returns why the field at the given dot-separated path was
+// skipped, or false if it was not recorded as skipped
+func (r *InitReport) SkipReasonFor(path string) (SkipReason, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.Skipped {
+		if s.PathString() == path {
+			return s.Reason, true
+		}
+	}
+	return 0, false
+}
+
+// WasInitialized reports whether the component at the given dot-separated path
+// completed its initializer. Use "<root>" for the target itself.
+func (r *InitReport) WasInitialized(path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.Initialized {
+		if pathToString(p) == path {
+			return true
+		}
+	}
+	return false
+}
+
+// InitPlan returns the paths of the initialized components in the order their
+// initializers completed, for use as Options.InitPlan of later runs. Recording
+// it from a dry run and checking it into config makes the startup order
+// explicit and reviewable.
+func (r *InitReport) InitPlan() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	plan := make([]string, 0, len(r.Initialized))
+	for _, path := range r.Initialized {
+		plan = append(plan, pathToString(path))
+	}
+	return plan
+}
+
+// TestingT is the subset of testing.TB used by the report's assertion helpers
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertInitialized reports a test error for every path that was not
+// initialized, so tests need not track an Initialized flag on each component:
+//
+//	report.AssertInitialized(t, "Database", "Database.Pool")
+func (r *InitReport) AssertInitialized(t TestingT, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		if !r.WasInitialized(path) {
+			if reason, ok := r.SkipReasonFor(path); ok {
+				t.Errorf("autoinit: %s was not initialized (skipped: %s)", path, reason)
+			} else {
+				t.Errorf("autoinit: %s was not initialized", path)
+			}
+		}
+	}
+}
+
+// AssertNotInitialized reports a test error for every path that was initialized
+func (r *InitReport) AssertNotInitialized(t TestingT, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		if r.WasInitialized(path) {
+			t.Errorf("autoinit: %s was initialized", path)
+		}
+	}
+}
+
+// UninitializedError is returned by AssertAllInitialized for components that
+// were not initialized
+type UninitializedError struct {
+	Paths []string // Dot-separated paths of the components, children first
+}
+
+// Error implements the error interface
+func (e *UninitializedError) Error() string {
+	return fmt.Sprintf("autoinit: %d component(s) not initialized: %s", len(e.Paths), strings.Join(e.Paths, ", "))
+}
+
+// AssertAllInitialized walks the tree rooted at target after a run recorded in
+// r and returns an *UninitializedError listing every component with an Init
+// method that completed no Init, although the run did not record it (or a
+// field above it) as skipped. It is a safety net for conditional setups: it
+// catches components below types excluded by AllowDescend, outside the group
+// of InitGroup, whose Init failed under ContinueOnError or that were added to
+// the tree after the run. It returns nil if there are none.
+//
+//	if err := report.AssertAllInitialized(app); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// The tree is walked with the default traversal rules, so components below
+// fields tagged `autoinit:"-"` are not considered.
+func (r *InitReport) AssertAllInitialized(target interface{}) error {
+	v, err := resolveTarget(target, "check")
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	initialized := make(map[string]bool, len(r.Initialized))
+	for _, path := range r.Initialized {
+		initialized[pathToString(path)] = true
+	}
+	skipped := make([]string, 0, len(r.Skipped))
+	for _, s := range r.Skipped {
+		skipped = append(skipped, s.PathString())
+	}
+	r.mu.Unlock()
+
+	var missing []string
+	if err := walkTree(v, nil, func(node componentNode) error {
+		path := pathToString(node.path)
+		if initialized[path] || withinSkipped(path, skipped) {
+			return nil
+		}
+		if resolveInitializer(node.value, node.parent, false) != nil {
+			missing = append(missing, path)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return &UninitializedError{Paths: missing}
+	}
+	return nil
+}
+
+// withinSkipped reports whether path is one of the skipped paths or below one
+func withinSkipped(path string, skipped []string) bool {
+	for _, s := range skipped {
+		if path == s || strings.HasPrefix(path, s+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// recordInitialized notes that the component at path completed its initializer
+func (r *InitReport) recordInitialized(path []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Initialized = append(r.Initialized, append([]string(nil), path...))
+}
+
+// recordOutcome notes whether the lifecycle of the component at v completed.
+// A later failure, e.g. in ReInit, withdraws an earlier success.
+func (r *InitReport) recordOutcome(key componentKey, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.succeeded == nil {
+		r.succeeded = make(map[componentKey]bool)
+	}
+	if ok {
+		r.succeeded[key] = true
+	} else {
+		delete(r.succeeded, key)
+	}
+}
+
+// shutdownAllowed reports whether AutoShutdown may tear down the component
+// identified by key: true if it completed its lifecycle, or if the report has
+// no outcomes
+func (r *InitReport) shutdownAllowed(key componentKey) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.succeeded == nil || r.succeeded[key]
+}
+
+// merge adds the results recorded in other to r
+func (r *InitReport) merge(other *InitReport) {
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	r.mu.Lock()
+	r.Skipped = append(r.Skipped, other.Skipped...)
+	r.Initialized = append(r.Initialized, other.Initialized...)
+	r.Warnings = append(r.Warnings, other.Warnings...)
+	r.Dependencies = append(r.Dependencies, other.Dependencies...)
+	if r.succeeded == nil && other.succeeded != nil {
+		r.succeeded = make(map[componentKey]bool)
+	}
+	for key := range other.succeeded {
+		r.succeeded[key] = true
+	}
+	r.mu.Unlock()
+	r.addDependencies(other.dependencies)
+}
+
+// addDependencies merges dependencies recorded during a run into the report
+func (r *InitReport) addDependencies(deps map[componentKey]map[componentKey]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.dependencies == nil {
+		r.dependencies = make(map[componentKey]map[componentKey]bool)
+	}
+	for from, tos := range deps {
+		if r.dependencies[from] == nil {
+			r.dependencies[from] = make(map[componentKey]bool)
+		}
+		for to := range tos {
+			r.dependencies[from][to] = true
+		}
+	}
+}
+
+// dependents returns, for every recorded dependency, the components that depend on it
+func (r *InitReport) dependents() map[componentKey][]componentKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make(map[componentKey][]componentKey)
+	for from, tos := range r.dependencies {
+		for to := range tos {
+			result[to] = append(result[to], from)
+		}
+	}
+	return result
+}
+
+const reportSinkKey contextKey = "autoinit:reportSink"
+
+// reportSink is where a run records what its components do on a context: the
+// report of the run, and the path of the component whose Init is in progress,
+// for warnings raised by As and the finder during it
+type reportSink struct {
+	report *InitReport
+	path   []string
+}
+
+// withReportSink returns ctx recording in report. Nested runs without a report
+// of their own record in the enclosing run's.
+func withReportSink(ctx context.Context, report *InitReport) context.Context {
+	if report == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, reportSinkKey, &reportSink{report: report})
+}
+
+// withReportPath returns ctx for the Init call of the component at path
+func withReportPath(ctx context.Context, path []string) context.Context {
+	sink := reportSinkOf(ctx)
+	if sink == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, reportSinkKey, &reportSink{report: sink.report, path: path})
+}
+
+// reportSinkOf returns the sink of the run on ctx, nil if it has no report
+func reportSinkOf(ctx context.Context) *reportSink {
+	if ctx == nil {
+		return nil
+	}
+	sink, _ := ctx.Value(reportSinkKey).(*reportSink)
+	return sink
+}
+
+// recordSkip notes that the field at path was skipped for reason
+func (r *InitReport) recordSkip(skipped SkippedField) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Skipped = append(r.Skipped, skipped)
+}
+
+// recordSkip records a skip in the report configured in options and in the
+// tree report being built, if any
+func recordSkip(ctx context.Context, options *Options, path []string, typ reflect.Type, reason SkipReason) {
+	if !recordsSkips(ctx) {
+		return
+	}
+	skipped := SkippedField{
+		Path:   append([]string(nil), path...),
+		Type:   typ.String(),
+		Reason: reason,
+	}
+	if options != nil && options.Report != nil {
+		options.Report.recordSkip(skipped)
+	}
+	if reason == SkipMissingTag {
+		recordWarning(ctx, WarningMissingTag, skipped.Path,
+			fmt.Sprintf("%s skipped: RequireTags is set and the field has no autoinit tag", skipped.Type))
+	}
+	if parent := currentNodeReport(ctx); parent != nil {
+		parent.mu.Lock()
+		defer parent.mu.Unlock()
+		parent.node.Children = append(parent.node.Children, &NodeReport{
+			Path:       skipped.Path,
+			Type:       skipped.Type,
+			Status:     NodeSkipped,
+			SkipReason: reason,
+		})
+	}
+}
+
+// NodeStatus is the outcome for one node of a NodeReport tree
+type NodeStatus string
+
+const (
+	// NodeOK means the component and everything below it initialized successfully
+	NodeOK NodeStatus = "ok"
+	// NodeSkipped means the field was not initialized; see NodeReport.SkipReason
+	NodeSkipped NodeStatus = "skipped"
+	// NodeFailed means the component, or a component below it, failed to initialize
+	NodeFailed NodeStatus = "failed"
+)
+
+// NodeReport describes the initialization outcome of one component and its
+// children, mirroring the struct tree. Skipped fields appear as leaves.
+// A failure is reported on the failing component and, with the same Err, on
+// each of its ancestors; components after the failure are never reached and
+// do not appear in the tree. With Options.ContinueOnError only the failing
+// component is marked failed and the rest of the tree is still reported.
+type NodeReport struct {
+	Path       []string
+	Type       string
+	Status     NodeStatus
+	Err        error
+	SkipReason SkipReason // Why the field was skipped, when Status is NodeSkipped
+	Children   []*NodeReport
+}
+
+// PathString returns the node's path in dot-separated form
+func (n *NodeReport) PathString() string {
+	return pathToString(n.Path)
+}
+
+// AutoInitTreeReport initializes target like WithOptions and also returns a
+// tree of per-component outcomes, suitable for rendering what succeeded,
+// failed or was skipped. The returned error is the same as WithOptions would return.
+func AutoInitTreeReport(ctx context.Context, target interface{}, options *Options) (*NodeReport, error) {
+	holder := &NodeReport{}
+	cursor := &nodeReportCursor{mu: &sync.Mutex{}, node: holder}
+	err := WithOptions(context.WithValue(ctx, nodeReportKey, cursor), target, options)
+	if len(holder.Children) == 0 {
+		// The target was rejected before traversal started
+		return nil, err
+	}
+	return holder.Children[0], err
+}
+
+const nodeReportKey contextKey = "autoinit:nodeReport"
+
+// nodeReportCursor is the node of a tree report being built that new children
+// are added to. One mutex guards the nodes of the whole tree, which nested
+// runs on several goroutines may add to.
+type nodeReportCursor struct {
+	mu   *sync.Mutex
+	node *NodeReport
+}
+
+// currentNodeReport returns the tree node that new children are added to, if any
+func currentNodeReport(ctx context.Context) *nodeReportCursor {
+	cursor, _ := ctx.Value(nodeReportKey).(*nodeReportCursor)
+	return cursor
+}
+
+// beginNodeReport adds a node for the component at path to the tree report
+// being built and returns a context in which the node collects its children.
+// It returns a nil node when no tree report is being built. A phased run
+// reaches a component once per pass and reuses its node.
+func beginNodeReport(ctx context.Context, path []string, t reflect.Type) (context.Context, *nodeReportCursor) {
+	parent := currentNodeReport(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+	if currentPhasePass(ctx) != nil {
+		for _, child := range parent.node.Children {
+			if child.Status != NodeSkipped && pathToString(child.Path) == pathToString(path) {
+				cursor := &nodeReportCursor{mu: parent.mu, node: child}
+				return context.WithValue(ctx, nodeReportKey, cursor), cursor
+			}
+		}
+	}
+	node := &NodeReport{
+		Path:   append([]string(nil), path...),
+		Type:   t.String(),
+		Status: NodeOK,
+	}
+	parent.node.Children = append(parent.node.Children, node)
+	cursor := &nodeReportCursor{mu: parent.mu, node: node}
+	return context.WithValue(ctx, nodeReportKey, cursor), cursor
+}
+
+// finish records the outcome of the node's initialization
+func (c *nodeReportCursor) finish(err error) {
+	if c == nil || err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.node.Status = NodeFailed
+	c.node.Err = err
+}
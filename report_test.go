@@ -0,0 +1,226 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type skipReasonApp struct {
+	Present  *SimpleComponent `autoinit:""`
+	Missing  *SimpleComponent `autoinit:""`
+	Excluded *SimpleComponent `autoinit:"-"`
+	Untagged *SimpleComponent
+	hidden   *SimpleComponent
+	List     []*SimpleComponent `autoinit:""`
+}
+
+func TestInitReportSkipReasons(t *testing.T) {
+	logger := zerolog.Nop()
+	report := &InitReport{}
+	app := &skipReasonApp{
+		Present:  &SimpleComponent{},
+		Excluded: &SimpleComponent{},
+		Untagged: &SimpleComponent{},
+		hidden:   &SimpleComponent{},
+		List:     []*SimpleComponent{{}, nil},
+	}
+
+	err := WithOptions(context.Background(), app, &Options{
+		Logger:      &logger,
+		RequireTags: true,
+		Report:      report,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]SkipReason{
+		"Missing":  SkipNilPointer,
+		"Excluded": SkipTag,
+		"Untagged": SkipMissingTag,
+		"hidden":   SkipUnexported,
+		"List.[1]": SkipNilPointer,
+	}
+	for path, want := range expected {
+		got, ok := report.SkipReasonFor(path)
+		if !ok {
+			t.Errorf("%s: expected skip to be recorded", path)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected reason %q, got %q", path, want, got)
+		}
+	}
+
+	if _, ok := report.SkipReasonFor("Present"); ok {
+		t.Error("initialized field should not be reported as skipped")
+	}
+	if len(report.Skipped) != len(expected) {
+		t.Errorf("expected %d skipped fields, got %d: %+v", len(expected), len(report.Skipped), report.Skipped)
+	}
+}
+
+type treeReportApp struct {
+	Config   *SimpleComponent
+	Services []Service
+	Missing  *SimpleComponent
+	Excluded *SimpleComponent `autoinit:"-"`
+}
+
+func TestAutoInitTreeReport(t *testing.T) {
+	logger := zerolog.Nop()
+	app := &treeReportApp{
+		Config: &SimpleComponent{},
+		Services: []Service{
+			{Name: "ok", Database: &Database{}},
+			{Name: "broken", Database: &Database{ShouldFail: true}},
+		},
+		Excluded: &SimpleComponent{},
+	}
+
+	root, err := AutoInitTreeReport(context.Background(), app, &Options{Logger: &logger})
+	if err == nil {
+		t.Fatal("expected the failing database to fail initialization")
+	}
+	if root == nil {
+		t.Fatal("expected a tree report")
+	}
+
+	statuses := map[string]NodeStatus{}
+	var collect func(n *NodeReport)
+	collect = func(n *NodeReport) {
+		statuses[n.PathString()] = n.Status
+		for _, child := range n.Children {
+			collect(child)
+		}
+	}
+	collect(root)
+
+	expected := map[string]NodeStatus{
+		"<root>":                NodeFailed,
+		"Config":                NodeOK,
+		"Services.[0]":          NodeOK,
+		"Services.[0].Database": NodeOK,
+		"Services.[1]":          NodeFailed,
+		"Services.[1].Database": NodeFailed,
+	}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("statuses = %v; want %v", statuses, expected)
+	}
+	if root.Type != "autoinit.treeReportApp" {
+		t.Errorf("root type = %q", root.Type)
+	}
+
+	// A successful run reports skipped fields as leaves
+	root, err = AutoInitTreeReport(context.Background(), &treeReportApp{Excluded: &SimpleComponent{}}, &Options{Logger: &logger})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if root.Status != NodeOK || len(root.Children) != 3 {
+		t.Fatalf("unexpected root report: %+v", root)
+	}
+	for _, child := range root.Children {
+		if child.Status != NodeSkipped {
+			t.Errorf("%s: status = %s; want skipped", child.PathString(), child.Status)
+		}
+	}
+	if root.Children[2].SkipReason != SkipTag || root.Children[2].Type != "*autoinit.SimpleComponent" {
+		t.Errorf("unexpected skipped node: %+v", root.Children[2])
+	}
+}
+
+// recordingT captures assertion failures
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestInitReportAssertions(t *testing.T) {
+	logger := zerolog.Nop()
+	report := &InitReport{}
+	app := &treeReportApp{
+		Config:   &SimpleComponent{},
+		Services: []Service{{Name: "ok", Database: &Database{}}},
+		Excluded: &SimpleComponent{},
+	}
+	if err := WithOptions(context.Background(), app, &Options{Logger: &logger, Report: report}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report.AssertInitialized(t, "Config", "Services.[0].Database")
+	report.AssertNotInitialized(t, "Excluded", "Missing")
+
+	rec := &recordingT{}
+	report.AssertInitialized(rec, "Excluded", "Nowhere")
+	report.AssertNotInitialized(rec, "Config")
+	expected := []string{
+		`autoinit: Excluded was not initialized (skipped: autoinit:"-" tag)`,
+		"autoinit: Nowhere was not initialized",
+		"autoinit: Config was initialized",
+	}
+	if !reflect.DeepEqual(rec.errors, expected) {
+		t.Errorf("errors = %q; want %q", rec.errors, expected)
+	}
+}
+
+// sealedModule is not descended into via AllowDescend, so its Worker is never reached
+type sealedModule struct {
+	Worker *SimpleComponent
+}
+
+func TestAssertAllInitialized(t *testing.T) {
+	type App struct {
+		Service  *SimpleComponent `autoinit:""`
+		Untagged *SimpleComponent
+		Sealed   *sealedModule `autoinit:""`
+	}
+
+	logger := zerolog.Nop()
+	report := &InitReport{}
+	app := &App{
+		Service:  &SimpleComponent{},
+		Untagged: &SimpleComponent{},
+		Sealed:   &sealedModule{Worker: &SimpleComponent{}},
+	}
+	options := &Options{
+		Logger:      &logger,
+		Report:      report,
+		RequireTags: true,
+		AllowDescend: func(t reflect.Type) bool {
+			return t != reflect.TypeOf(sealedModule{})
+		},
+	}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Untagged was skipped on purpose; the worker slipped through unnoticed
+	err := report.AssertAllInitialized(app)
+	var uninitialized *UninitializedError
+	if !errors.As(err, &uninitialized) {
+		t.Fatalf("expected an UninitializedError, got %v", err)
+	}
+	if want := []string{"Sealed.Worker"}; !reflect.DeepEqual(uninitialized.Paths, want) {
+		t.Errorf("Paths = %v, want %v", uninitialized.Paths, want)
+	}
+
+	// A complete run passes
+	report = &InitReport{}
+	app = &App{Service: &SimpleComponent{}, Sealed: &sealedModule{Worker: &SimpleComponent{}}}
+	if err := WithOptions(context.Background(), app, &Options{Logger: &logger, Report: report}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := report.AssertAllInitialized(app); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DependencyDeclarer is implemented by components that declare the
+// dependencies they look up with As, so that CheckRequirements, or
+// Options.CheckRequirements, can verify before any Init runs that the tree
+// provides them:
+//
+//	func (s *Service) Requires() []autoinit.Requirement {
+//	    return []autoinit.Requirement{
+//	        autoinit.Require[*Database](autoinit.WithFieldName("PrimaryDB")),
+//	        autoinit.Require[Logger](),
+//	    }
+//	}
+//
+// Requires may be called before the component is initialized, so it must not
+// depend on state set by Init.
+type DependencyDeclarer interface {
+	Requires() []Requirement
+}
+
+// Requirement is a dependency a component finds with As: a component of Type
+// matching all Filters, searched from the component's position in the tree
+type Requirement struct {
+	Type    reflect.Type
+	Filters []Filter
+}
+
+// Require returns the Requirement met by what As(ctx, self, parent, &t,
+// filters...) finds for a variable t of type T
+func Require[T any](filters ...Filter) Requirement {
+	return Requirement{Type: reflect.TypeOf((*T)(nil)).Elem(), Filters: filters}
+}
+
+// UnsatisfiedRequirement is a declared requirement As would not find
+type UnsatisfiedRequirement struct {
+	Path    []string // Path of the component declaring the requirement
+	Type    string   // Required type
+	Filters int      // Number of filters the requirement has
+}
+
+// String describes the requirement, e.g. "Service needs *Database (1 filter)"
+func (r UnsatisfiedRequirement) String() string {
+	s := pathToString(r.Path) + " needs " + r.Type
+	switch r.Filters {
+	case 0:
+		return s
+	case 1:
+		return s + " (1 filter)"
+	default:
+		return fmt.Sprintf("%s (%d filters)", s, r.Filters)
+	}
+}
+
+// RequirementsError lists the declared requirements the tree does not satisfy
+type RequirementsError struct {
+	Unsatisfied []UnsatisfiedRequirement
+}
+
+// Error implements the error interface
+func (e *RequirementsError) Error() string {
+	descriptions := make([]string, len(e.Unsatisfied))
+	for i, requirement := range e.Unsatisfied {
+		descriptions[i] = requirement.String()
+	}
+	return "unsatisfied requirements: " + strings.Join(descriptions, "; ")
+}
+
+// CheckRequirements resolves the requirements declared by every component of
+// target implementing DependencyDeclarer, as As would resolve them from the
+// component's position: among its parent's fields, then the roots added to ctx
+// with WithAdditionalRoots. The nil fields tagged `autoinit:"inject"` that are
+// not optional are resolved the same way, with the field name of
+// `inject=Name`. No lifecycle method is called. It returns a
+// *RequirementsError listing every requirement that cannot be met, in tree
+// order.
+//
+// Only declared requirements and inject fields are checked; As calls that
+// are not declared still fail at the component's Init. Components that build
+// their dependencies during initialization, e.g. with factories or hooks,
+// should not declare them.
+func CheckRequirements(ctx context.Context, target interface{}, options *Options) error {
+	v, err := resolveTarget(target, "check")
+	if err != nil {
+		return err
+	}
+	return checkRequirements(ctx, v, options)
+}
+
+// checkRequirements resolves the declared requirements of the tree rooted at v
+func checkRequirements(ctx context.Context, v reflect.Value, options *Options) error {
+	edges, unsatisfied, err := resolveRequirements(ctx, v, options)
+	if err != nil {
+		return err
+	}
+	warnMutualRequirements(ctx, edges)
+
+	if len(unsatisfied) > 0 {
+		return &RequirementsError{Unsatisfied: unsatisfied}
+	}
+	return nil
+}
+
+// resolveRequirements resolves the declared requirements of the tree rooted
+// at v, returning the edges to the components found for them, in tree order,
+// and the requirements nothing meets
+func resolveRequirements(ctx context.Context, v reflect.Value, options *Options) ([]requirementEdge, []UnsatisfiedRequirement, error) {
+	var edges []requirementEdge
+	var unsatisfied []UnsatisfiedRequirement
+	err := walkTree(v, options, func(node componentNode) error {
+		self := nodeInterface(node.value)
+		var parent interface{}
+		if node.parent.IsValid() {
+			parent = nodeInterface(node.parent)
+		}
+		unsatisfied = append(unsatisfied, unresolvedInjections(ctx, node, self, parent)...)
+
+		declarer, ok := self.(DependencyDeclarer)
+		if !ok {
+			return nil
+		}
+		for _, requirement := range declarer.Requires() {
+			var found interface{}
+			if requirement.Type != nil {
+				found = asSearch(ctx, self, parent, requirement.Type, requirement.Filters...)
+			}
+			if found != nil {
+				if edge, ok := newRequirementEdge(node, found); ok {
+					edges = append(edges, edge)
+				}
+				continue
+			}
+
+			typeName := "<nil>"
+			if requirement.Type != nil {
+				typeName = requirement.Type.String()
+			}
+			unsatisfied = append(unsatisfied, UnsatisfiedRequirement{
+				Path:    node.path,
+				Type:    typeName,
+				Filters: len(requirement.Filters),
+			})
+		}
+		return nil
+	})
+	return edges, unsatisfied, err
+}
+
+// unresolvedInjections returns the nil, non-optional fields of node tagged
+// `autoinit:"inject"` that injection after the run's Inits would not resolve.
+// Injection does not order initialization, so resolved ones are no edges.
+func unresolvedInjections(ctx context.Context, node componentNode, self, parent interface{}) []UnsatisfiedRequirement {
+	v := node.value
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	var unsatisfied []UnsatisfiedRequirement
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := parseTag(t.Field(i))
+		field := v.Field(i)
+		if !isInjected(tag) || tag.has("optional") || !field.CanSet() || !isNilValue(field) {
+			continue
+		}
+		filters := injectFilters(tag)
+		found := asSearch(ctx, self, parent, field.Type(), filters...)
+		if found != nil && reflect.TypeOf(found).AssignableTo(field.Type()) {
+			continue
+		}
+		unsatisfied = append(unsatisfied, UnsatisfiedRequirement{
+			Path:    node.path,
+			Type:    field.Type().String(),
+			Filters: len(filters),
+		})
+	}
+	return unsatisfied
+}
+
+// requirementEdge is a declared requirement of the component at path, met by
+// the component dependency
+type requirementEdge struct {
+	path       []string
+	component  componentKey
+	dependency componentKey
+}
+
+// newRequirementEdge returns the edge from node to the component found for
+// one of its requirements
+func newRequirementEdge(node componentNode, found interface{}) (requirementEdge, bool) {
+	component, ok := keyOf(node.value)
+	if !ok {
+		return requirementEdge{}, false
+	}
+	dependency, ok := keyOf(reflect.ValueOf(found))
+	if !ok {
+		return requirementEdge{}, false
+	}
+	return requirementEdge{path: node.path, component: component, dependency: dependency}, true
+}
+
+// warnMutualRequirements warns about pairs of components requiring each
+// other. Whichever initializes first finds the other before its Init ran, so
+// one side should resolve its dependency after all Inits: with a field tagged
+// `autoinit:"inject"`, or in Link.
+func warnMutualRequirements(ctx context.Context, edges []requirementEdge) {
+	// edges are in initialization order, so the first of a pair initializes first
+	for i, first := range edges {
+		for _, second := range edges[i+1:] {
+			if first.component != second.dependency || first.dependency != second.component {
+				continue
+			}
+			message := fmt.Sprintf("%s and %s require each other; %s initializes first and finds %s uninitialized, "+
+				"so resolve its dependency with an autoinit:\"inject\" field or in Link",
+				pathToString(first.path), pathToString(second.path), pathToString(first.path), pathToString(second.path))
+			runLogger(ctx).Warn().
+				Str("path", pathToString(first.path)).
+				Str("other", pathToString(second.path)).
+				Msg("Mutual requirement")
+			recordWarning(ctx, WarningMutualRequirement, first.path, message)
+		}
+	}
+}
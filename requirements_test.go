@@ -0,0 +1,133 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type reqDatabase struct {
+	initialized bool
+}
+
+func (d *reqDatabase) Init() error {
+	d.initialized = true
+	return nil
+}
+
+type reqLogger interface {
+	Log(msg string)
+}
+
+type reqConsole struct{}
+
+func (c *reqConsole) Log(msg string) {}
+
+// reqService looks up its dependencies with As and declares them
+type reqService struct {
+	db     *reqDatabase
+	logger reqLogger
+}
+
+func (s *reqService) Requires() []Requirement {
+	return []Requirement{
+		Require[*reqDatabase](WithFieldName("Primary")),
+		Require[reqLogger](),
+	}
+}
+
+func (s *reqService) Init(ctx context.Context, parent interface{}) error {
+	MustAs(ctx, s, parent, &s.db, WithFieldName("Primary"))
+	MustAs(ctx, s, parent, &s.logger)
+	return nil
+}
+
+type reqApp struct {
+	Primary *reqDatabase
+	Replica *reqDatabase
+	Logger  *reqConsole
+	Service *reqService
+}
+
+func TestCheckRequirements(t *testing.T) {
+	app := &reqApp{Primary: &reqDatabase{}, Logger: &reqConsole{}, Service: &reqService{}}
+	if err := CheckRequirements(context.Background(), app, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := WithOptions(context.Background(), app, &Options{CheckRequirements: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Service.db != app.Primary {
+		t.Error("service did not find its database")
+	}
+
+	// Without a logger and a database named Primary, both requirements are
+	// reported before anything is initialized
+	app = &reqApp{Replica: &reqDatabase{}, Service: &reqService{}}
+	err := WithOptions(context.Background(), app, &Options{CheckRequirements: true})
+	var reqErr *RequirementsError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected *RequirementsError, got %v", err)
+	}
+	want := "Service needs *autoinit.reqDatabase (1 filter); Service needs autoinit.reqLogger"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err.Error(), want)
+	}
+	if app.Replica.initialized {
+		t.Error("components initialized despite unsatisfied requirements")
+	}
+
+	// Additional roots count, as they do for As
+	ctx := WithAdditionalRoots(context.Background(), &struct {
+		Primary *reqDatabase
+		Logger  *reqConsole
+	}{&reqDatabase{}, &reqConsole{}})
+	if err := CheckRequirements(ctx, app, nil); err != nil {
+		t.Errorf("requirements met by additional roots reported: %v", err)
+	}
+
+	if !ComponentCapabilities(&reqService{}).DependencyDeclarer {
+		t.Error("DependencyDeclarer capability not reported")
+	}
+}
+
+// reqInjected has its dependencies injected after every Init
+type reqInjected struct {
+	DB     *reqDatabase `autoinit:"inject=Primary"`
+	Logger reqLogger    `autoinit:"inject,optional"`
+}
+
+func (i *reqInjected) Init() error { return nil }
+
+func TestCheckRequirementsInjectFields(t *testing.T) {
+	type App struct {
+		Replica  *reqDatabase
+		Injected *reqInjected
+	}
+
+	// Only a database named Replica exists, so inject=Primary cannot be met;
+	// the optional logger is not reported
+	app := &App{Replica: &reqDatabase{}, Injected: &reqInjected{}}
+	err := WithOptions(context.Background(), app, &Options{CheckRequirements: true})
+	var reqErr *RequirementsError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected *RequirementsError, got %v", err)
+	}
+	want := []UnsatisfiedRequirement{{Path: []string{"Injected"}, Type: "*autoinit.reqDatabase", Filters: 1}}
+	if !reflect.DeepEqual(reqErr.Unsatisfied, want) {
+		t.Errorf("Unsatisfied = %+v; want %+v", reqErr.Unsatisfied, want)
+	}
+	if app.Replica.initialized {
+		t.Error("components initialized despite an unresolvable inject field")
+	}
+
+	type Complete struct {
+		Primary  *reqDatabase
+		Injected *reqInjected
+	}
+	if err := CheckRequirements(context.Background(), &Complete{Primary: &reqDatabase{}, Injected: &reqInjected{}}, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package autoinit
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	resourceKey     contextKey = "autoinit:resource"
+	heldResourceKey contextKey = "autoinit:heldResource"
+)
+
+// heldResource is a resource class whose slot is held by the Init call in
+// progress, linked to the classes held by the Init calls enclosing it
+type heldResource struct {
+	class string
+	next  *heldResource
+}
+
+// withFieldResource returns ctx for the component held by a field: its
+// resource class is the one named by the field's tag, if any. Unlike groups
+// and phases, the class is not inherited by the components below it.
+func withFieldResource(ctx context.Context, tag tagOptions) context.Context {
+	resource, _ := tag.value("resource")
+	if current, _ := ctx.Value(resourceKey).(string); current == resource {
+		return ctx
+	}
+	return context.WithValue(ctx, resourceKey, resource)
+}
+
+// holdsResource reports whether an Init call in progress on ctx holds a slot
+// of class, e.g. one that initializes its own subtree with a nested AutoInit
+func holdsResource(ctx context.Context, class string) bool {
+	for held, _ := ctx.Value(heldResourceKey).(*heldResource); held != nil; held = held.next {
+		if held.class == class {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceLimit returns the number of concurrent Init calls allowed for class
+// by Options.ConcurrencyByTag, 0 if it is not limited
+func resourceLimit(options *Options, class string) int {
+	if options == nil || class == "" {
+		return 0
+	}
+	if limit := options.ConcurrencyByTag[class]; limit > 0 {
+		return limit
+	}
+	return 0
+}
+
+// acquireResource waits for a slot of the resource class of the component
+// being initialized, if Options.ConcurrencyByTag limits it, and returns the
+// context to call its Init with and the function releasing the slot. It fails
+// with the run's interruption if ctx ends while waiting.
+func acquireResource(ctx context.Context, path []string, logger *zerolog.Logger, options *Options) (context.Context, func(), error) {
+	class, _ := ctx.Value(resourceKey).(string)
+	limit := resourceLimit(options, class)
+	state := getRunState(ctx)
+	// A component nested in the Init of one holding the class already runs
+	// within its slot; waiting for another could deadlock
+	if limit == 0 || state == nil || holdsResource(ctx, class) {
+		return ctx, func() {}, nil
+	}
+
+	slots := state.resourceSlots(class, limit)
+	select {
+	case slots <- struct{}{}:
+	default:
+		logger.Trace().
+			Str("path", pathToString(path)).
+			Str("resource", class).
+			Int("limit", cap(slots)).
+			Msg("Waiting for a resource slot")
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx, func() {}, interruption(ctx, path)
+		}
+	}
+
+	held, _ := ctx.Value(heldResourceKey).(*heldResource)
+	ctx = context.WithValue(ctx, heldResourceKey, &heldResource{class: class, next: held})
+	return ctx, func() { <-slots }, nil
+}
+
+// resourceSlots returns the semaphore limiting the concurrent Init calls of
+// class to limit, created by the first run component to need it
+func (s *runState) resourceSlots(class string, limit int) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slots, ok := s.resources[class]
+	if !ok {
+		slots = make(chan struct{}, limit)
+		s.resources[class] = slots
+	}
+	return slots
+}
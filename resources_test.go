@@ -0,0 +1,176 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// resourceCounter tracks how many components of a class are in their Init
+type resourceCounter struct {
+	active int32
+	max    int32
+	inits  int32
+}
+
+func (c *resourceCounter) enter() {
+	atomic.AddInt32(&c.inits, 1)
+	active := atomic.AddInt32(&c.active, 1)
+	for {
+		max := atomic.LoadInt32(&c.max)
+		if active <= max || atomic.CompareAndSwapInt32(&c.max, max, active) {
+			return
+		}
+	}
+}
+
+func (c *resourceCounter) leave() {
+	atomic.AddInt32(&c.active, -1)
+}
+
+type resourceConn struct {
+	counter *resourceCounter
+	nested  *resourceGroup
+}
+
+func (c *resourceConn) Init(ctx context.Context) error {
+	c.counter.enter()
+	defer c.counter.leave()
+	time.Sleep(5 * time.Millisecond)
+	if c.nested != nil {
+		return WithOptions(ctx, c.nested, resourceOptions(1))
+	}
+	return nil
+}
+
+type resourceGroup struct {
+	Conn  *resourceConn `autoinit:"resource=network"`
+	Local *resourceConn
+}
+
+// resourceApp initializes its groups concurrently with nested runs
+type resourceApp struct {
+	groups []*resourceGroup
+}
+
+func (a *resourceApp) Init(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(a.groups))
+	for i, group := range a.groups {
+		wg.Add(1)
+		go func(i int, group *resourceGroup) {
+			defer wg.Done()
+			errs[i] = WithOptions(ctx, group, resourceOptions(2))
+		}(i, group)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func resourceOptions(limit int) *Options {
+	return &Options{ConcurrencyByTag: map[string]int{"network": limit}}
+}
+
+func TestConcurrencyByTag(t *testing.T) {
+	network, local := &resourceCounter{}, &resourceCounter{}
+	app := &resourceApp{}
+	for i := 0; i < 8; i++ {
+		app.groups = append(app.groups, &resourceGroup{
+			Conn:  &resourceConn{counter: network},
+			Local: &resourceConn{counter: local},
+		})
+	}
+	if err := WithOptions(context.Background(), app, resourceOptions(2)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if network.inits != 8 || local.inits != 8 {
+		t.Errorf("initialized %d network and %d local components, want 8 each", network.inits, local.inits)
+	}
+	if network.max > 2 {
+		t.Errorf("%d network components initialized concurrently, limit is 2", network.max)
+	}
+}
+
+func TestConcurrencyByTagNested(t *testing.T) {
+	// A network component whose Init initializes another network component
+	// already holds the only slot and must not wait for a second one
+	counter := &resourceCounter{}
+	group := &resourceGroup{Conn: &resourceConn{
+		counter: counter,
+		nested:  &resourceGroup{Conn: &resourceConn{counter: counter}},
+	}}
+
+	done := make(chan error, 1)
+	go func() { done <- WithOptions(context.Background(), group, resourceOptions(1)) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("nested component deadlocked waiting for its class")
+	}
+	if counter.inits != 2 {
+		t.Errorf("initialized %d components, want 2", counter.inits)
+	}
+}
+
+// concurrentLeaf reads the parent chain while sibling runs initialize concurrently
+type concurrentLeaf struct {
+	Depth int
+	Group *concurrentGroup
+}
+
+func (l *concurrentLeaf) Init(ctx context.Context) error {
+	time.Sleep(time.Millisecond)
+	l.Depth = getParentChain(ctx).Len()
+	l.Group, _ = AncestorOfType[*concurrentGroup](ctx)
+	return nil
+}
+
+type concurrentGroup struct {
+	Leaf *concurrentLeaf `autoinit:"resource=network"`
+}
+
+// concurrentApp initializes its groups concurrently with nested runs
+type concurrentApp struct {
+	groups []*concurrentGroup
+}
+
+func (a *concurrentApp) Init(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(a.groups))
+	for i, group := range a.groups {
+		wg.Add(1)
+		go func(i int, group *concurrentGroup) {
+			defer wg.Done()
+			errs[i] = WithOptions(ctx, group, &Options{ContinueOnError: true, Report: &InitReport{}})
+		}(i, group)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func TestConcurrentNestedRuns(t *testing.T) {
+	// Run with -race: each nested run has its own parent chain, so leaves see
+	// their own ancestors only
+	app := &concurrentApp{}
+	for i := 0; i < 16; i++ {
+		app.groups = append(app.groups, &concurrentGroup{Leaf: &concurrentLeaf{}})
+	}
+	if _, err := AutoInitTreeReport(context.Background(), app, resourceOptions(4)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, group := range app.groups {
+		// The app, the group and the leaf itself
+		if group.Leaf.Depth != 3 {
+			t.Errorf("group %d: leaf saw a chain of %d, want 3", i, group.Leaf.Depth)
+		}
+		if group.Leaf.Group != group {
+			t.Errorf("group %d: leaf found another goroutine's group as its ancestor", i)
+		}
+	}
+}
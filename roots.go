@@ -0,0 +1,120 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	additionalRootsKey contextKey = "autoinit:additionalRoots"
+	rootKey            contextKey = "autoinit:root"
+)
+
+// RootFromContext returns the target passed to AutoInit, so deeply nested
+// components can reach app-wide configuration or services without walking the
+// parent chain or knowing their depth in the tree:
+//
+//	func (h *Handler) Init(ctx context.Context) error {
+//	    app, ok := autoinit.RootFromContext(ctx).(*App)
+//	    ...
+//	}
+//
+// Nested AutoInit calls made with a run's context keep the outermost target.
+// It returns nil outside of an AutoInit run.
+func RootFromContext(ctx context.Context) interface{} {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Value(rootKey)
+}
+
+// withRoot records target as the run's root unless ctx already carries one
+func withRoot(ctx context.Context, target interface{}) context.Context {
+	if ctx.Value(rootKey) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, rootKey, target)
+}
+
+// WithAdditionalRoots returns a context that lets As and the ComponentFinder
+// discover components in other, separately initialized trees. Use it for
+// modular apps whose subsystems are built and initialized on their own but
+// must find each other:
+//
+//	ctx = autoinit.WithAdditionalRoots(ctx, storage, messaging)
+//	err := autoinit.AutoInit(ctx, api)
+//
+// A search covers the component's own tree first, as without additional roots,
+// and only if nothing matches there each root in turn, in the order they were
+// added. For a root, the root itself is considered first, then its fields as if
+// they were siblings; the search does not descend further into a root. Roots
+// should be pointers to structs. Searches restricted with
+// SearchOption.WithinAncestorType, FindSibling and FindAncestor stay within
+// the own tree.
+func WithAdditionalRoots(ctx context.Context, roots ...interface{}) context.Context {
+	existing, _ := ctx.Value(additionalRootsKey).([]interface{})
+
+	// Copy so contexts derived earlier are not affected
+	all := make([]interface{}, len(existing), len(existing)+len(roots))
+	copy(all, existing)
+	for _, root := range roots {
+		if root != nil {
+			all = append(all, root)
+		}
+	}
+
+	return context.WithValue(ctx, additionalRootsKey, all)
+}
+
+// additionalRoots returns the roots registered with WithAdditionalRoots
+func additionalRoots(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	roots, _ := ctx.Value(additionalRootsKey).([]interface{})
+	return roots
+}
+
+// searchRootsAs searches the additional roots for As. filters describe fields,
+// so a root itself only matches a search without filters.
+func searchRootsAs(ctx context.Context, self interface{}, targetType reflect.Type, mode asMode, filters []Filter, logger *zerolog.Logger) interface{} {
+	for _, root := range additionalRoots(ctx) {
+		if sameComponent(root, self) {
+			continue
+		}
+		if len(filters) == 0 {
+			if match, ok := mode.match(reflect.ValueOf(root), targetType); ok {
+				if match.Kind() != reflect.Ptr && match.Kind() != reflect.Interface && match.CanAddr() {
+					return match.Addr().Interface()
+				}
+				return match.Interface()
+			}
+		}
+		if result := searchInStruct(root, self, targetType, mode, filters, logger); result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+// searchRoots searches the additional roots, passing matches to found until
+// it returns true
+func (cf *ComponentFinder) searchRoots(opt *SearchOption, found func(interface{}) bool) bool {
+	if opt != nil && opt.WithinAncestorType != nil {
+		return false
+	}
+	for _, root := range additionalRoots(cf.ctx) {
+		if sameComponent(root, cf.self) {
+			continue
+		}
+		if cf.matchesValue(reflect.ValueOf(root), opt) && found(cf.resultOf(reflect.ValueOf(root), opt)) {
+			return true
+		}
+		if cf.searchSiblings(root, cf.self, opt, found) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type rootsDatabase struct {
+	Name string
+}
+
+type rootsStorage struct {
+	DB *rootsDatabase
+}
+
+// rootsService discovers the database with As and the storage root with the finder
+type rootsService struct {
+	DB      *rootsDatabase
+	Storage interface{}
+}
+
+func (s *rootsService) Init(ctx context.Context, parent interface{}) error {
+	As(ctx, s, parent, &s.DB)
+	s.Storage = NewComponentFinder(ctx, s, parent).Find(&SearchOption{
+		ByType: reflect.TypeOf(&rootsStorage{}),
+	})
+	return nil
+}
+
+func TestWithAdditionalRoots(t *testing.T) {
+	type API struct {
+		Service *rootsService
+	}
+
+	storage := &rootsStorage{DB: &rootsDatabase{Name: "shared"}}
+	if err := AutoInit(context.Background(), storage); err != nil {
+		t.Fatalf("storage init failed: %v", err)
+	}
+
+	// Without additional roots nothing is found outside the tree
+	api := &API{Service: &rootsService{}}
+	if err := AutoInit(context.Background(), api); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.Service.DB != nil || api.Service.Storage != nil {
+		t.Fatalf("found components without additional roots: %+v", api.Service)
+	}
+
+	ctx := WithAdditionalRoots(context.Background(), storage)
+	api = &API{Service: &rootsService{}}
+	if err := AutoInit(ctx, api); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.Service.DB != storage.DB {
+		t.Errorf("As did not find the database in the additional root")
+	}
+	if api.Service.Storage != storage {
+		t.Errorf("Find did not match the additional root itself, got %v", api.Service.Storage)
+	}
+
+	// The own tree is searched first
+	type LocalAPI struct {
+		DB      *rootsDatabase
+		Service *rootsService
+	}
+	local := &LocalAPI{DB: &rootsDatabase{Name: "local"}, Service: &rootsService{}}
+	if err := AutoInit(ctx, local); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if local.Service.DB != local.DB {
+		t.Errorf("expected the local database, got %+v", local.Service.DB)
+	}
+
+	// Scoped searches stay within the own tree
+	finder := NewComponentFinder(ctx, local.Service, local)
+	scoped := finder.Find(&SearchOption{
+		ByType:             reflect.TypeOf(&rootsStorage{}),
+		WithinAncestorType: reflect.TypeOf(&LocalAPI{}),
+	})
+	if scoped != nil {
+		t.Errorf("scoped search reached an additional root: %v", scoped)
+	}
+	if all := finder.FindAll(&SearchOption{ByType: reflect.TypeOf(&rootsDatabase{})}); len(all) != 2 || all[0] != local.DB || all[1] != storage.DB {
+		t.Errorf("FindAll = %v, want local then shared database", all)
+	}
+}
+
+type rootConfig struct {
+	Region string
+}
+
+// rootHandler sits deep in the tree and reads the app config via the root
+type rootHandler struct {
+	Region string
+	nested *rootNested
+}
+
+func (h *rootHandler) Init(ctx context.Context) error {
+	type rootApp interface{ config() *rootConfig }
+	if app, ok := RootFromContext(ctx).(rootApp); ok {
+		h.Region = app.config().Region
+	}
+
+	// A nested run keeps the outermost target
+	h.nested = &rootNested{}
+	return AutoInit(ctx, h.nested)
+}
+
+type rootApp struct {
+	Config *rootConfig
+	API    struct {
+		V1 struct {
+			Handler *rootHandler
+		}
+	}
+}
+
+func (a *rootApp) config() *rootConfig { return a.Config }
+
+type rootNested struct {
+	Name string
+}
+
+func (n *rootNested) Init(ctx context.Context) error {
+	if _, ok := RootFromContext(ctx).(*rootApp); ok {
+		n.Name = "nested"
+	}
+	return nil
+}
+
+func TestRootFromContext(t *testing.T) {
+	app := &rootApp{Config: &rootConfig{Region: "eu-west"}}
+	app.API.V1.Handler = &rootHandler{}
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.API.V1.Handler.Region != "eu-west" {
+		t.Errorf("handler did not reach the root config, region = %q", app.API.V1.Handler.Region)
+	}
+	if app.API.V1.Handler.nested.Name != "nested" {
+		t.Error("nested run did not keep the outermost root")
+	}
+
+	if RootFromContext(context.Background()) != nil {
+		t.Error("expected no root outside of a run")
+	}
+}
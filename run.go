@@ -0,0 +1,330 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// componentKey identifies a component instance. The type is part of the key
+// because a struct and its first field share the same address.
+type componentKey struct {
+	ptr   uintptr
+	typ   reflect.Type
+	entry mapEntry // Entry of the map copy holding the component, whose offset in the copy ptr then is
+}
+
+// mapEntry identifies the entry of a map holding a struct value
+type mapEntry struct {
+	m   uintptr
+	key interface{}
+}
+
+// mapCopy is the addressable copy a struct value held in a map is initialized
+// and walked as. Every traversal makes its own copy, so components within one
+// are identified by the map entry and their offset in the copy rather than by
+// address.
+type mapCopy struct {
+	entry  mapEntry
+	base   uintptr
+	size   uintptr
+	parent *mapCopy // The copy enclosing the map, if any
+}
+
+const mapCopyKey contextKey = "autoinit:mapCopy"
+
+// newMapCopy returns the mapCopy for copied, the value of m at key, within
+// parent (nil outside any copy)
+func newMapCopy(parent *mapCopy, m, key, copied reflect.Value) *mapCopy {
+	return &mapCopy{
+		entry:  mapEntry{m: m.Pointer(), key: key.Interface()},
+		base:   copied.Addr().Pointer(),
+		size:   copied.Type().Size(),
+		parent: parent,
+	}
+}
+
+// withMapCopy returns ctx for initializing copied, the value of m at key
+func withMapCopy(ctx context.Context, m, key, copied reflect.Value) context.Context {
+	return context.WithValue(ctx, mapCopyKey, newMapCopy(mapCopyOf(ctx), m, key, copied))
+}
+
+// mapCopyOf returns the innermost map copy being initialized on ctx, if any
+func mapCopyOf(ctx context.Context) *mapCopy {
+	c, _ := ctx.Value(mapCopyKey).(*mapCopy)
+	return c
+}
+
+// keyOf returns the identity of the component at v, which lies within the
+// copy c (or one enclosing it) or, like anything outside a copy, is keyed by
+// its address
+func (c *mapCopy) keyOf(v reflect.Value) (componentKey, bool) {
+	key, ok := keyOf(v)
+	if !ok {
+		return key, false
+	}
+	for copied := c; copied != nil; copied = copied.parent {
+		if key.ptr >= copied.base && key.ptr < copied.base+copied.size {
+			key.ptr -= copied.base
+			key.entry = copied.entry
+			break
+		}
+	}
+	return key, true
+}
+
+// keyOf returns the identity of an addressable struct value
+func keyOf(v reflect.Value) (componentKey, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return componentKey{}, false
+		}
+		v = v.Elem()
+	}
+	if !v.CanAddr() {
+		return componentKey{}, false
+	}
+	return componentKey{ptr: v.Addr().Pointer(), typ: v.Type()}, true
+}
+
+// runState holds bookkeeping shared by every component of one AutoInit run.
+// It travels on the context so that nested AutoInit calls made with a run's
+// context take part in the same run.
+type runState struct {
+	mu          sync.Mutex
+	initialized map[componentKey]bool
+	// dependencies maps a component to the components it discovered with As
+	dependencies map[componentKey]map[componentKey]bool
+	// failed holds components whose lifecycle failed (see ContinueOnError)
+	failed map[componentKey]bool
+	// linked holds components whose Link method already ran
+	linked map[componentKey]bool
+	// singletons holds the first instance of each Singleton type and its path
+	singletons map[reflect.Type]singletonInstance
+	// lastCompleted is the path of the last component whose Init completed
+	lastCompleted []string
+	// initOrder lists the components of each struct type in the order their
+	// Init completed (see PreviousOfType)
+	initOrder map[reflect.Type][]interface{}
+	// resources holds a semaphore per resource class limited by
+	// Options.ConcurrencyByTag
+	resources map[string]chan struct{}
+	// interrupted is set once the run's context ended
+	interrupted *InterruptedError
+	// callbacks serializes the calls to the user callbacks of Options
+	callbacks sync.Mutex
+}
+
+// lockCallbacks takes the lock serializing the user callbacks of the run ctx
+// belongs to, such as OnEvent, OnProgress and TypeHooks, which AutoInitLayers
+// reaches from several goroutines. It returns the context to pass to the
+// callback, on which code the callback runs, e.g. a nested AutoInit, does
+// not take the lock again, and the function releasing it.
+func lockCallbacks(ctx context.Context) (context.Context, func()) {
+	state := getRunState(ctx)
+	if state == nil || ctx.Value(callbacksHeldKey) == state {
+		return ctx, func() {}
+	}
+	state.callbacks.Lock()
+	return context.WithValue(ctx, callbacksHeldKey, state), state.callbacks.Unlock
+}
+
+type runStateKeyType struct{}
+
+type callbacksHeldKeyType struct{}
+
+var callbacksHeldKey callbacksHeldKeyType
+
+var runStateKey runStateKeyType
+
+// getRunState retrieves the run state from context
+func getRunState(ctx context.Context) *runState {
+	if ctx == nil {
+		return nil
+	}
+	state, _ := ctx.Value(runStateKey).(*runState)
+	return state
+}
+
+// withRunState returns ctx carrying a run state, reusing one already present
+func withRunState(ctx context.Context) (context.Context, *runState) {
+	if state := getRunState(ctx); state != nil {
+		return ctx, state
+	}
+	state := &runState{
+		initialized:  make(map[componentKey]bool),
+		dependencies: make(map[componentKey]map[componentKey]bool),
+		failed:       make(map[componentKey]bool),
+		linked:       make(map[componentKey]bool),
+		singletons:   make(map[reflect.Type]singletonInstance),
+		initOrder:    make(map[reflect.Type][]interface{}),
+		resources:    make(map[string]chan struct{}),
+	}
+	return context.WithValue(ctx, runStateKey, state), state
+}
+
+// markInitialized records that the component at v, reached at path, completed Init
+func (s *runState) markInitialized(v reflect.Value, path []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastCompleted = append([]string(nil), path...)
+	if key, ok := keyOf(v); ok && !s.initialized[key] {
+		s.initialized[key] = true
+		if v.Kind() != reflect.Ptr {
+			v = v.Addr()
+		}
+		if v.CanInterface() {
+			s.initOrder[key.typ] = append(s.initOrder[key.typ], v.Interface())
+		}
+	}
+}
+
+// previous returns the component of the same type as key whose Init completed
+// last before key's, or last of all if key's has not completed
+func (s *runState) previous(key componentKey) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.initOrder[key.typ]
+	for i, component := range order {
+		if k, _ := keyOf(reflect.ValueOf(component)); k == key {
+			if i == 0 {
+				return nil, false
+			}
+			return order[i-1], true
+		}
+	}
+	if len(order) == 0 {
+		return nil, false
+	}
+	return order[len(order)-1], true
+}
+
+// interrupt returns the run's InterruptedError for a context that ended while
+// the component at path was being initialized. Only the first call of a run
+// records a path; later calls return the same error.
+func (s *runState) interrupt(path []string, cause error) *InterruptedError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.interrupted == nil {
+		s.interrupted = &InterruptedError{
+			LastCompleted: s.lastCompleted,
+			InProgress:    append([]string(nil), path...),
+			Cause:         cause,
+		}
+	}
+	return s.interrupted
+}
+
+// interruption returns the error to stop with if ctx has ended, nil otherwise
+func interruption(ctx context.Context, path []string) error {
+	cause := ctx.Err()
+	if cause == nil {
+		return nil
+	}
+	if state := getRunState(ctx); state != nil {
+		return state.interrupt(path, cause)
+	}
+	return cause
+}
+
+// isInitialized reports whether the component at v completed Init in this run
+func (s *runState) isInitialized(v reflect.Value) bool {
+	key, ok := keyOf(v)
+	if !ok {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.initialized[key]
+}
+
+// markFailed records that the lifecycle of the component at v failed
+func (s *runState) markFailed(v reflect.Value) {
+	key, ok := keyOf(v)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed[key] = true
+}
+
+// isFailed reports whether the lifecycle of the component at v failed in this run
+func (s *runState) isFailed(v reflect.Value) bool {
+	key, ok := keyOf(v)
+	if !ok {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failed[key]
+}
+
+// markLinked records that Link is about to run for the component at v and
+// reports whether it had not run yet
+func (s *runState) markLinked(v reflect.Value) bool {
+	key, ok := keyOf(v)
+	if !ok {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.linked[key] {
+		return false
+	}
+	s.linked[key] = true
+	return true
+}
+
+// recordDependency records that dependent discovered dependency, e.g. through As
+func (s *runState) recordDependency(dependent, dependency reflect.Value) {
+	from, ok := keyOf(dependent)
+	if !ok {
+		return
+	}
+	to, ok := keyOf(dependency)
+	if !ok || from == to {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dependencies[from] == nil {
+		s.dependencies[from] = make(map[componentKey]bool)
+	}
+	s.dependencies[from][to] = true
+}
+
+// dependencySnapshot returns a copy of the recorded dependencies
+func (s *runState) dependencySnapshot() map[componentKey]map[componentKey]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := make(map[componentKey]map[componentKey]bool, len(s.dependencies))
+	for from, deps := range s.dependencies {
+		snapshot[from] = make(map[componentKey]bool, len(deps))
+		for to := range deps {
+			snapshot[from][to] = true
+		}
+	}
+	return snapshot
+}
+
+// AlreadyInitialized reports whether component (a pointer to a struct) has already
+// completed Init during the AutoInit run that ctx belongs to. Components can use it
+// to guard against accidental re-initialization, e.g. a manual Init call or a
+// second AutoInit made with the run's context:
+//
+//	func (c *Cache) Init(ctx context.Context) error {
+//	    if autoinit.AlreadyInitialized(ctx, c) {
+//	        return errors.New("cache initialized twice")
+//	    }
+//	    ...
+//	}
+//
+// It returns false outside of an AutoInit run.
+func AlreadyInitialized(ctx context.Context, component interface{}) bool {
+	state := getRunState(ctx)
+	if state == nil || component == nil {
+		return false
+	}
+	return state.isInitialized(reflect.ValueOf(component))
+}
@@ -0,0 +1,90 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var errInitTwice = errors.New("initialized twice")
+
+// guardedComponent refuses to be initialized twice in the same run
+type guardedComponent struct {
+	InitCount int
+}
+
+func (g *guardedComponent) Init(ctx context.Context) error {
+	if AlreadyInitialized(ctx, g) {
+		return errInitTwice
+	}
+	g.InitCount++
+	return nil
+}
+
+// reinitCaller accidentally re-initializes its sibling
+type reinitCaller struct {
+	Target *guardedComponent
+}
+
+func (r *reinitCaller) Init(ctx context.Context) error {
+	return r.Target.Init(ctx)
+}
+
+func TestAlreadyInitializedDetectsReinit(t *testing.T) {
+	type App struct {
+		Guarded *guardedComponent
+		Caller  reinitCaller
+	}
+
+	app := &App{Guarded: &guardedComponent{}}
+	app.Caller.Target = app.Guarded
+
+	err := AutoInit(context.Background(), app)
+	if !errors.Is(err, errInitTwice) {
+		t.Fatalf("expected re-init to be detected, got %v", err)
+	}
+	if app.Guarded.InitCount != 1 {
+		t.Errorf("InitCount = %d; want 1", app.Guarded.InitCount)
+	}
+}
+
+func TestAlreadyInitializedAcrossNestedAutoInit(t *testing.T) {
+	guarded := &guardedComponent{}
+
+	var nestedErr error
+	type App struct {
+		Guarded *guardedComponent
+		Nested  *nestedRunner
+	}
+	app := &App{
+		Guarded: guarded,
+		Nested: &nestedRunner{run: func(ctx context.Context) {
+			// A second AutoInit using the run's context joins the same run
+			nestedErr = AutoInit(ctx, guarded)
+		}},
+	}
+
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(nestedErr, errInitTwice) {
+		t.Errorf("expected nested AutoInit to detect re-init, got %v", nestedErr)
+	}
+
+	// A separate run starts with a clean slate
+	if err := AutoInit(context.Background(), guarded); err != nil {
+		t.Errorf("a new run should be allowed to initialize again: %v", err)
+	}
+	if AlreadyInitialized(context.Background(), guarded) {
+		t.Error("AlreadyInitialized should be false outside a run")
+	}
+}
+
+type nestedRunner struct {
+	run func(ctx context.Context)
+}
+
+func (n *nestedRunner) Init(ctx context.Context) error {
+	n.run(ctx)
+	return nil
+}
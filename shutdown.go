@@ -0,0 +1,216 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Shutdowner is the interface for components that release resources during AutoShutdown
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// Starter is the conventional start method recognized as an initializer
+// when Options.RecognizeConventions is set
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// PhaseShutdown is reported after a component's Shutdown (or Close) method runs
+const PhaseShutdown EventPhase = "Shutdown"
+
+// AutoShutdown tears down all components in a struct tree in reverse initialization
+// order: parents before their children and later fields before earlier ones.
+// A component takes part if it implements Shutdowner, or io.Closer when
+// options.RecognizeConventions is set.
+//
+// The tree is traversed with the same rules as AutoInit (tags, RequireTags,
+// AllowDescend, cycle detection). Teardown is best-effort: every component is
+// shut down even if others fail, and all failures are returned joined together
+// as *ShutdownError values.
+//
+// When options.Report is the report of the AutoInit run that built the tree,
+// only components whose lifecycle completed are shut down: one whose Init
+// failed (under ContinueOnError) or never ran is left alone, so teardown code
+// need not guard against half-initialized state. Without a report every
+// component is shut down.
+func AutoShutdown(ctx context.Context, target interface{}, options *Options) error {
+	ctx = withContextValues(ctx, options)
+	logger := optionsLogger(options)
+	ctx, logger = withRunTraceID(ctx, options, logger)
+
+	logger.WithLevel(stepLogLevel(options)).
+		Str("target_type", fmt.Sprintf("%T", target)).
+		Msg("Starting AutoShutdown")
+
+	v, err := resolveTarget(target, "shut down")
+	if err != nil {
+		return err
+	}
+
+	// Collect components in init order, then shut them down in reverse
+	var nodes []componentNode
+	if err := walkTree(v, options, func(node componentNode) error {
+		if !selectsGroup(options, node.group) {
+			return nil
+		}
+		key, ok := node.copy.keyOf(node.value)
+		if ok && options != nil && options.Report != nil && !options.Report.shutdownAllowed(key) {
+			logger.Trace().
+				Str("path", pathToString(node.path)).
+				Msg("Skipping shutdown of component that did not complete initialization")
+			return nil
+		}
+		nodes = append(nodes, node)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if err := callShutdownIfExists(ctx, nodes[i], &logger, options); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	err = errors.Join(errs...)
+	if err != nil {
+		logger.WithLevel(errorLogLevel(options)).
+			Err(err).
+			Msg("AutoShutdown failed")
+	} else {
+		logger.WithLevel(stepLogLevel(options)).
+			Msg("AutoShutdown completed successfully")
+	}
+	return err
+}
+
+// resolveShutdown finds the teardown method implemented by v, if any
+func resolveShutdown(v reflect.Value, conventions bool) *lifecycleCall {
+	ptr := v
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		ptr = v.Addr()
+	}
+	if !ptr.CanInterface() {
+		return nil
+	}
+
+	typeName := ptr.Type().String()
+	if shutdowner, ok := ptr.Interface().(Shutdowner); ok {
+		return &lifecycleCall{
+			method:   "Shutdown(ctx)",
+			typeName: typeName,
+			invoke:   shutdowner.Shutdown,
+		}
+	}
+	if closer, ok := ptr.Interface().(io.Closer); ok && conventions {
+		return &lifecycleCall{
+			method:   "Close()",
+			typeName: typeName,
+			invoke: func(context.Context) error {
+				return closer.Close()
+			},
+		}
+	}
+	return nil
+}
+
+// callShutdownIfExists calls the teardown method of a component if it has one
+func callShutdownIfExists(ctx context.Context, node componentNode, logger *zerolog.Logger, options *Options) error {
+	call := resolveShutdown(node.value, options != nil && options.RecognizeConventions)
+	if call == nil {
+		return nil
+	}
+
+	pathStr := pathToString(node.path)
+	logger.WithLevel(stepLogLevel(options)).
+		Str("path", pathStr).
+		Str("type", call.typeName).
+		Str("method", call.method).
+		Msg("Calling shutdown")
+
+	start := time.Now()
+	err := call.invoke(ctx)
+	emitEvent(ctx, options, Event{
+		Phase:      PhaseShutdown,
+		Path:       node.path,
+		FieldIndex: node.index,
+		Type:       call.typeName,
+		Err:        err,
+		Duration:   time.Since(start),
+	})
+
+	if err != nil {
+		logger.WithLevel(errorLogLevel(options)).
+			Str("path", pathStr).
+			Err(err).
+			Msg(call.method + " failed")
+		return &ShutdownError{
+			Path:      node.path,
+			FieldType: call.typeName,
+			Cause:     err,
+		}
+	}
+
+	logger.WithLevel(stepLogLevel(options)).
+		Str("path", pathStr).
+		Msg(call.method + " completed successfully")
+	return nil
+}
+
+// OnContextDone registers fn to run on its own goroutine once ctx is cancelled.
+// Call it from Init to tie cleanup of long-running work started there (listeners,
+// background loops) to the application context, e.g. one cancelled on SIGTERM:
+//
+//	func (s *Server) Init(ctx context.Context) error {
+//	    go s.serve()
+//	    autoinit.OnContextDone(ctx, s.stop)
+//	    return nil
+//	}
+//
+// The returned stop function unregisters fn; it reports whether it prevented fn
+// from running. fn never runs for a context that cannot be cancelled.
+func OnContextDone(ctx context.Context, fn func()) (stop func() bool) {
+	done := ctx.Done()
+	if done == nil {
+		return func() bool { return true }
+	}
+
+	var mu sync.Mutex
+	finished := false
+	stopCh := make(chan struct{})
+
+	go func() {
+		select {
+		case <-done:
+			mu.Lock()
+			if finished {
+				mu.Unlock()
+				return
+			}
+			finished = true
+			mu.Unlock()
+			fn()
+		case <-stopCh:
+		}
+	}()
+
+	return func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if finished {
+			return false
+		}
+		finished = true
+		close(stopCh)
+		return true
+	}
+}
@@ -0,0 +1,274 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// startedServer uses the Start(ctx) convention instead of Init
+type startedServer struct {
+	Started bool
+	Closed  bool
+}
+
+func (s *startedServer) Start(ctx context.Context) error {
+	s.Started = true
+	return nil
+}
+
+func (s *startedServer) Close() error {
+	s.Closed = true
+	return nil
+}
+
+// shutdownRecorder records the order of teardown calls
+type shutdownRecorder struct {
+	Name  string
+	Calls *[]string
+	Fail  bool
+}
+
+func (r *shutdownRecorder) Init() error {
+	return nil
+}
+
+func (r *shutdownRecorder) Shutdown(ctx context.Context) error {
+	*r.Calls = append(*r.Calls, r.Name)
+	if r.Fail {
+		return errors.New("shutdown failed")
+	}
+	return nil
+}
+
+type shutdownModule struct {
+	shutdownRecorder
+	Child *shutdownRecorder
+}
+
+type shutdownApp struct {
+	First  *shutdownRecorder
+	Module *shutdownModule
+	Last   *shutdownRecorder
+}
+
+func newShutdownApp(calls *[]string) *shutdownApp {
+	return &shutdownApp{
+		First: &shutdownRecorder{Name: "first", Calls: calls},
+		Module: &shutdownModule{
+			shutdownRecorder: shutdownRecorder{Name: "module", Calls: calls},
+			Child:            &shutdownRecorder{Name: "child", Calls: calls},
+		},
+		Last: &shutdownRecorder{Name: "last", Calls: calls},
+	}
+}
+
+func TestAutoShutdownReverseOrder(t *testing.T) {
+	var calls []string
+	app := newShutdownApp(&calls)
+	logger := zerolog.Nop()
+
+	if err := AutoShutdown(context.Background(), app, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Init order is first, child, module, last. The module's Shutdown is
+	// promoted from its (unexported, so not traversed) embedded recorder.
+	expected := []string{"last", "module", "child", "first"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("shutdown order = %v; want %v", calls, expected)
+	}
+}
+
+func TestAutoShutdownContinuesOnError(t *testing.T) {
+	var calls []string
+	app := newShutdownApp(&calls)
+	app.Last.Fail = true
+	logger := zerolog.Nop()
+
+	err := AutoShutdown(context.Background(), app, &Options{Logger: &logger})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) {
+		t.Fatalf("expected *ShutdownError, got %T", err)
+	}
+	if pathToString(shutdownErr.Path) != "Last" {
+		t.Errorf("error path = %v; want Last", shutdownErr.Path)
+	}
+	if len(calls) != 4 {
+		t.Errorf("all components should be shut down, got %v", calls)
+	}
+}
+
+func TestRecognizeConventions(t *testing.T) {
+	type App struct {
+		Server *startedServer
+	}
+	logger := zerolog.Nop()
+
+	// Without the option, Start and Close are ignored
+	app := &App{Server: &startedServer{}}
+	if err := WithOptions(context.Background(), app, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := AutoShutdown(context.Background(), app, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Server.Started || app.Server.Closed {
+		t.Error("convention methods should not be called without RecognizeConventions")
+	}
+
+	// With the option, Start initializes and Close tears down
+	options := &Options{Logger: &logger, RecognizeConventions: true}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !app.Server.Started {
+		t.Error("Start(ctx) should be called as the initializer")
+	}
+	if err := AutoShutdown(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !app.Server.Closed {
+		t.Error("Close() should be called during shutdown")
+	}
+}
+
+// backgroundWorker registers its cleanup with the application context during Init
+type backgroundWorker struct {
+	stopped chan struct{}
+}
+
+func (w *backgroundWorker) Init(ctx context.Context) error {
+	OnContextDone(ctx, func() { close(w.stopped) })
+	return nil
+}
+
+// Test that finalizers registered in Init run when the context is cancelled
+func TestOnContextDone(t *testing.T) {
+	logger := zerolog.Nop()
+	ctx, cancel := context.WithCancel(context.Background())
+	worker := &backgroundWorker{stopped: make(chan struct{})}
+
+	if err := WithOptions(ctx, worker, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-worker.stopped:
+		t.Fatal("finalizer ran before cancellation")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-worker.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("finalizer did not run after cancellation")
+	}
+
+	// A stopped registration never runs
+	ctx, cancel = context.WithCancel(context.Background())
+	ran := make(chan struct{})
+	stop := OnContextDone(ctx, func() { close(ran) })
+	if !stop() {
+		t.Error("stop should report that it prevented the finalizer")
+	}
+	if stop() {
+		t.Error("second stop should report false")
+	}
+	cancel()
+	select {
+	case <-ran:
+		t.Error("stopped finalizer should not run")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// failingShutdownRecorder fails its Init but would record a Shutdown call
+type failingShutdownRecorder struct {
+	shutdownRecorder
+}
+
+func (r *failingShutdownRecorder) Init() error {
+	return errors.New("init failed")
+}
+
+func TestAutoShutdownSkipsFailedInit(t *testing.T) {
+	type App struct {
+		First  *shutdownRecorder
+		Broken *failingShutdownRecorder
+		Last   *shutdownRecorder
+	}
+
+	var calls []string
+	app := &App{
+		First:  &shutdownRecorder{Name: "First", Calls: &calls},
+		Broken: &failingShutdownRecorder{shutdownRecorder{Name: "Broken", Calls: &calls}},
+		Last:   &shutdownRecorder{Name: "Last", Calls: &calls},
+	}
+	logger := zerolog.Nop()
+	options := &Options{Logger: &logger, Report: &InitReport{}, ContinueOnError: true}
+	if err := WithOptions(context.Background(), app, options); err == nil {
+		t.Fatal("expected Broken to fail")
+	}
+
+	if err := AutoShutdown(context.Background(), app, options); err != nil {
+		t.Fatalf("AutoShutdown failed: %v", err)
+	}
+	if want := []string{"Last", "First"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("shutdown calls = %v, want %v", calls, want)
+	}
+
+	// Without a report every component is shut down
+	calls = nil
+	if err := AutoShutdown(context.Background(), app, &Options{Logger: &logger}); err != nil {
+		t.Fatalf("AutoShutdown failed: %v", err)
+	}
+	if want := []string{"Last", "Broken", "First"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("shutdown calls without report = %v, want %v", calls, want)
+	}
+}
+
+// shutdownRecorderModule holds a recorder by value, so within a map entry it lies at
+// an offset in the entry's copy
+type shutdownRecorderModule struct {
+	Label    string
+	Recorder shutdownRecorder
+}
+
+func TestAutoShutdownMapValues(t *testing.T) {
+	type App struct {
+		Recorders map[string]shutdownRecorder
+		Modules   map[string]shutdownRecorderModule
+		Broken    map[string]failingShutdownRecorder
+	}
+
+	var calls []string
+	app := &App{
+		Recorders: map[string]shutdownRecorder{"a": {Name: "a", Calls: &calls}},
+		Modules:   map[string]shutdownRecorderModule{"m": {Recorder: shutdownRecorder{Name: "m", Calls: &calls}}},
+		Broken:    map[string]failingShutdownRecorder{"b": {shutdownRecorder{Name: "b", Calls: &calls}}},
+	}
+	logger := zerolog.Nop()
+	options := &Options{Logger: &logger, Report: &InitReport{}, ContinueOnError: true}
+	if err := WithOptions(context.Background(), app, options); err == nil {
+		t.Fatal("expected the broken entry to fail")
+	}
+
+	// Init and AutoShutdown each work on their own copy of a map value; the
+	// entries that completed their lifecycle are still recognized
+	if err := AutoShutdown(context.Background(), app, options); err != nil {
+		t.Fatalf("AutoShutdown failed: %v", err)
+	}
+	if want := []string{"m", "a"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("shutdown calls = %v, want %v", calls, want)
+	}
+}
@@ -0,0 +1,70 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Singleton marks a component type of which a tree may hold only one
+// instance. AutoInit fails with ErrDuplicateSingleton when it reaches a second,
+// distinct instance of the type, catching accidental duplication such as two
+// separate *Logger instances:
+//
+//	func (l *Logger) Singleton() {}
+//
+// Reaching the same instance through several fields is fine. Instances are
+// counted per run, so nested AutoInit calls made with a run's context count
+// towards it.
+type Singleton interface {
+	Singleton()
+}
+
+// singletonInstance records where the first instance of a Singleton type was found
+type singletonInstance struct {
+	key  componentKey
+	path []string
+}
+
+// isSingleton reports whether struct v implements Singleton
+func isSingleton(v reflect.Value) bool {
+	if v.CanAddr() && v.Addr().CanInterface() {
+		_, ok := v.Addr().Interface().(Singleton)
+		return ok
+	}
+	if v.CanInterface() {
+		_, ok := v.Interface().(Singleton)
+		return ok
+	}
+	return false
+}
+
+// checkSingleton fails if struct v is a Singleton of which the run already
+// holds a different instance
+func checkSingleton(ctx context.Context, v reflect.Value, path []string, index int) error {
+	state := getRunState(ctx)
+	if state == nil || !isSingleton(v) {
+		return nil
+	}
+	key, ok := keyOf(v)
+	if !ok {
+		return nil
+	}
+
+	state.mu.Lock()
+	first, seen := state.singletons[key.typ]
+	if !seen {
+		state.singletons[key.typ] = singletonInstance{key: key, path: append([]string(nil), path...)}
+	}
+	state.mu.Unlock()
+
+	if !seen || first.key == key {
+		return nil
+	}
+	return &InitError{
+		Path:       path,
+		FieldIndex: index,
+		FieldType:  reflect.PointerTo(key.typ).String(),
+		Cause:      fmt.Errorf("%w (first instance at %s)", ErrDuplicateSingleton, pathToString(first.path)),
+	}
+}
@@ -0,0 +1,45 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type singletonLogger struct {
+	Name string
+}
+
+func (l *singletonLogger) Singleton() {}
+
+func TestSingleton(t *testing.T) {
+	type Module struct {
+		Logger *singletonLogger
+	}
+	type App struct {
+		Logger *singletonLogger
+		Module *Module
+	}
+
+	// The same instance reached through several fields is allowed
+	shared := &singletonLogger{Name: "shared"}
+	app := &App{Logger: shared, Module: &Module{Logger: shared}}
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error for a shared instance: %v", err)
+	}
+
+	// Two distinct instances are rejected
+	app = &App{Logger: &singletonLogger{Name: "a"}, Module: &Module{Logger: &singletonLogger{Name: "b"}}}
+	err := AutoInit(context.Background(), app)
+	if !errors.Is(err, ErrDuplicateSingleton) {
+		t.Fatalf("expected ErrDuplicateSingleton, got %v", err)
+	}
+	var initErr *InitError
+	if !errors.As(err, &initErr) || pathToString(initErr.GetPath()) != "Module.Logger" {
+		t.Fatalf("expected the error at Module.Logger, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "first instance at Logger") {
+		t.Errorf("error does not name the first instance: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package autoinit
+
+import (
+	"reflect"
+	"sort"
+)
+
+// SnapshotState captures the exported field values of every component in the
+// tree, keyed by dotted path (e.g. "Database.Host"), so tests can compare the
+// state before and after AutoInit:
+//
+//	before, _ := autoinit.SnapshotState(app)
+//	autoinit.AutoInit(ctx, app)
+//	after, _ := autoinit.SnapshotState(app)
+//	changed := autoinit.DiffState(before, after) // e.g. ["Database.Conn"]
+//
+// The tree is traversed with AutoInit's default rules, visiting each pointer
+// once, so cycles are handled. Unexported fields are skipped. Fields holding a
+// struct AutoInit descends into are not recorded themselves, their fields are
+// recorded under their own paths; every other field is recorded as a value,
+// including leaf types such as time.Time, fields tagged `autoinit:"-"` and nil
+// struct pointers.
+//
+// Slices and maps are copied so later appends or writes do not change the
+// snapshot, but their elements, and values behind other pointers, are not.
+func SnapshotState(target interface{}) (map[string]interface{}, error) {
+	v, err := resolveTarget(target, "snapshot")
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []componentNode
+	if err := walkTree(v, nil, func(node componentNode) error {
+		nodes = append(nodes, node)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	byPath := indexByPath(nodes)
+
+	state := make(map[string]interface{})
+	for _, node := range nodes {
+		t := node.value.Type()
+		for i := 0; i < t.NumField(); i++ {
+			fieldType := t.Field(i)
+			if !fieldType.IsExported() {
+				continue
+			}
+			// A struct the walk visited records its own fields
+			path := pathToString(appendPath(node.path, fieldType.Name))
+			if _, visited := byPath[path]; visited {
+				continue
+			}
+			state[path] = snapshotValue(node.value.Field(i))
+		}
+	}
+	return state, nil
+}
+
+// snapshotValue returns the value of field, copying slices and maps one level
+func snapshotValue(field reflect.Value) interface{} {
+	return copyValue(field).Interface()
+}
+
+// copyValue returns a copy of v that later appends to a slice or writes to a
+// map held by v do not change. Elements and values behind pointers are shared.
+func copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			break
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		return copied
+
+	case reflect.Map:
+		if v.IsNil() {
+			break
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return copied
+	}
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+	return copied
+}
+
+// restorePoint saves the exported fields of the component at v so that a
+// failed Init can be undone (see Options.RestoreOnFailure). It returns nil if
+// v is not an addressable struct, whose changes are not visible anyway.
+func restorePoint(v reflect.Value) func() {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !v.CanAddr() {
+		return nil
+	}
+
+	saved := make([]reflect.Value, v.NumField())
+	for i := range saved {
+		if field := v.Field(i); v.Type().Field(i).IsExported() && field.CanSet() {
+			saved[i] = copyValue(field)
+		}
+	}
+	return func() {
+		for i, value := range saved {
+			if value.IsValid() {
+				v.Field(i).Set(value)
+			}
+		}
+	}
+}
+
+// DiffState returns the sorted paths whose values differ between two snapshots
+// taken with SnapshotState, including paths present in only one of them.
+// Values are compared with reflect.DeepEqual.
+func DiffState(before, after map[string]interface{}) []string {
+	var changed []string
+	for path, old := range before {
+		current, ok := after[path]
+		if !ok || !reflect.DeepEqual(old, current) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
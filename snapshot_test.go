@@ -0,0 +1,174 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type snapshotDB struct {
+	Host    string
+	Ready   bool
+	Tables  []string
+	Options map[string]string
+	App     *snapshotApp // back-reference forming a cycle
+	conn    int
+}
+
+func (d *snapshotDB) Init() error {
+	d.Ready = true
+	d.Tables = append(d.Tables, "users")
+	d.Options["pool"] = "10"
+	d.conn = 1
+	return nil
+}
+
+type snapshotApp struct {
+	Name     string
+	DB       *snapshotDB
+	Cache    *snapshotDB
+	Internal *snapshotDB `autoinit:"-"`
+}
+
+func TestSnapshotState(t *testing.T) {
+	app := &snapshotApp{Name: "svc"}
+	app.DB = &snapshotDB{Host: "localhost", Tables: []string{"orders"}, Options: map[string]string{}, App: app}
+
+	before, err := SnapshotState(app)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if before["Name"] != "svc" || before["DB.Host"] != "localhost" {
+		t.Errorf("snapshot missing values: %v", before)
+	}
+	if v, ok := before["Cache"]; !ok || v != (*snapshotDB)(nil) {
+		t.Errorf("nil component should be recorded as nil, got %v", v)
+	}
+	if _, ok := before["DB.conn"]; ok {
+		t.Error("unexported fields should be skipped")
+	}
+	if _, ok := before["DB"]; ok {
+		t.Error("non-nil components should be recorded by their fields")
+	}
+
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after, err := SnapshotState(app)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Appends and map writes must not leak into the earlier snapshot
+	expected := []string{"DB.Options", "DB.Ready", "DB.Tables"}
+	if changed := DiffState(before, after); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("changed = %v; want %v", changed, expected)
+	}
+
+	if _, err := SnapshotState(nil); err == nil {
+		t.Error("expected an error for a nil target")
+	}
+}
+
+// snapshotClock holds values AutoInit does not descend into
+type snapshotClock struct {
+	Started  time.Time
+	Endpoint *url.URL
+	Settings snapshotSettings `autoinit:"-"`
+}
+
+type snapshotSettings struct {
+	Retries int
+}
+
+func (c *snapshotClock) Init() error {
+	c.Started = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	c.Endpoint.Host = "db:5432"
+	c.Settings.Retries = 3
+	return nil
+}
+
+func TestSnapshotStateLeafValues(t *testing.T) {
+	type App struct {
+		Clock *snapshotClock
+	}
+	app := &App{Clock: &snapshotClock{Endpoint: &url.URL{Scheme: "postgres"}}}
+
+	// The URL is changed in place, so it is compared with a copy
+	before, err := SnapshotState(&App{Clock: &snapshotClock{Endpoint: &url.URL{Scheme: "postgres"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after, err := SnapshotState(app)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"Clock.Endpoint", "Clock.Settings", "Clock.Started"}
+	if changed := DiffState(before, after); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("changed = %v; want %v", changed, expected)
+	}
+}
+
+// restoreConn mutates itself before failing
+type restoreConn struct {
+	Addr    string
+	Retries int
+	Peers   []string
+	Labels  map[string]string
+	Panic   bool
+	touched bool
+}
+
+func (c *restoreConn) Init() error {
+	c.Addr = "10.0.0.1:5432"
+	c.Retries++
+	c.Peers = append(c.Peers, "replica")
+	c.Labels["state"] = "dialing"
+	c.touched = true
+	if c.Panic {
+		panic("dial")
+	}
+	return errors.New("connection refused")
+}
+
+func TestRestoreOnFailure(t *testing.T) {
+	newConn := func() *restoreConn {
+		return &restoreConn{Addr: "db:5432", Peers: make([]string, 1, 4), Labels: map[string]string{"role": "primary"}}
+	}
+	want := newConn()
+
+	conn := newConn()
+	if err := WithOptions(context.Background(), conn, &Options{RestoreOnFailure: true}); err == nil {
+		t.Fatal("expected Init to fail")
+	}
+	if conn.Addr != want.Addr || conn.Retries != 0 || !reflect.DeepEqual(conn.Peers, want.Peers) || !reflect.DeepEqual(conn.Labels, want.Labels) {
+		t.Errorf("exported fields not restored: %+v", conn)
+	}
+	if !conn.touched {
+		t.Error("unexported fields should be left as Init set them")
+	}
+
+	// Recovered panics are failures too
+	conn = newConn()
+	conn.Panic = true
+	if err := WithOptions(context.Background(), conn, &Options{RestoreOnFailure: true, RecoverPanics: true}); err == nil {
+		t.Fatal("expected the panic to fail Init")
+	}
+	if conn.Addr != want.Addr || conn.Retries != 0 {
+		t.Errorf("fields not restored after a panic: %+v", conn)
+	}
+
+	// Without the option the partial changes remain
+	conn = newConn()
+	_ = AutoInit(context.Background(), conn)
+	if conn.Addr == want.Addr || conn.Labels["state"] != "dialing" {
+		t.Errorf("fields restored without RestoreOnFailure: %+v", conn)
+	}
+}
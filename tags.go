@@ -0,0 +1,121 @@
+package autoinit
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tagOptions holds the parsed contents of an autoinit struct tag.
+// Tags are comma-separated lists of flags (e.g. "serial") and
+// key=value options (e.g. "priority=10"). The legacy values "" and
+// "init" carry no options and simply mark the field as tagged.
+type tagOptions struct {
+	present bool              // Field has an autoinit tag at all
+	skip    bool              // Tag is "-"
+	flags   map[string]bool   // Bare options such as "serial"
+	values  map[string]string // key=value options
+}
+
+// parseTag parses the autoinit tag of a struct field
+func parseTag(field reflect.StructField) tagOptions {
+	tag, ok := field.Tag.Lookup("autoinit")
+	opts := tagOptions{present: ok}
+	if !ok {
+		return opts
+	}
+	if tag == "-" {
+		opts.skip = true
+		return opts
+	}
+
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "init" {
+			continue
+		}
+		if key, value, found := strings.Cut(part, "="); found {
+			if opts.values == nil {
+				opts.values = make(map[string]string)
+			}
+			opts.values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			continue
+		}
+		if opts.flags == nil {
+			opts.flags = make(map[string]bool)
+		}
+		opts.flags[part] = true
+	}
+	return opts
+}
+
+// has reports whether the tag contains the given flag
+func (o tagOptions) has(flag string) bool {
+	return o.flags[flag]
+}
+
+// value returns the value of a key=value option
+func (o tagOptions) value(key string) (string, bool) {
+	v, ok := o.values[key]
+	return v, ok
+}
+
+// priority returns the field's priority=N option, 0 if unset
+func (o tagOptions) priority() (int, error) {
+	raw, ok := o.value("priority")
+	if !ok {
+		return 0, nil
+	}
+	p, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid autoinit priority %q: must be an integer", raw)
+	}
+	return p, nil
+}
+
+// fieldOrder returns the field indices of struct type t in initialization order:
+// ascending priority, with ties kept in declaration order (reverse declaration
+// order with Options.ReverseFieldOrder). With Options.DeferCollections,
+// collection fields follow all other fields, each group in that order.
+func fieldOrder(t reflect.Type, options *Options) ([]int, error) {
+	reverse := options != nil && options.ReverseFieldOrder
+	deferCollections := options != nil && options.DeferCollections
+	order := make([]int, t.NumField())
+	priorities := make([]int, t.NumField())
+	deferred := make([]bool, t.NumField())
+	for i := range order {
+		order[i] = i
+		if reverse {
+			order[i] = len(order) - 1 - i
+		}
+		p, err := parseTag(t.Field(i)).priority()
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", t.String(), t.Field(i).Name, err)
+		}
+		priorities[i] = p
+		deferred[i] = deferCollections && isCollectionType(t.Field(i).Type)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if deferred[order[a]] != deferred[order[b]] {
+			return deferred[order[b]]
+		}
+		return priorities[order[a]] < priorities[order[b]]
+	})
+	return order, nil
+}
+
+var iterableType = reflect.TypeOf((*Iterable)(nil)).Elem()
+
+// isCollectionType reports whether fields of type t hold their components as
+// elements: slices, arrays, maps and Iterable containers
+func isCollectionType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	case reflect.Struct:
+		return reflect.PointerTo(t).Implements(iterableType)
+	}
+	return t.Kind() != reflect.Interface && t.Implements(iterableType)
+}
@@ -2,7 +2,10 @@ package autoinit
 
 import (
 	"context"
+	"reflect"
 	"testing"
+
+	"github.com/rs/zerolog"
 )
 
 // Test types for tag-based initialization
@@ -277,3 +280,209 @@ func TestNestedFieldsWithParentTag(t *testing.T) {
 		t.Error("Child.Service2 should NOT be initialized (no tag with RequireTags=true)")
 	}
 }
+
+// Test parsing of multi-option autoinit tags
+func TestParseTagOptions(t *testing.T) {
+	type tagged struct {
+		None    int
+		Skip    int `autoinit:"-"`
+		Init    int `autoinit:"init"`
+		Empty   int `autoinit:""`
+		Options int `autoinit:"serial, priority=10"`
+	}
+
+	typ := reflect.TypeOf(tagged{})
+	field := func(name string) reflect.StructField {
+		f, _ := typ.FieldByName(name)
+		return f
+	}
+
+	if opts := parseTag(field("None")); opts.present {
+		t.Error("untagged field should not be marked present")
+	}
+	if opts := parseTag(field("Skip")); !opts.present || !opts.skip {
+		t.Error("autoinit:\"-\" should be present and skipped")
+	}
+	for _, name := range []string{"Init", "Empty"} {
+		opts := parseTag(field(name))
+		if !opts.present || opts.skip || len(opts.flags) != 0 || len(opts.values) != 0 {
+			t.Errorf("%s: expected a plain tag without options, got %+v", name, opts)
+		}
+	}
+
+	opts := parseTag(field("Options"))
+	if !opts.has("serial") {
+		t.Error("expected serial flag")
+	}
+	if v, ok := opts.value("priority"); !ok || v != "10" {
+		t.Errorf("expected priority=10, got %q (present=%v)", v, ok)
+	}
+}
+
+type serialMarked struct {
+	Initialized bool
+}
+
+func (s *serialMarked) SerialInit() {}
+
+func (s *serialMarked) Init() error {
+	s.Initialized = true
+	return nil
+}
+
+// Test that serial components are detected and initialized normally
+func TestSerialComponents(t *testing.T) {
+	type App struct {
+		Marked serialMarked
+		Tagged *SimpleComponent `autoinit:"serial"`
+		Plain  *SimpleComponent
+	}
+
+	app := &App{Tagged: &SimpleComponent{}, Plain: &SimpleComponent{}}
+	v := reflect.ValueOf(app).Elem()
+	typ := v.Type()
+
+	for i, want := range []bool{true, true, false} {
+		got := requiresSerialInit(v.Field(i), parseTag(typ.Field(i)))
+		if got != want {
+			t.Errorf("%s: requiresSerialInit = %v; want %v", typ.Field(i).Name, got, want)
+		}
+	}
+
+	if err := AutoInit(context.Background(), app); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !app.Marked.Initialized || !app.Tagged.Initialized || !app.Plain.Initialized {
+		t.Error("expected all components to be initialized")
+	}
+}
+
+// Test that priority=N reorders initialization within a parent
+func TestPriorityTag(t *testing.T) {
+	type App struct {
+		First  *orderedComponent
+		Late   *orderedComponent `autoinit:"priority=5"`
+		Early  *orderedComponent `autoinit:"priority=-1"`
+		Second *orderedComponent
+	}
+
+	app := &App{
+		First:  &orderedComponent{},
+		Late:   &orderedComponent{},
+		Early:  &orderedComponent{},
+		Second: &orderedComponent{},
+	}
+
+	var order []string
+	logger := zerolog.Nop()
+	options := &Options{
+		Logger: &logger,
+		OnEvent: func(e Event) {
+			order = append(order, e.PathString())
+		},
+	}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"Early", "First", "Second", "Late"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("init order = %v; want %v", order, expected)
+	}
+
+	// Invalid priorities are reported as errors
+	type Bad struct {
+		C *orderedComponent `autoinit:"priority=high"`
+	}
+	if err := WithOptions(context.Background(), &Bad{}, &Options{Logger: &logger}); err == nil {
+		t.Error("expected error for non-integer priority")
+	}
+}
+
+func TestReverseFieldOrder(t *testing.T) {
+	type Inner struct {
+		A *orderedComponent
+		B *orderedComponent
+	}
+	type App struct {
+		First  *orderedComponent
+		Nested *Inner
+		Early  *orderedComponent `autoinit:"priority=-1"`
+		Last   *orderedComponent
+	}
+
+	app := &App{
+		First:  &orderedComponent{},
+		Nested: &Inner{A: &orderedComponent{}, B: &orderedComponent{}},
+		Early:  &orderedComponent{},
+		Last:   &orderedComponent{},
+	}
+
+	var order []string
+	logger := zerolog.Nop()
+	options := &Options{
+		Logger:            &logger,
+		ReverseFieldOrder: true,
+		OnEvent: func(e Event) {
+			order = append(order, e.PathString())
+		},
+	}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Priorities still come first; equal priorities run last to first, at every level
+	expected := []string{"Early", "Last", "Nested.B", "Nested.A", "First"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("init order = %v; want %v", order, expected)
+	}
+}
+
+type weakParent struct {
+	Child *weakChild
+	Inits int
+}
+
+func (p *weakParent) Init() error {
+	p.Inits++
+	return nil
+}
+
+// weakChild keeps a navigation-only pointer back to its parent
+type weakChild struct {
+	Parent *weakParent      `autoinit:"weak"`
+	Peer   *SimpleComponent `autoinit:"weak"`
+}
+
+func TestWeakTag(t *testing.T) {
+	parent := &weakParent{}
+	parent.Child = &weakChild{Parent: parent, Peer: &SimpleComponent{}}
+
+	var paths []string
+	report := &InitReport{}
+	logger := zerolog.Nop()
+	options := &Options{
+		Logger: &logger,
+		Report: report,
+		OnEvent: func(e Event) {
+			paths = append(paths, e.PathString())
+		},
+	}
+	if err := WithOptions(context.Background(), parent, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The back-reference is neither traversed nor initialized, and is left intact
+	if parent.Inits != 1 || len(paths) != 1 {
+		t.Errorf("parent initialized %d times via %v; want once, as the root", parent.Inits, paths)
+	}
+	if parent.Child.Parent != parent {
+		t.Error("weak pointer should be left intact")
+	}
+	if parent.Child.Peer.Initialized {
+		t.Error("weak fields should not be initialized")
+	}
+	if reason, ok := report.SkipReasonFor("Child.Parent"); !ok || reason != SkipWeak {
+		t.Errorf("skip reason = %v, %v; want %v", reason, ok, SkipWeak)
+	}
+}
@@ -17,6 +17,7 @@ type TestContext struct {
 	dependencies map[reflect.Type][]interface{}
 	namedDeps    map[string]interface{}
 	taggedDeps   map[string][]interface{}
+	overrides    map[reflect.Type]interface{}
 	mu           sync.RWMutex
 }
 
@@ -26,6 +27,7 @@ func NewTestContext() *TestContext {
 		dependencies: make(map[reflect.Type][]interface{}),
 		namedDeps:    make(map[string]interface{}),
 		taggedDeps:   make(map[string][]interface{}),
+		overrides:    make(map[reflect.Type]interface{}),
 	}
 }
 
@@ -85,6 +87,30 @@ func (tc *TestContext) RegisterTagged(tag string, dep interface{}) *TestContext
 	return tc
 }
 
+// Override forces dep to be returned whenever typ is looked up, taking precedence
+// over everything registered for typ. Calling Override again for the same type
+// replaces the previous override. This lets a base set of dependencies be
+// registered once while individual tests swap out a single instance.
+func (tc *TestContext) Override(typ reflect.Type, dep interface{}) *TestContext {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.overrides[typ] = dep
+	return tc
+}
+
+// lookup returns the dependency to use for typ: its override if one is set,
+// otherwise the first registered candidate. Callers must hold tc.mu.
+func (tc *TestContext) lookup(typ reflect.Type) (interface{}, bool) {
+	if dep, ok := tc.overrides[typ]; ok {
+		return dep, true
+	}
+	if candidates := tc.dependencies[typ]; len(candidates) > 0 {
+		return candidates[0], true
+	}
+	return nil, false
+}
+
 // Context returns a context.Context that can be used with As/MustAs for dependency discovery.
 func (tc *TestContext) Context() context.Context {
 	return context.WithValue(context.Background(), testCtxKey, tc)
@@ -133,11 +159,9 @@ func TestAs[T any](ctx context.Context, target interface{}, dest *T, filters ...
 	}
 
 	// Try to find by type first
-	if candidates, exists := tc.dependencies[targetType]; exists {
-		for _, candidate := range candidates {
-			*dest = candidate.(T)
-			return nil
-		}
+	if candidate, ok := tc.lookup(targetType); ok {
+		*dest = candidate.(T)
+		return nil
 	}
 
 	return ErrComponentNotFound
@@ -174,6 +198,12 @@ func (tb *TestBuilder) WithInterfaceDependency(interfaceType reflect.Type, dep i
 	return tb
 }
 
+// WithOverride forces a specific dependency to be returned for a type.
+func (tb *TestBuilder) WithOverride(typ reflect.Type, dep interface{}) *TestBuilder {
+	tb.tc.Override(typ, dep)
+	return tb
+}
+
 // WithNamedDependency adds a named dependency.
 func (tb *TestBuilder) WithNamedDependency(name string, dep interface{}) *TestBuilder {
 	tb.tc.RegisterNamed(name, dep)
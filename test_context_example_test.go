@@ -209,3 +209,50 @@ func TestMustAs_Panic(t *testing.T) {
 	var logger TestLogger
 	TestMustAs(ctx, nil, &logger) // Should panic
 }
+
+// Example 5: Overriding a single dependency on top of a shared base set
+func TestTestContext_Override(t *testing.T) {
+	loggerType := reflect.TypeOf((*TestLogger)(nil)).Elem()
+	baseLogger := &MockTestLogger{}
+	otherLogger := &MockTestLogger{}
+	overrideLogger := &MockTestLogger{}
+
+	testCtx := NewTestContext().
+		RegisterInterface(loggerType, baseLogger).
+		RegisterInterface(loggerType, otherLogger)
+
+	// Without an override the first registration wins
+	var logger TestLogger
+	if err := TestAs(testCtx.Context(), nil, &logger); err != nil {
+		t.Fatalf("Failed to discover logger: %v", err)
+	}
+	if logger != baseLogger {
+		t.Error("expected the first registered logger")
+	}
+
+	// The override takes precedence for TestAs and As
+	testCtx.Override(loggerType, overrideLogger)
+	ctx := testCtx.Context()
+
+	if err := TestAs(ctx, nil, &logger); err != nil {
+		t.Fatalf("Failed to discover logger: %v", err)
+	}
+	if logger != overrideLogger {
+		t.Error("TestAs should return the override")
+	}
+
+	var asLogger TestLogger
+	if !As(ctx, nil, nil, &asLogger) || asLogger != overrideLogger {
+		t.Error("As should return the override")
+	}
+
+	// Overrides work even for types with no registrations
+	dbType := reflect.TypeOf((*TestDB)(nil)).Elem()
+	mockDB := &MockTestDB{}
+	ctx = NewTestBuilder().WithOverride(dbType, mockDB).Context()
+
+	var db TestDB
+	if err := TestAs(ctx, nil, &db); err != nil || db != mockDB {
+		t.Errorf("expected overridden DB, got %v (err=%v)", db, err)
+	}
+}
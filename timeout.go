@@ -0,0 +1,45 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const fieldTimeoutKey contextKey = "autoinit:fieldTimeout"
+
+// timeout returns the field's timeout=D option, 0 if unset
+func (o tagOptions) timeout() (time.Duration, error) {
+	raw, ok := o.value("timeout")
+	if !ok {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid autoinit timeout %q: must be a positive duration such as 5s", raw)
+	}
+	return d, nil
+}
+
+// withFieldTimeout records the timeout declared by the tag of the field being
+// initialized. Unlike groups it is not inherited: every field sets its own value,
+// 0 meaning Options.PerComponentTimeout applies.
+func withFieldTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	if current, _ := ctx.Value(fieldTimeoutKey).(time.Duration); current == timeout {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldTimeoutKey, timeout)
+}
+
+// initContext derives the context passed to a component's Init, applying the
+// field's timeout tag or else Options.PerComponentTimeout
+func initContext(ctx context.Context, options *Options) (context.Context, context.CancelFunc) {
+	timeout, _ := ctx.Value(fieldTimeoutKey).(time.Duration)
+	if timeout == 0 && options != nil {
+		timeout = options.PerComponentTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
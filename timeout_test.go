@@ -0,0 +1,146 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// deadlineRecorder records the time left before its Init context expires
+type deadlineRecorder struct {
+	Remaining time.Duration
+	Child     *deadlineRecorder
+}
+
+func (d *deadlineRecorder) Init(ctx context.Context) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		d.Remaining = time.Until(deadline)
+	}
+	return nil
+}
+
+// blockingComponent waits for its context to be cancelled
+type blockingComponent struct{}
+
+func (b *blockingComponent) Init(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestPerComponentTimeout(t *testing.T) {
+	type App struct {
+		Default *deadlineRecorder
+		Slow    *deadlineRecorder `autoinit:"timeout=1h"`
+	}
+
+	logger := zerolog.Nop()
+	app := &App{
+		Default: &deadlineRecorder{},
+		// The tag applies to Slow only, not to its children
+		Slow: &deadlineRecorder{Child: &deadlineRecorder{}},
+	}
+	options := &Options{Logger: &logger, PerComponentTimeout: time.Minute}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if app.Default.Remaining <= 0 || app.Default.Remaining > time.Minute {
+		t.Errorf("Default remaining = %v; want the global timeout", app.Default.Remaining)
+	}
+	if app.Slow.Remaining <= time.Minute || app.Slow.Remaining > time.Hour {
+		t.Errorf("Slow remaining = %v; want the tag timeout", app.Slow.Remaining)
+	}
+	if app.Slow.Child.Remaining <= 0 || app.Slow.Child.Remaining > time.Minute {
+		t.Errorf("Slow.Child remaining = %v; want the global timeout", app.Slow.Child.Remaining)
+	}
+
+	// A component that honors ctx fails once its timeout expires
+	type Blocking struct {
+		Stuck *blockingComponent `autoinit:"timeout=10ms"`
+	}
+	err := WithOptions(context.Background(), &Blocking{Stuck: &blockingComponent{}}, &Options{Logger: &logger})
+	var initErr *InitError
+	if !errors.As(err, &initErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error for Stuck, got %v", err)
+	}
+
+	// Invalid durations name the field
+	type Bad struct {
+		C *deadlineRecorder `autoinit:"timeout=soon"`
+	}
+	err = WithOptions(context.Background(), &Bad{C: &deadlineRecorder{}}, &Options{Logger: &logger})
+	if err == nil || !strings.Contains(err.Error(), "Bad.C") || !strings.Contains(err.Error(), `"soon"`) {
+		t.Errorf("expected an error naming the field, got %v", err)
+	}
+}
+
+// sleepyComponent ignores its context and sleeps through Init
+type sleepyComponent struct {
+	Delay time.Duration
+	Done  bool
+}
+
+func (s *sleepyComponent) Init() error {
+	time.Sleep(s.Delay)
+	s.Done = true
+	return nil
+}
+
+func TestAutoInitContextDeadline(t *testing.T) {
+	type App struct {
+		First *sleepyComponent
+		Stuck *blockingComponent
+		Later *sleepyComponent
+	}
+
+	logger := zerolog.Nop()
+	options := &Options{Logger: &logger, ContinueOnError: true}
+
+	// A cooperative component fails once the deadline fires
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	app := &App{First: &sleepyComponent{}, Stuck: &blockingComponent{}, Later: &sleepyComponent{}}
+	err := WithOptions(ctx, app, options)
+	var interrupted *InterruptedError
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("expected an InterruptedError, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the error to wrap context.DeadlineExceeded: %v", err)
+	}
+	if got := pathToString(interrupted.InProgress); got != "Stuck" {
+		t.Errorf("InProgress = %s, want Stuck", got)
+	}
+	if got := pathToString(interrupted.LastCompleted); got != "First" {
+		t.Errorf("LastCompleted = %s, want First", got)
+	}
+	if app.Later.Done {
+		t.Error("traversal continued after the deadline")
+	}
+	if !strings.Contains(err.Error(), "interrupted in Stuck (last completed: First)") {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	// A component ignoring ctx is reported as in progress once it returns
+	type SlowApp struct {
+		Slow  *sleepyComponent
+		Later *sleepyComponent
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	slow := &SlowApp{Slow: &sleepyComponent{Delay: 30 * time.Millisecond}, Later: &sleepyComponent{}}
+	err = WithOptions(ctx, slow, &Options{Logger: &logger})
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("expected an InterruptedError, got %v", err)
+	}
+	if got := pathToString(interrupted.InProgress); got != "Slow" || interrupted.LastCompleted != nil {
+		t.Errorf("InProgress = %s, LastCompleted = %v; want Slow and none", got, interrupted.LastCompleted)
+	}
+	if !slow.Slow.Done || slow.Later.Done {
+		t.Errorf("expected only Slow to run: %+v %+v", slow.Slow, slow.Later)
+	}
+}
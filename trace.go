@@ -0,0 +1,142 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TraceEntry is one lifecycle call recorded in an InitTrace
+type TraceEntry struct {
+	Phase EventPhase `json:"phase"`           // Lifecycle step that ran
+	Path  string     `json:"path"`            // Dot-separated path to the component, "" for the root
+	Type  string     `json:"type"`            // Type the method was called on
+	Error string     `json:"error,omitempty"` // Error returned by the method, "" on success
+}
+
+// String formats the entry as "Init Database (*app.Database): ok"
+func (e TraceEntry) String() string {
+	result := "ok"
+	if e.Error != "" {
+		result = "error: " + e.Error
+	}
+	path := e.Path
+	if path == "" {
+		path = "<root>"
+	}
+	return fmt.Sprintf("%s %s (%s): %s", e.Phase, path, e.Type, result)
+}
+
+// InitTrace records the ordered lifecycle calls of a run. It is built on the
+// Event callback: pass its OnEvent method as Options.OnEvent. Entries leave
+// out durations and trace IDs, so two runs of an unchanged tree produce equal
+// traces, and the trace serializes to JSON for use as a golden file:
+//
+//	trace := &autoinit.InitTrace{}
+//	err := autoinit.WithOptions(ctx, app, &autoinit.Options{OnEvent: trace.OnEvent})
+//	data, _ := json.MarshalIndent(trace, "", "  ")
+//	os.WriteFile("testdata/init.golden.json", data, 0o644)
+//
+// ReplayTrace checks a later run against the recorded trace.
+type InitTrace struct {
+	Entries []TraceEntry `json:"entries"`
+
+	mu sync.Mutex
+}
+
+// OnEvent appends event to the trace. It is safe for concurrent use.
+func (t *InitTrace) OnEvent(event Event) {
+	entry := TraceEntry{
+		Phase: event.Phase,
+		Path:  strings.Join(event.Path, "."),
+		Type:  event.Type,
+	}
+	if event.Err != nil {
+		entry.Error = event.Err.Error()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Entries = append(t.Entries, entry)
+}
+
+// TraceMismatchError is returned by ReplayTrace when a run does not reproduce
+// the expected trace
+type TraceMismatchError struct {
+	Index int         // Position of the first differing entry
+	Want  *TraceEntry // Expected entry, nil if the run made extra calls
+	Got   *TraceEntry // Recorded entry, nil if the run stopped early
+}
+
+// Error describes the first difference
+func (e *TraceMismatchError) Error() string {
+	describe := func(entry *TraceEntry) string {
+		if entry == nil {
+			return "<none>"
+		}
+		return entry.String()
+	}
+	return fmt.Sprintf("init trace differs at entry %d: want %s, got %s", e.Index, describe(e.Want), describe(e.Got))
+}
+
+// ReplayTrace initializes target with WithOptions while recording a trace, and
+// returns a *TraceMismatchError if it differs from want, e.g. a golden trace
+// recorded before a refactor:
+//
+//	var golden autoinit.InitTrace
+//	data, _ := os.ReadFile("testdata/init.golden.json")
+//	json.Unmarshal(data, &golden)
+//	if err := autoinit.ReplayTrace(ctx, newApp(), nil, &golden); err != nil {
+//	    t.Fatal(err)
+//	}
+//
+// Errors returned by lifecycle methods are part of the trace, so a run that
+// fails the same way as the recorded one matches and ReplayTrace returns nil.
+// options is not modified; an OnEvent callback it sets is still called.
+func ReplayTrace(ctx context.Context, target interface{}, options *Options, want *InitTrace) error {
+	got := &InitTrace{}
+	replayOptions := &Options{}
+	if options != nil {
+		*replayOptions = *options
+	}
+	onEvent := replayOptions.OnEvent
+	replayOptions.OnEvent = func(event Event) {
+		got.OnEvent(event)
+		if onEvent != nil {
+			onEvent(event)
+		}
+	}
+
+	err := WithOptions(ctx, target, replayOptions)
+	if mismatch := compareTraces(want, got); mismatch != nil {
+		return mismatch
+	}
+	if len(got.Entries) == 0 && err != nil {
+		// Failures before any lifecycle call, e.g. an invalid target, leave no trace
+		return err
+	}
+	return nil
+}
+
+// compareTraces returns the first difference between want and got, nil if
+// they are equal
+func compareTraces(want, got *InitTrace) *TraceMismatchError {
+	var wantEntries []TraceEntry
+	if want != nil {
+		wantEntries = want.Entries
+	}
+	for i := 0; i < len(wantEntries) || i < len(got.Entries); i++ {
+		mismatch := &TraceMismatchError{Index: i}
+		if i < len(wantEntries) {
+			mismatch.Want = &wantEntries[i]
+		}
+		if i < len(got.Entries) {
+			mismatch.Got = &got.Entries[i]
+		}
+		if mismatch.Want == nil || mismatch.Got == nil || *mismatch.Want != *mismatch.Got {
+			return mismatch
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package autoinit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type traceDatabase struct {
+	DSN string
+}
+
+func (d *traceDatabase) Init() error { return nil }
+
+type traceCache struct {
+	Size int
+	fail bool
+}
+
+func (c *traceCache) Init() error {
+	if c.fail {
+		return errors.New("cache unavailable")
+	}
+	return nil
+}
+
+type traceApp struct {
+	Database *traceDatabase
+	Cache    *traceCache
+}
+
+func (a *traceApp) PostInit(ctx context.Context) error { return nil }
+
+func TestInitTraceReplay(t *testing.T) {
+	trace := &InitTrace{}
+	app := &traceApp{Database: &traceDatabase{}, Cache: &traceCache{}}
+	if err := WithOptions(context.Background(), app, &Options{OnEvent: trace.OnEvent}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []TraceEntry{
+		{Phase: PhaseInit, Path: "Database", Type: "*autoinit.traceDatabase"},
+		{Phase: PhaseInit, Path: "Cache", Type: "*autoinit.traceCache"},
+		{Phase: PhasePostInit, Path: "", Type: "*autoinit.traceApp"},
+	}
+	if len(trace.Entries) != len(want) {
+		t.Fatalf("trace = %v, want %v", trace.Entries, want)
+	}
+	for i := range want {
+		if trace.Entries[i] != want[i] {
+			t.Errorf("entry %d = %v, want %v", i, trace.Entries[i], want[i])
+		}
+	}
+
+	// The trace round-trips through JSON as a golden file
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var golden InitTrace
+	if err := json.Unmarshal(data, &golden); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	// An unchanged tree reproduces the trace, and a user OnEvent still runs
+	events := 0
+	options := &Options{OnEvent: func(Event) { events++ }}
+	fresh := &traceApp{Database: &traceDatabase{}, Cache: &traceCache{}}
+	if err := ReplayTrace(context.Background(), fresh, options, &golden); err != nil {
+		t.Errorf("unexpected replay error: %v", err)
+	}
+	if events != len(want) {
+		t.Errorf("user OnEvent called %d times, want %d", events, len(want))
+	}
+
+	// A changed behavior is reported at the first differing entry
+	failing := &traceApp{Database: &traceDatabase{}, Cache: &traceCache{fail: true}}
+	err = ReplayTrace(context.Background(), failing, nil, &golden)
+	var mismatch *TraceMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected TraceMismatchError, got %v", err)
+	}
+	if mismatch.Index != 1 || mismatch.Got.Error == "" {
+		t.Errorf("unexpected mismatch: %v", mismatch)
+	}
+
+	// A reordered tree is reported too
+	type reordered struct {
+		Cache    *traceCache
+		Database *traceDatabase
+	}
+	err = ReplayTrace(context.Background(), &reordered{Cache: &traceCache{}, Database: &traceDatabase{}}, nil, &golden)
+	if !errors.As(err, &mismatch) || mismatch.Index != 0 {
+		t.Errorf("expected a mismatch at entry 0, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TreeRetry configures Options.TreeRetry. Unlike Recoverable, which retries a
+// single component's Init, it restarts the entire AutoInit from scratch when
+// any component fails, for boot sequences whose components must come up
+// together, e.g. behind a flaky dependency.
+//
+// Every failed attempt, including the last, is rolled back: AutoShutdown runs
+// with the same options, shutting down only the components whose lifecycle
+// completed in that attempt, so resources they opened are released. The next
+// attempt then calls Init again on every component, including those that
+// succeeded before, so components must be idempotent across attempts: Init
+// must cope with state left by an earlier attempt and its Shutdown.
+//
+// Retries stop once the context ends. With Options.Report set, only the last
+// attempt is recorded in the report. Nested AutoInit calls made with a run's context
+// are not retried on their own.
+type TreeRetry struct {
+	// Attempts is the maximum number of runs, including the first; values
+	// below 2 disable retries
+	Attempts int
+	// Backoff is the wait between a rollback and the next attempt
+	Backoff time.Duration
+}
+
+// initTreeWithRetry runs AutoInit up to options.TreeRetry.Attempts times,
+// rolling back each failed attempt
+func initTreeWithRetry(ctx context.Context, target interface{}, options *Options) error {
+	logger := optionsLogger(options)
+
+	for attempt := 1; ; attempt++ {
+		// Each attempt records its own report, so rollback only shuts down what
+		// that attempt completed
+		attemptOptions := *options
+		attemptOptions.TreeRetry = TreeRetry{}
+		attemptOptions.Report = &InitReport{}
+
+		err := initTree(ctx, target, &attemptOptions)
+		if err == nil {
+			return finishTreeRetry(attempt, err, attemptOptions.Report, options)
+		}
+
+		logger.Warn().
+			Int("attempt", attempt).
+			Err(err).
+			Msg("AutoInit failed, rolling back")
+		if shutdownErr := AutoShutdown(ctx, target, &attemptOptions); shutdownErr != nil {
+			logger.Warn().
+				Int("attempt", attempt).
+				Err(shutdownErr).
+				Msg("Rollback of failed attempt failed")
+		}
+		if attempt == options.TreeRetry.Attempts || ctx.Err() != nil {
+			return finishTreeRetry(attempt, err, attemptOptions.Report, options)
+		}
+
+		select {
+		case <-time.After(options.TreeRetry.Backoff):
+		case <-ctx.Done():
+			return finishTreeRetry(attempt, err, nil, options)
+		}
+	}
+}
+
+// finishTreeRetry records the last attempt's report and returns its error
+func finishTreeRetry(attempts int, err error, report *InitReport, options *Options) error {
+	if report != nil && options.Report != nil {
+		options.Report.merge(report)
+	}
+	if err != nil {
+		return fmt.Errorf("after %d attempts: %w", attempts, err)
+	}
+	return nil
+}
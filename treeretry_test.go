@@ -0,0 +1,76 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// retriedComponent fails its first FailFor Init calls and counts its lifecycle calls
+type retriedComponent struct {
+	FailFor   int
+	Inits     int
+	Shutdowns int
+}
+
+func (r *retriedComponent) Init() error {
+	r.Inits++
+	if r.Inits <= r.FailFor {
+		return errors.New("transient failure")
+	}
+	return nil
+}
+
+func (r *retriedComponent) Shutdown(ctx context.Context) error {
+	r.Shutdowns++
+	return nil
+}
+
+func TestTreeRetry(t *testing.T) {
+	type App struct {
+		First *retriedComponent
+		Flaky *retriedComponent
+	}
+
+	logger := zerolog.Nop()
+	report := &InitReport{}
+	options := &Options{
+		Logger:    &logger,
+		Report:    report,
+		TreeRetry: TreeRetry{Attempts: 3, Backoff: time.Millisecond},
+	}
+	app := &App{First: &retriedComponent{}, Flaky: &retriedComponent{FailFor: 1}}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The failed attempt was rolled back before the whole tree was initialized again
+	if app.First.Inits != 2 || app.First.Shutdowns != 1 {
+		t.Errorf("First: %+v, want 2 inits and 1 rollback shutdown", app.First)
+	}
+	if app.Flaky.Inits != 2 || app.Flaky.Shutdowns != 0 {
+		t.Errorf("Flaky: %+v, want 2 inits and no shutdown", app.Flaky)
+	}
+	if len(report.Initialized) != 2 {
+		t.Errorf("report should describe the last attempt only, got %v", report.Initialized)
+	}
+
+	// Exhausted attempts return the last error, once the last attempt was
+	// rolled back too
+	app = &App{First: &retriedComponent{}, Flaky: &retriedComponent{FailFor: 5}}
+	options.TreeRetry.Attempts = 2
+	err := WithOptions(context.Background(), app, options)
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") || !strings.Contains(err.Error(), "transient failure") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if app.Flaky.Inits != 2 || app.First.Shutdowns != 2 {
+		t.Errorf("expected 2 attempts with two rollbacks, got First %+v Flaky %+v", app.First, app.Flaky)
+	}
+	if app.First.Inits != app.First.Shutdowns {
+		t.Errorf("First was left initialized: %+v", app.First)
+	}
+}
@@ -0,0 +1,66 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/rs/zerolog"
+)
+
+// TypeHook holds the functions Options.TypeHooks runs around the lifecycle of
+// each matching component. component is a pointer to the component's struct
+// when it is addressable. Either function may be nil.
+type TypeHook struct {
+	Before func(ctx context.Context, component interface{}) error // Runs before PreInit
+	After  func(ctx context.Context, component interface{}) error // Runs after PostInit
+}
+
+// typeHooksFor returns the hooks whose key matches component, ordered by the
+// key's type name so that overlapping keys run deterministically
+func typeHooksFor(options *Options, component reflect.Value) []TypeHook {
+	if options == nil || len(options.TypeHooks) == 0 {
+		return nil
+	}
+	var keys []reflect.Type
+	for key := range options.TypeHooks {
+		if TypeMatches(component, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	hooks := make([]TypeHook, len(keys))
+	for i, key := range keys {
+		hooks[i] = options.TypeHooks[key]
+	}
+	return hooks
+}
+
+// callTypeHooks runs the Before or After functions, depending on phase, of the
+// type hooks matching struct v
+func callTypeHooks(ctx context.Context, v reflect.Value, path []string, index int, logger *zerolog.Logger, options *Options, phase EventPhase) error {
+	ptr := v
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		ptr = v.Addr()
+	}
+	for _, hook := range typeHooksFor(options, ptr) {
+		fn := hook.After
+		if phase == PhaseBeforeTypeHook {
+			fn = hook.Before
+		}
+		if fn == nil {
+			continue
+		}
+		if err := callInitHook(ctx, v, path, index, logger, options, phase, func(ptr reflect.Value) (bool, error) {
+			hookCtx, unlock := lockCallbacks(ctx)
+			defer unlock()
+			return true, fn(hookCtx, ptr.Interface())
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
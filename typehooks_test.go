@@ -0,0 +1,95 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+type hookNamer interface {
+	HookName() string
+}
+
+// hookedHandler records its own lifecycle calls in a shared log
+type hookedHandler struct {
+	Name string
+	Log  *[]string
+}
+
+func (h *hookedHandler) HookName() string { return h.Name }
+
+func (h *hookedHandler) PreInit(ctx context.Context) error {
+	*h.Log = append(*h.Log, "PreInit "+h.Name)
+	return nil
+}
+
+func (h *hookedHandler) Init() error {
+	*h.Log = append(*h.Log, "Init "+h.Name)
+	return nil
+}
+
+func (h *hookedHandler) PostInit(ctx context.Context) error {
+	*h.Log = append(*h.Log, "PostInit "+h.Name)
+	return nil
+}
+
+func TestTypeHooks(t *testing.T) {
+	var log []string
+	record := func(label string) func(context.Context, interface{}) error {
+		return func(ctx context.Context, component interface{}) error {
+			log = append(log, fmt.Sprintf("%s %s", label, component.(hookNamer).HookName()))
+			return nil
+		}
+	}
+
+	type App struct {
+		Handlers []*hookedHandler
+		Other    *SimpleComponent
+	}
+	app := &App{
+		Handlers: []*hookedHandler{{Name: "a", Log: &log}, {Name: "b", Log: &log}},
+		Other:    &SimpleComponent{},
+	}
+
+	logger := zerolog.Nop()
+	options := &Options{
+		Logger: &logger,
+		TypeHooks: map[reflect.Type]TypeHook{
+			// Interface keys match implementers; "autoinit.hookNamer" sorts after
+			// "*autoinit.hookedHandler" so the concrete hook runs first
+			reflect.TypeOf((*hookNamer)(nil)).Elem(): {Before: record("register"), After: record("registered")},
+			reflect.TypeOf(&hookedHandler{}):         {After: record("concrete")},
+		},
+	}
+	if err := WithOptions(context.Background(), app, options); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"register a", "PreInit a", "Init a", "PostInit a", "concrete a", "registered a",
+		"register b", "PreInit b", "Init b", "PostInit b", "concrete b", "registered b",
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("calls = %v; want %v", log, expected)
+	}
+
+	// A failing Before hook fails the component before its lifecycle starts
+	log = nil
+	options.TypeHooks = map[reflect.Type]TypeHook{
+		reflect.TypeOf(hookedHandler{}): {Before: func(ctx context.Context, component interface{}) error {
+			return errors.New("rejected")
+		}},
+	}
+	err := WithOptions(context.Background(), &App{Handlers: []*hookedHandler{{Name: "a", Log: &log}}}, options)
+	var initErr *InitError
+	if !errors.As(err, &initErr) || pathToString(initErr.Path) != "Handlers.[0]" {
+		t.Errorf("expected an error for Handlers.[0], got %v", err)
+	}
+	if len(log) != 0 {
+		t.Errorf("lifecycle should not run after a failed Before hook, got %v", log)
+	}
+}
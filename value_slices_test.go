@@ -0,0 +1,76 @@
+package autoinit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// valueElement has a pointer-receiver Init, so it only runs on an element
+// that can be addressed in place
+type valueElement struct {
+	Name  string
+	Ready bool
+}
+
+func (e *valueElement) Init() error {
+	e.Ready = true
+	return nil
+}
+
+type valueElementList struct {
+	Elements []valueElement
+}
+
+func TestValueSliceElements(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("slice in a struct reached by value", func(t *testing.T) {
+		type App struct {
+			List valueElementList
+		}
+		// The root is passed by value, so List is a copy, but it shares the
+		// slice's backing array with the caller's
+		app := App{List: valueElementList{Elements: []valueElement{{Name: "a"}, {Name: "b"}}}}
+		if err := AutoInit(ctx, app); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, e := range app.List.Elements {
+			if !e.Ready {
+				t.Errorf("expected %s to be initialized in place", e.Name)
+			}
+		}
+	})
+
+	t.Run("slice in a map value", func(t *testing.T) {
+		type App struct {
+			Lists map[string]valueElementList
+		}
+		app := &App{Lists: map[string]valueElementList{"x": {Elements: []valueElement{{Name: "a"}}}}}
+		if err := AutoInit(ctx, app); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !app.Lists["x"].Elements[0].Ready {
+			t.Errorf("expected the element to be initialized in place")
+		}
+	})
+
+	t.Run("array in a struct reached by value", func(t *testing.T) {
+		// Unlike a slice, an array is copied with its struct, so its
+		// elements cannot be initialized where the caller sees them
+		type App struct {
+			Elements [1]valueElement
+		}
+		err := AutoInit(ctx, App{})
+		if !errors.Is(err, ErrNotAddressable) {
+			t.Fatalf("expected ErrNotAddressable, got %v", err)
+		}
+		app := &App{}
+		if err := AutoInit(ctx, app); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !app.Elements[0].Ready {
+			t.Errorf("expected the element of an addressable array to be initialized")
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package autoinit
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// componentNode is a struct value reached during a tree walk
+type componentNode struct {
+	value  reflect.Value // The struct value (addressable when reachable by pointer)
+	parent reflect.Value // The enclosing struct, invalid for the root
+	path   []string      // Path from the root
+	index  int           // Declaration index of the field in its parent struct (-1 for the root)
+	tag    tagOptions    // Parsed autoinit tag of the field the node was reached through
+	group  string        // Init group the node belongs to, "" if none (see InitGroup)
+	copy   *mapCopy      // Innermost map copy the node was reached in, nil if none
+}
+
+// treeWalker visits every struct in a tree using the same traversal rules as
+// AutoInit: unexported, "-"-tagged and (with RequireTags) untagged fields are
+// skipped, nil pointers are ignored, AllowDescend is honored and pointers are
+// visited once unless cycle detection is disabled.
+//
+// Nodes are visited children-first, i.e. in the order AutoInit calls Init.
+// Struct values held in maps are visited as addressable copies; changes made
+// to them are not written back.
+type treeWalker struct {
+	options *Options
+	visited map[uintptr]bool
+	visit   func(node componentNode) error
+
+	// With reinit, components reached through a reinit-tagged field are
+	// visited again, as AutoInit initializes them again; ancestors holds the
+	// pointers to the enclosing structs, which are never revisited
+	reinit    bool
+	ancestors []reflect.Value
+
+	// copy is the innermost map copy being walked
+	copy *mapCopy
+}
+
+// walkTree walks the tree rooted at the struct value root
+func walkTree(root reflect.Value, options *Options, visit func(node componentNode) error) error {
+	w := &treeWalker{
+		options: options,
+		visit:   visit,
+	}
+	if options == nil || !options.DisableCycleDetection {
+		w.visited = make(map[uintptr]bool)
+	}
+	return w.walk(root, reflect.Value{}, []string{}, -1, tagOptions{}, "")
+}
+
+// walkInitCalls is walkTree visiting components once per Init call AutoInit
+// makes: a component reached through several reinit-tagged fields is visited
+// once per reference
+func walkInitCalls(root reflect.Value, options *Options, visit func(node componentNode) error) error {
+	w := &treeWalker{
+		options: options,
+		visit:   visit,
+		reinit:  true,
+	}
+	if options == nil || !options.DisableCycleDetection {
+		w.visited = make(map[uintptr]bool)
+	}
+	return w.walk(root, reflect.Value{}, []string{}, -1, tagOptions{}, "")
+}
+
+func (w *treeWalker) walk(v, parent reflect.Value, path []string, index int, tag tagOptions, group string) error {
+	// Like AutoInit, follow a pointer to an interface to the value it holds
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		if w.visited != nil {
+			ptr := v.Pointer()
+			if w.visited[ptr] {
+				return nil
+			}
+			w.visited[ptr] = true
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if w.reinit && v.CanAddr() {
+		w.ancestors = append(w.ancestors, v.Addr())
+		defer func() { w.ancestors = w.ancestors[:len(w.ancestors)-1] }()
+	}
+
+	if allowDescend(w.options, v.Type()) {
+		if err := w.walkFields(v, path, group); err != nil {
+			return err
+		}
+	}
+
+	return w.visit(componentNode{
+		value:  v,
+		parent: parent,
+		path:   path,
+		index:  index,
+		tag:    tag,
+		group:  group,
+		copy:   w.copy,
+	})
+}
+
+func (w *treeWalker) walkFields(v reflect.Value, path []string, group string) error {
+	t := v.Type()
+	order, err := fieldOrder(t, w.options)
+	if err != nil {
+		return err
+	}
+	for _, i := range order {
+		field := v.Field(i)
+		fieldType := t.Field(i)
+
+		tag := parseTag(fieldType)
+		if fieldSkipReason(field, tag, w.options) != 0 {
+			continue
+		}
+		field, _, _ = unwrapRecoverable(field)
+		if w.reinit && tag.has("reinit") {
+			w.allowReinit(field)
+		}
+
+		fieldPath := appendPath(path, fieldType.Name)
+		fieldGroup := group
+		if g, ok := tag.value("group"); ok {
+			fieldGroup = g
+		}
+
+		if iterable, ok := iterableOf(field); ok {
+			for _, entry := range iterableEntries(iterable) {
+				if entry.value.Kind() != reflect.Ptr {
+					continue
+				}
+				elemPath := appendPath(fieldPath, fmt.Sprintf("[%v]", entry.key))
+				if err := w.walk(entry.value, v, elemPath, i, tag, fieldGroup); err != nil {
+					return err
+				}
+			}
+			if err := w.walk(field, v, fieldPath, i, tag, fieldGroup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.Struct, reflect.Ptr:
+			if err := w.walk(field, v, fieldPath, i, tag, fieldGroup); err != nil {
+				return err
+			}
+
+		case reflect.Interface:
+			// Like AutoInit, only descend into embedded interfaces
+			if fieldType.Anonymous {
+				if err := w.walk(field, v, fieldPath, i, tag, fieldGroup); err != nil {
+					return err
+				}
+			}
+
+		case reflect.Slice, reflect.Array:
+			for j := 0; j < field.Len(); j++ {
+				elemPath := appendPath(fieldPath, fmt.Sprintf("[%d]", j))
+				if err := w.walk(field.Index(j), v, elemPath, i, tag, fieldGroup); err != nil {
+					return err
+				}
+			}
+
+		case reflect.Map:
+			if !enterMap(w.visited, field) {
+				continue
+			}
+			for _, key := range sortedMapKeys(field) {
+				elem := field.MapIndex(key)
+				elemPath := appendPath(fieldPath, fmt.Sprintf("[%v]", key))
+				if elem.Kind() == reflect.Interface && !elem.IsNil() {
+					elem = elem.Elem()
+				}
+				enclosing := w.copy
+				if elem.Kind() == reflect.Struct {
+					copied := reflect.New(elem.Type())
+					copied.Elem().Set(elem)
+					elem = copied
+					w.copy = newMapCopy(enclosing, field, key, copied.Elem())
+				}
+				err := w.walk(elem, v, elemPath, i, tag, fieldGroup)
+				w.copy = enclosing
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// allowReinit lets the component held by a reinit-tagged field be visited
+// again, unless it encloses the field, as allowReinit does for AutoInit
+func (w *treeWalker) allowReinit(field reflect.Value) {
+	if w.visited == nil {
+		return
+	}
+	if field.Kind() == reflect.Interface && !field.IsNil() {
+		field = field.Elem()
+	}
+	if field.Kind() != reflect.Ptr || field.IsNil() {
+		return
+	}
+	for _, ancestor := range w.ancestors {
+		if ancestor.Type() == field.Type() && ancestor.Pointer() == field.Pointer() {
+			return
+		}
+	}
+	delete(w.visited, field.Pointer())
+}
@@ -0,0 +1,143 @@
+package autoinit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WarningCode classifies a Warning
+type WarningCode string
+
+const (
+	// WarningTargetByValue means the target was passed by value, so changes
+	// to its value fields are not visible to the caller
+	WarningTargetByValue WarningCode = "target-by-value"
+	// WarningCopiedReceiver means a value-receiver initializer was called on
+	// a copy of a non-addressable component, so its changes do not persist
+	WarningCopiedReceiver WarningCode = "copied-receiver"
+	// WarningDuplicateInit means Options.DetectDuplicateInit found a component
+	// whose Init is reachable more than once
+	WarningDuplicateInit WarningCode = "duplicate-init"
+	// WarningAmbiguousMatch means As found several sibling fields matching
+	// and returned the first one
+	WarningAmbiguousMatch WarningCode = "ambiguous-match"
+	// WarningSearchTruncated means a finder search stopped at its maximum
+	// depth (see DefaultMaxSearchDepth) before reaching the root
+	WarningSearchTruncated WarningCode = "search-truncated"
+	// WarningMissingTag means a field was skipped because Options.RequireTags
+	// is set and it has no autoinit tag
+	WarningMissingTag WarningCode = "missing-tag"
+	// WarningUnknownTagOption means a field's autoinit tag has an option
+	// AutoInit does not know, which is ignored
+	WarningUnknownTagOption WarningCode = "unknown-tag-option"
+	// WarningMutualRequirement means Options.CheckRequirements found two
+	// components declaring each other as requirements, so one initializes
+	// before the other it depends on
+	WarningMutualRequirement WarningCode = "mutual-requirement"
+)
+
+// Warning is a non-fatal issue found during a run
+type Warning struct {
+	Code    WarningCode
+	Path    []string // Path of the component or field concerned, empty for the root
+	Message string
+}
+
+// PathString returns the warning's path in dot-separated form
+func (w Warning) PathString() string {
+	return pathToString(w.Path)
+}
+
+// String describes the warning on one line, e.g.
+// "Cache: unknown autoinit tag option \"eager\" is ignored (unknown-tag-option)"
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s (%s)", w.PathString(), w.Message, w.Code)
+}
+
+// recordWarning records a warning in the report of the run on ctx, if any.
+// A nil path stands for the component whose Init is in progress.
+func recordWarning(ctx context.Context, code WarningCode, path []string, message string) {
+	sink := reportSinkOf(ctx)
+	if sink == nil {
+		return
+	}
+	if path == nil {
+		path = sink.path
+	}
+	sink.report.recordWarning(Warning{
+		Code:    code,
+		Path:    append([]string{}, path...),
+		Message: message,
+	})
+}
+
+// recordWarning adds w to the report
+func (r *InitReport) recordWarning(w Warning) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Warnings = append(r.Warnings, w)
+}
+
+// WarningsWithCode returns the warnings recorded with code, in the order they
+// were raised
+func (r *InitReport) WarningsWithCode(code WarningCode) []Warning {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var warnings []Warning
+	for _, w := range r.Warnings {
+		if w.Code == code {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// ambiguousFields returns the names of the fields of parent that the first
+// pass of searchInStruct would match, if there are several
+func ambiguousFields(parent, exclude interface{}, targetType reflect.Type, mode asMode, filters []Filter) []string {
+	v := reflect.ValueOf(parent)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		fieldType := t.Field(i)
+		if !field.CanInterface() || sameComponent(field.Interface(), exclude) ||
+			(field.Kind() == reflect.Ptr && field.IsNil()) {
+			continue
+		}
+		if _, ok := mode.match(field, targetType); ok && matchesAllFilters(field, &fieldType, filters) {
+			names = append(names, fieldType.Name)
+		}
+	}
+	if len(names) < 2 {
+		return nil
+	}
+	return names
+}
+
+// recordAmbiguousMatch warns if As resolved targetType among several matching
+// fields of parent
+func recordAmbiguousMatch(ctx context.Context, parent, self interface{}, targetType reflect.Type, mode asMode, filters []Filter) {
+	if reportSinkOf(ctx) == nil {
+		return
+	}
+	names := ambiguousFields(parent, self, targetType, mode, filters)
+	if names == nil {
+		return
+	}
+	message := fmt.Sprintf("As found %d matches for %s (fields %s) and returned %s", len(names), targetType, strings.Join(names, ", "), names[0])
+	runLogger(ctx).Warn().
+		Str("target", targetType.String()).
+		Strs("fields", names).
+		Msg("As: " + message)
+	recordWarning(ctx, WarningAmbiguousMatch, nil, message)
+}
@@ -0,0 +1,116 @@
+package autoinit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type warnStore struct {
+	Name string
+}
+
+// warnConsumer resolves a *warnStore with As, which its parent holds twice
+type warnConsumer struct {
+	Store *warnStore
+}
+
+func (c *warnConsumer) Init(ctx context.Context, parent interface{}) error {
+	As(ctx, c, parent, &c.Store)
+	return nil
+}
+
+type warnApp struct {
+	Primary  *warnStore
+	Replica  *warnStore
+	Consumer warnConsumer `autoinit:"eager"`
+}
+
+func TestReportWarnings(t *testing.T) {
+	t.Run("ambiguous As match", func(t *testing.T) {
+		app := &warnApp{Primary: &warnStore{Name: "primary"}, Replica: &warnStore{Name: "replica"}}
+		report := &InitReport{}
+		if err := WithOptions(context.Background(), app, &Options{Report: report}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if app.Consumer.Store != app.Primary {
+			t.Fatalf("expected As to return the first match")
+		}
+		if len(report.Warnings) != 2 {
+			t.Fatalf("expected an unknown tag option and an ambiguous match, got %v", report.Warnings)
+		}
+
+		unknown := report.Warnings[0]
+		if unknown.Code != WarningUnknownTagOption || unknown.PathString() != "Consumer" || !strings.Contains(unknown.Message, `"eager"`) {
+			t.Errorf("unexpected warning: %v", unknown)
+		}
+		ambiguous := report.Warnings[1]
+		if ambiguous.Code != WarningAmbiguousMatch || ambiguous.PathString() != "Consumer" {
+			t.Errorf("expected the ambiguity to be reported for Consumer, got %v", ambiguous)
+		}
+		if !strings.Contains(ambiguous.Message, "Primary, Replica") {
+			t.Errorf("expected the message to name the matching fields, got %q", ambiguous.Message)
+		}
+	})
+
+	t.Run("copied receiver and target by value", func(t *testing.T) {
+		count := 0
+		report := &InitReport{}
+		root := valueReceiverRoot{Component: valueReceiverInit{Count: &count}}
+		if err := WithOptions(context.Background(), root, &Options{Report: report}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := report.WarningsWithCode(WarningTargetByValue); len(got) != 1 || got[0].PathString() != "<root>" {
+			t.Errorf("expected a target-by-value warning for the root, got %v", got)
+		}
+		if got := report.WarningsWithCode(WarningCopiedReceiver); len(got) != 1 || got[0].PathString() != "Component" {
+			t.Errorf("expected a copied-receiver warning for Component, got %v", got)
+		}
+	})
+
+	t.Run("missing tag", func(t *testing.T) {
+		type App struct {
+			Tagged   *SimpleComponent `autoinit:""`
+			Untagged *SimpleComponent
+		}
+		report := &InitReport{}
+		app := &App{Tagged: &SimpleComponent{}, Untagged: &SimpleComponent{}}
+		if err := WithOptions(context.Background(), app, &Options{Report: report, RequireTags: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := report.WarningsWithCode(WarningMissingTag)
+		if len(got) != 1 || got[0].PathString() != "Untagged" {
+			t.Errorf("expected a missing-tag warning for Untagged, got %v", got)
+		}
+	})
+
+	t.Run("truncated finder search", func(t *testing.T) {
+		root, leaf := newDepthTree(8, SearchOption{ByType: reflect.TypeOf(depthConfig{}), MaxDepth: 2})
+		report := &InitReport{}
+		if err := WithOptions(context.Background(), root, &Options{Report: report}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := report.WarningsWithCode(WarningSearchTruncated)
+		if len(got) != 1 {
+			t.Fatalf("expected one truncated search, got %v", report.Warnings)
+		}
+		if want := "Tree" + strings.Repeat(".Next", 7); got[0].PathString() != want {
+			t.Errorf("expected the warning on the searching leaf %s, got %s", want, got[0].PathString())
+		}
+		if leaf.Found != nil {
+			t.Errorf("expected the search to be truncated")
+		}
+	})
+
+	t.Run("clean run", func(t *testing.T) {
+		report := &InitReport{}
+		app := &warnApp{Primary: &warnStore{Name: "primary"}}
+		if err := WithOptions(context.Background(), app, &Options{Report: report}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := report.WarningsWithCode(WarningAmbiguousMatch); len(got) != 0 {
+			t.Errorf("expected no ambiguity with a single store, got %v", got)
+		}
+	})
+}